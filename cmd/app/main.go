@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"errors"
+	"math"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/IvanMeln1k/go-bank-app-bank/internal/broker"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
 	"github.com/IvanMeln1k/go-bank-app-bank/internal/handler"
 	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
 	"github.com/IvanMeln1k/go-bank-app-bank/internal/server"
@@ -19,6 +21,7 @@ import (
 	"github.com/IvanMeln1k/go-bank-app-bank/pkg/redisdb"
 	"github.com/IvanMeln1k/go-bank-app-bank/pkg/tokens"
 	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -82,13 +85,178 @@ func main() {
 	if err != nil {
 		logrus.Fatalf("invalid emailTTL: %s", err)
 	}
+	machineTTL, err := time.ParseDuration(viper.GetString("tokens.machineTTL"))
+	if err != nil {
+		logrus.Fatalf("invalid machineTTL: %s", err)
+	}
+	keyRotationPeriod, err := time.ParseDuration(viper.GetString("tokens.keyRotationPeriod"))
+	if err != nil {
+		logrus.Fatalf("invalid keyRotationPeriod: %s", err)
+	}
+	signingKeys, err := tokens.NewKeySet(viper.GetString("tokens.keysDir"))
+	if err != nil {
+		logrus.Fatalf("error loading signing keys: %s", err)
+	}
 	tokenManager := tokens.NewTokenManager(tokens.Config{
-		SecretKey: os.Getenv("SECRET_KEY"),
-		AccessTTL: accessTTL,
-		EmailTTL:  emailTTL,
+		Keys:       signingKeys,
+		SecretKey:  os.Getenv("SECRET_KEY"),
+		AllowHS256: viper.GetBool("tokens.allowHS256"),
+		AccessTTL:  accessTTL,
+		EmailTTL:   emailTTL,
+		MachineTTL: machineTTL,
 	})
 
-	hasher := hasher.NewHasher(os.Getenv("SALT"))
+	// retired keys must verify tokens until the longest-living of them expires
+	keysRetention := accessTTL
+	for _, ttl := range []time.Duration{emailTTL, machineTTL} {
+		if ttl > keysRetention {
+			keysRetention = ttl
+		}
+	}
+	rotationCtx, stopRotation := context.WithCancel(context.Background())
+	go signingKeys.RunRotation(rotationCtx, keyRotationPeriod, keysRetention)
+
+	hasherThreads := viper.GetUint("hasher.threads")
+	if hasherThreads > math.MaxUint8 {
+		logrus.Fatalf("invalid hasher.threads: %d", hasherThreads)
+	}
+	hasher, err := hasher.NewArgon2Hasher(hasher.Argon2Config{
+		Time:    viper.GetUint32("hasher.time"),
+		Memory:  viper.GetUint32("hasher.memory"),
+		Threads: uint8(hasherThreads),
+		KeyLen:  viper.GetUint32("hasher.keyLen"),
+		SaltLen: viper.GetUint32("hasher.saltLen"),
+	}, hasher.NewHasher(os.Getenv("SALT")))
+	if err != nil {
+		logrus.Fatalf("invalid hasher config: %s", err)
+	}
+
+	passwordResetTTL, err := time.ParseDuration(viper.GetString("passwordReset.ttl"))
+	if err != nil {
+		logrus.Fatalf("invalid passwordReset.ttl: %s", err)
+	}
+	passwordResetWindow, err := time.ParseDuration(viper.GetString("passwordReset.window"))
+	if err != nil {
+		logrus.Fatalf("invalid passwordReset.window: %s", err)
+	}
+
+	twoFactorChallengeTTL, err := time.ParseDuration(viper.GetString("twoFactor.challengeTTL"))
+	if err != nil {
+		logrus.Fatalf("invalid twoFactor.challengeTTL: %s", err)
+	}
+
+	magicLinkTTL, err := time.ParseDuration(viper.GetString("magicLink.ttl"))
+	if err != nil {
+		logrus.Fatalf("invalid magicLink.ttl: %s", err)
+	}
+	magicLinkWindow, err := time.ParseDuration(viper.GetString("magicLink.window"))
+	if err != nil {
+		logrus.Fatalf("invalid magicLink.window: %s", err)
+	}
+
+	signInWindow, err := time.ParseDuration(viper.GetString("signIn.window"))
+	if err != nil {
+		logrus.Fatalf("invalid signIn.window: %s", err)
+	}
+	signInBaseLockout, err := time.ParseDuration(viper.GetString("signIn.baseLockout"))
+	if err != nil {
+		logrus.Fatalf("invalid signIn.baseLockout: %s", err)
+	}
+	signInMaxLockout, err := time.ParseDuration(viper.GetString("signIn.maxLockout"))
+	if err != nil {
+		logrus.Fatalf("invalid signIn.maxLockout: %s", err)
+	}
+
+	exportTTL, err := time.ParseDuration(viper.GetString("privacy.exportTTL"))
+	if err != nil {
+		logrus.Fatalf("invalid privacy.exportTTL: %s", err)
+	}
+	exportWindow, err := time.ParseDuration(viper.GetString("privacy.exportWindow"))
+	if err != nil {
+		logrus.Fatalf("invalid privacy.exportWindow: %s", err)
+	}
+
+	stepUpOperationTTL, err := time.ParseDuration(viper.GetString("stepUp.operationTTL"))
+	if err != nil {
+		logrus.Fatalf("invalid stepUp.operationTTL: %s", err)
+	}
+	stepUpUserWindow, err := time.ParseDuration(viper.GetString("stepUp.userWindow"))
+	if err != nil {
+		logrus.Fatalf("invalid stepUp.userWindow: %s", err)
+	}
+	stepUpThresholds := make(map[string]int, len(domain.Tiers))
+	for _, tier := range domain.Tiers {
+		stepUpThresholds[tier] = viper.GetInt("stepUp.thresholds." + tier)
+	}
+
+	machinesOfflineAfter, err := time.ParseDuration(viper.GetString("machines.offlineAfter"))
+	if err != nil {
+		logrus.Fatalf("invalid machines.offlineAfter: %s", err)
+	}
+	machinesHealthCheckInterval, err := time.ParseDuration(viper.GetString("machines.healthCheckInterval"))
+	if err != nil {
+		logrus.Fatalf("invalid machines.healthCheckInterval: %s", err)
+	}
+	// intervals drive tickers, which panic on non-positive period
+	if machinesHealthCheckInterval <= 0 {
+		logrus.Fatalf("invalid machines.healthCheckInterval: %s", machinesHealthCheckInterval)
+	}
+
+	machinesSessionIdleTimeout, err := time.ParseDuration(viper.GetString("machines.sessionIdleTimeout"))
+	if err != nil {
+		logrus.Fatalf("invalid machines.sessionIdleTimeout: %s", err)
+	}
+	// idle sessions are swept every half of the timeout
+	if machinesSessionIdleTimeout/2 <= 0 {
+		logrus.Fatalf("invalid machines.sessionIdleTimeout: %s", machinesSessionIdleTimeout)
+	}
+
+	cashoutCodesTTL, err := time.ParseDuration(viper.GetString("cashoutCodes.ttl"))
+	if err != nil {
+		logrus.Fatalf("invalid cashoutCodes.ttl: %s", err)
+	}
+	cashoutCodesMachineWindow, err := time.ParseDuration(viper.GetString("cashoutCodes.machineWindow"))
+	if err != nil {
+		logrus.Fatalf("invalid cashoutCodes.machineWindow: %s", err)
+	}
+	cashoutCodesSweepInterval, err := time.ParseDuration(viper.GetString("cashoutCodes.sweepInterval"))
+	if err != nil {
+		logrus.Fatalf("invalid cashoutCodes.sweepInterval: %s", err)
+	}
+	if cashoutCodesSweepInterval <= 0 {
+		logrus.Fatalf("invalid cashoutCodes.sweepInterval: %s", cashoutCodesSweepInterval)
+	}
+	thirdPartyDepositsLookupWindow, err := time.ParseDuration(viper.GetString("thirdPartyDeposits.lookupWindow"))
+	if err != nil {
+		logrus.Fatalf("invalid thirdPartyDeposits.lookupWindow: %s", err)
+	}
+
+	cardsBin := viper.GetString("cards.bin")
+	if !domain.ValidateBin(cardsBin) {
+		logrus.Fatalf("invalid cards.bin: %q", cardsBin)
+	}
+	panKey := os.Getenv("PAN_KEY")
+	if !domain.ValidatePanKey(panKey) {
+		logrus.Fatalf("PAN_KEY must be set and at least %d bytes long", domain.PanKeyMinLength)
+	}
+	cashoutCodeKey := os.Getenv("CASHOUT_CODE_KEY")
+	if !domain.ValidateCashoutCodeKey(cashoutCodeKey) {
+		logrus.Fatalf("CASHOUT_CODE_KEY must be set and at least %d bytes long",
+			domain.CashoutCodeKeyMinLength)
+	}
+
+	passkeyCeremonyTTL, err := time.ParseDuration(viper.GetString("passkeys.ceremonyTTL"))
+	if err != nil {
+		logrus.Fatalf("invalid passkeys.ceremonyTTL: %s", err)
+	}
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          viper.GetString("passkeys.rpId"),
+		RPDisplayName: viper.GetString("passkeys.rpDisplayName"),
+		RPOrigins:     viper.GetStringSlice("passkeys.rpOrigins"),
+	})
+	if err != nil {
+		logrus.Fatalf("invalid passkeys config: %s", err)
+	}
 
 	broker := broker.NewBroker(broker.Deps{
 		RDB: rdb,
@@ -101,8 +269,77 @@ func main() {
 		Hasher:             hasher,
 		TransactionManager: transactionManager,
 		Broker:             broker,
+		WebAuthn:           webAuthn,
+		AuthConfig: service.AuthConfig{
+			EmailChangeTTL:        emailTTL,
+			PasswordResetTTL:      passwordResetTTL,
+			PasswordResetLimit:    viper.GetInt("passwordReset.limit"),
+			PasswordResetWindow:   passwordResetWindow,
+			TwoFactorIssuer:       viper.GetString("twoFactor.issuer"),
+			TwoFactorChallengeTTL: twoFactorChallengeTTL,
+			RecoveryCodesCount:    viper.GetInt("twoFactor.recoveryCodes"),
+			SignInGuard: service.SignInGuardConfig{
+				AccountAttempts:   viper.GetInt("signIn.accountAttempts"),
+				IpAttempts:        viper.GetInt("signIn.ipAttempts"),
+				TwoFactorAttempts: viper.GetInt("signIn.twoFactorAttempts"),
+				Window:            signInWindow,
+				BaseLockout:       signInBaseLockout,
+				MaxLockout:        signInMaxLockout,
+			},
+			MagicLinkTTL:       magicLinkTTL,
+			MagicLinkLimit:     viper.GetInt("magicLink.limit"),
+			MagicLinkWindow:    magicLinkWindow,
+			PasskeyCeremonyTTL: passkeyCeremonyTTL,
+			SessionTTL:         accessTTL,
+		},
+		PrivacyConfig: service.PrivacyConfig{
+			ExportTTL:    exportTTL,
+			ExportLimit:  viper.GetInt("privacy.exportLimit"),
+			ExportWindow: exportWindow,
+		},
+		StepUpConfig: service.StepUpConfig{
+			Thresholds:   stepUpThresholds,
+			OperationTTL: stepUpOperationTTL,
+			CodeAttempts: viper.GetInt("stepUp.codeAttempts"),
+			UserAttempts: viper.GetInt("stepUp.userAttempts"),
+			UserWindow:   stepUpUserWindow,
+		},
+		MachinesConfig: service.MachinesConfig{
+			OfflineAfter:        machinesOfflineAfter,
+			HealthCheckInterval: machinesHealthCheckInterval,
+			SessionIdleTimeout:  machinesSessionIdleTimeout,
+			CashoutCodes: service.CashoutCodesConfig{
+				TTL:             cashoutCodesTTL,
+				MaxAmount:       viper.GetInt("cashoutCodes.maxAmount"),
+				MaxPending:      viper.GetInt("cashoutCodes.maxPending"),
+				SecretAttempts:  viper.GetInt("cashoutCodes.secretAttempts"),
+				MachineAttempts: viper.GetInt("cashoutCodes.machineAttempts"),
+				MachineWindow:   cashoutCodesMachineWindow,
+				SweepInterval:   cashoutCodesSweepInterval,
+				CodeKey:         cashoutCodeKey,
+			},
+			ThirdPartyDeposits: service.ThirdPartyDepositsConfig{
+				MaxAmount:       viper.GetInt("thirdPartyDeposits.maxAmount"),
+				ReviewThreshold: viper.GetInt("thirdPartyDeposits.reviewThreshold"),
+				LookupAttempts:  viper.GetInt("thirdPartyDeposits.lookupAttempts"),
+				LookupWindow:    thirdPartyDepositsLookupWindow,
+			},
+		},
+		CardsConfig: service.CardsConfig{
+			Bin:            cardsBin,
+			ValidityMonths: viper.GetInt("cards.validityMonths"),
+			PinAttempts:    viper.GetInt("cards.pinAttempts"),
+			MaxPerAccount:  viper.GetInt("cards.maxPerAccount"),
+			PanKey:         panKey,
+		},
 	})
 
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	go services.Privacy.RunExportWorker(workersCtx)
+	go services.Machines.RunHealthMonitor(workersCtx)
+	go services.Machines.RunSessionSweeper(workersCtx)
+	go services.Machines.RunCashoutCodeSweeper(workersCtx)
+
 	handlerDeps := handler.Deps{
 		TokenManager: tokenManager,
 		Services:     services,
@@ -129,6 +366,9 @@ func main() {
 
 	logrus.Printf("Server shutting down...")
 
+	stopRotation()
+	stopWorkers()
+
 	if err := srv.Shutdown(context.Background()); err != nil {
 		logrus.Fatalf("error shutting down server: %s", err)
 	}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"math"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/broker"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/gateway"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/hasher"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/postgres"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/redisdb"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// gateway serves ATMs speaking ISO 8583 over mutual TLS. Terminals are mapped
+// to machines in gateway.terminals as "<terminal id>=<machine id>", client
+// certificate of the terminal is issued with the terminal id as common name.
+func main() {
+	viper.AddConfigPath("configs")
+	viper.SetConfigName("config")
+	if err := viper.ReadInConfig(); err != nil {
+		logrus.Fatalf("error loading configs: %s", err)
+	}
+	if err := godotenv.Load(); err != nil {
+		logrus.Fatalf("error loading env file: %s", err)
+	}
+
+	db, err := postgres.NewPostgresDB(postgres.Config{
+		User:     viper.GetString("db.user"),
+		Password: os.Getenv("POSTGRES_PASSWORD"),
+		Host:     viper.GetString("db.host"),
+		Port:     viper.GetString("db.port"),
+		DBName:   viper.GetString("db.name"),
+		SSLMode:  viper.GetString("db.sslmode"),
+	})
+	if err != nil {
+		logrus.Fatalf("error connect to postgres: %s", err)
+	}
+	rdb := redisdb.NewRedisDB(redisdb.Config{
+		Host:     viper.GetString("redis.host"),
+		Port:     viper.GetString("redis.port"),
+		DB:       viper.GetInt("redis.db"),
+		Password: os.Getenv("REDIS_PASSWORD"),
+	})
+
+	transactionManager := transactions.NewManager(db)
+	repos := repository.NewRepository(repository.Deps{
+		DB:        db,
+		CtxGetter: transactions.NewCtxGetter(transactions.NewCtxManager()),
+	})
+
+	hasherThreads := viper.GetUint("hasher.threads")
+	if hasherThreads > math.MaxUint8 {
+		logrus.Fatalf("invalid hasher.threads: %d", hasherThreads)
+	}
+	hasher, err := hasher.NewArgon2Hasher(hasher.Argon2Config{
+		Time:    viper.GetUint32("hasher.time"),
+		Memory:  viper.GetUint32("hasher.memory"),
+		Threads: uint8(hasherThreads),
+		KeyLen:  viper.GetUint32("hasher.keyLen"),
+		SaltLen: viper.GetUint32("hasher.saltLen"),
+	}, hasher.NewHasher(os.Getenv("SALT")))
+	if err != nil {
+		logrus.Fatalf("invalid hasher config: %s", err)
+	}
+
+	broker := broker.NewBroker(broker.Deps{
+		RDB: rdb,
+	})
+
+	machinesSessionIdleTimeout, err := time.ParseDuration(viper.GetString("machines.sessionIdleTimeout"))
+	if err != nil {
+		logrus.Fatalf("invalid machines.sessionIdleTimeout: %s", err)
+	}
+	if machinesSessionIdleTimeout <= 0 {
+		logrus.Fatalf("invalid machines.sessionIdleTimeout: %s", machinesSessionIdleTimeout)
+	}
+	idleTimeout, err := time.ParseDuration(viper.GetString("gateway.idleTimeout"))
+	if err != nil {
+		logrus.Fatalf("invalid gateway.idleTimeout: %s", err)
+	}
+	lengthHeader := viper.GetInt("gateway.lengthHeader")
+	if lengthHeader != 2 && lengthHeader != 4 {
+		logrus.Fatalf("invalid gateway.lengthHeader: %d", lengthHeader)
+	}
+
+	terminals := make(map[string]uuid.UUID)
+	for _, terminal := range viper.GetStringSlice("gateway.terminals") {
+		terminalId, machine, ok := strings.Cut(terminal, "=")
+		if !ok {
+			logrus.Fatalf("invalid gateway.terminals entry: %q", terminal)
+		}
+		machineId, err := uuid.Parse(machine)
+		if err != nil {
+			logrus.Fatalf("invalid machine id of terminal %s: %s", terminalId, err)
+		}
+		terminals[terminalId] = machineId
+	}
+
+	cardsBin := viper.GetString("cards.bin")
+	if !domain.ValidateBin(cardsBin) {
+		logrus.Fatalf("invalid cards.bin: %q", cardsBin)
+	}
+	panKey := os.Getenv("PAN_KEY")
+	if !domain.ValidatePanKey(panKey) {
+		logrus.Fatalf("PAN_KEY must be set and at least %d bytes long", domain.PanKeyMinLength)
+	}
+
+	machines := service.NewMachinesService(repos.Machines, repos.Accounts, repos.Users, repos.Cassettes,
+		repos.MachineHealth, repos.MachineSessions, repos.MachineJournal, repos.CashoutCodes, rdb, hasher,
+		broker, nil, transactionManager, service.MachinesConfig{
+			SessionIdleTimeout: machinesSessionIdleTimeout,
+		})
+	cards := service.NewCardsService(repos.Cards, repos.Accounts, repos.Users, hasher, service.CardsConfig{
+		Bin:            cardsBin,
+		ValidityMonths: viper.GetInt("cards.validityMonths"),
+		PinAttempts:    viper.GetInt("cards.pinAttempts"),
+		MaxPerAccount:  viper.GetInt("cards.maxPerAccount"),
+		PanKey:         panKey,
+	})
+
+	certificate, err := tls.LoadX509KeyPair(viper.GetString("gateway.certFile"), viper.GetString("gateway.keyFile"))
+	if err != nil {
+		logrus.Fatalf("error loading gateway certificate: %s", err)
+	}
+	clientCA, err := os.ReadFile(viper.GetString("gateway.clientCAFile"))
+	if err != nil {
+		logrus.Fatalf("error loading gateway client CA: %s", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(clientCA) {
+		logrus.Fatalf("invalid gateway.clientCAFile: no certificates")
+	}
+
+	gw := gateway.NewGateway(gateway.Config{
+		TLS: &tls.Config{
+			Certificates: []tls.Certificate{certificate},
+			ClientCAs:    clientCAs,
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			MinVersion:   tls.VersionTLS12,
+		},
+		LengthHeader: lengthHeader,
+		HexBitmap:    viper.GetBool("gateway.hexBitmap"),
+		Messages:     viper.GetStringSlice("gateway.messages"),
+		Terminals:    terminals,
+		Currency:     viper.GetString("gateway.currency"),
+		MinorUnits:   viper.GetInt("gateway.minorUnits"),
+		IdleTimeout:  idleTimeout,
+	}, machines, cards)
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(viper.GetString("gateway.host"),
+		viper.GetString("gateway.port")))
+	if err != nil {
+		logrus.Fatalf("error listening: %s", err)
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := gw.Serve(ctx, listener); err != nil {
+			logrus.Fatalf("error running gateway: %s", err)
+		}
+	}()
+	logrus.Printf("Gateway starting...")
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
+	<-quit
+
+	logrus.Printf("Gateway shutting down...")
+
+	stop()
+	<-done
+
+	if err := db.Close(); err != nil {
+		logrus.Fatalf("error clode db connect: %s", err)
+	}
+	if err := rdb.Close(); err != nil {
+		logrus.Fatalf("error close redisdb connect: %s", err)
+	}
+
+	logrus.Printf("Gateway stoped")
+}
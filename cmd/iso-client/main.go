@@ -0,0 +1,194 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/iso8583"
+	"github.com/sirupsen/logrus"
+)
+
+// iso-client signs on to the gateway as the terminal of its certificate, sends
+// single ISO 8583 request and prints the responses, it's meant for testing the
+// gateway without ATM.
+//
+//	go run ./cmd/iso-client -cert atm.crt -key atm.key -ca gateway-ca.crt -terminal ATM00001 \
+//		-pan <pan> -expiry 12/29 -pin 1234 -op cashout -amount 5000
+func main() {
+	addr := flag.String("addr", "localhost:8583", "address of the gateway")
+	header := flag.Int("header", 2, "size of length header, 2 or 4 bytes")
+	certFile := flag.String("cert", "", "client certificate of the terminal")
+	keyFile := flag.String("key", "", "key of the client certificate")
+	caFile := flag.String("ca", "", "CA of the gateway certificate")
+	terminal := flag.String("terminal", "", "terminal id of the machine")
+	pan := flag.String("pan", "", "card number")
+	expiry := flag.String("expiry", "", "card expiry as MM/YY")
+	pin := flag.String("pin", "", "card PIN")
+	op := flag.String("op", "balance", "operation: balance, authorize, cashout, deposit, reversal or echo")
+	amount := flag.Int("amount", 0, "amount in minor units")
+	currency := flag.String("currency", "643", "currency code")
+	rrn := flag.String("rrn", "", "retrieval reference number, generated if empty")
+	notes := flag.String("notes", "", "deposited notes as 5000x2,1000x3")
+	flag.Parse()
+
+	stan, err := randomDigits(6)
+	if err != nil {
+		logrus.Fatalf("error generating stan: %s", err)
+	}
+	if *rrn == "" {
+		if *rrn, err = randomDigits(12); err != nil {
+			logrus.Fatalf("error generating rrn: %s", err)
+		}
+	}
+
+	now := time.Now().UTC()
+	var request *iso8583.Message
+	switch *op {
+	case "balance":
+		request = iso8583.NewMessage("0200")
+		request.Set(3, "310000")
+	case "authorize":
+		request = iso8583.NewMessage("0100")
+		request.Set(3, "010000")
+	case "cashout":
+		request = iso8583.NewMessage("0200")
+		request.Set(3, "010000")
+	case "deposit":
+		request = iso8583.NewMessage("0200")
+		request.Set(3, "210000")
+		request.Set(48, *notes)
+	case "reversal":
+		request = iso8583.NewMessage("0420")
+		request.Set(3, "010000")
+	case "echo":
+		request = iso8583.NewMessage("0800")
+		request.Set(70, "301")
+	default:
+		logrus.Fatalf("unknown operation %q", *op)
+	}
+
+	request.Set(7, now.Format("0102150405"))
+	request.Set(11, stan)
+	if *terminal != "" {
+		request.Set(41, *terminal)
+	}
+	if request.MTI != "0800" {
+		request.Set(12, now.Format("150405"))
+		request.Set(13, now.Format("0102"))
+		request.Set(37, *rrn)
+		request.Set(49, *currency)
+		if *amount > 0 {
+			request.Set(4, fmt.Sprintf("%012d", *amount))
+		}
+	}
+	if request.MTI == "0100" || request.MTI == "0200" {
+		if len(*expiry) != 5 {
+			logrus.Fatalf("invalid expiry %q", *expiry)
+		}
+		request.Set(2, *pan)
+		request.Set(14, (*expiry)[3:]+(*expiry)[:2])
+		pinBlock, err := iso8583.EncodePinBlock(*pin, *pan)
+		if err != nil {
+			logrus.Fatalf("error encoding pin block: %s", err)
+		}
+		request.Set(52, string(pinBlock))
+	}
+
+	certificate, err := tls.LoadX509KeyPair(*certFile, *keyFile)
+	if err != nil {
+		logrus.Fatalf("error loading client certificate: %s", err)
+	}
+	ca, err := os.ReadFile(*caFile)
+	if err != nil {
+		logrus.Fatalf("error loading CA: %s", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(ca) {
+		logrus.Fatalf("invalid CA: no certificates")
+	}
+	host, _, err := net.SplitHostPort(*addr)
+	if err != nil {
+		logrus.Fatalf("invalid address: %s", err)
+	}
+
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", *addr, &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		RootCAs:      roots,
+		ServerName:   host,
+		MinVersion:   tls.VersionTLS12,
+	})
+	if err != nil {
+		logrus.Fatalf("error connecting to gateway: %s", err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(30 * time.Second)); err != nil {
+		logrus.Fatalf("error setting deadline: %s", err)
+	}
+
+	spec := iso8583.DefaultSpec()
+	if request.MTI != "0800" {
+		signOn := iso8583.NewMessage("0800")
+		signOn.Set(7, now.Format("0102150405"))
+		signOn.Set(11, stan)
+		signOn.Set(41, *terminal)
+		signOn.Set(70, "001")
+		response := exchange(conn, spec, *header, signOn)
+		if response.Get(39) != "00" {
+			logrus.Fatalf("sign on declined with %s", response.Get(39))
+		}
+	}
+	exchange(conn, spec, *header, request)
+}
+
+// exchange sends the request and returns the response, both are printed.
+func exchange(conn net.Conn, spec *iso8583.Spec, header int, request *iso8583.Message) *iso8583.Message {
+	data, err := spec.Pack(request)
+	if err != nil {
+		logrus.Fatalf("error packing request: %s", err)
+	}
+	printMessage(">>", request)
+	if err := iso8583.WriteFrame(conn, header, data); err != nil {
+		logrus.Fatalf("error writing request: %s", err)
+	}
+	data, err = iso8583.ReadFrame(conn, header)
+	if err != nil {
+		logrus.Fatalf("error reading response: %s", err)
+	}
+	response, err := spec.Unpack(data)
+	if err != nil {
+		logrus.Fatalf("error unpacking response: %s", err)
+	}
+	printMessage("<<", response)
+	return response
+}
+
+func printMessage(direction string, m *iso8583.Message) {
+	fmt.Printf("%s %s\n", direction, m.MTI)
+	for _, field := range m.Fields() {
+		value := m.Get(field)
+		if field == 52 {
+			value = fmt.Sprintf("%X", value)
+		}
+		fmt.Printf("   %3d: %s\n", field, value)
+	}
+}
+
+func randomDigits(n int) (string, error) {
+	digits := make([]byte, n)
+	for i := range digits {
+		d, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		digits[i] = byte('0' + d.Int64())
+	}
+	return string(digits), nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/postgres"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/tokens"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// machine-token issues token for ATM at provisioning. The previous token of
+// the machine is revoked.
+//
+//	go run ./cmd/machine-token -machine <uuid>
+func main() {
+	machine := flag.String("machine", "", "id of the machine")
+	flag.Parse()
+
+	machineId, err := uuid.Parse(*machine)
+	if err != nil {
+		logrus.Fatalf("invalid machine id: %s", err)
+	}
+
+	viper.AddConfigPath("configs")
+	viper.SetConfigName("config")
+	if err := viper.ReadInConfig(); err != nil {
+		logrus.Fatalf("error loading configs: %s", err)
+	}
+	if err := godotenv.Load(); err != nil {
+		logrus.Fatalf("error loading env file: %s", err)
+	}
+
+	db, err := postgres.NewPostgresDB(postgres.Config{
+		User:     viper.GetString("db.user"),
+		Password: os.Getenv("POSTGRES_PASSWORD"),
+		Host:     viper.GetString("db.host"),
+		Port:     viper.GetString("db.port"),
+		DBName:   viper.GetString("db.name"),
+		SSLMode:  viper.GetString("db.sslmode"),
+	})
+	if err != nil {
+		logrus.Fatalf("error connect to postgres: %s", err)
+	}
+	defer db.Close()
+
+	repos := repository.NewRepository(repository.Deps{
+		DB:        db,
+		CtxGetter: transactions.NewCtxGetter(transactions.NewCtxManager()),
+	})
+
+	machineTTL, err := time.ParseDuration(viper.GetString("tokens.machineTTL"))
+	if err != nil {
+		logrus.Fatalf("invalid machineTTL: %s", err)
+	}
+	signingKeys, err := tokens.NewKeySet(viper.GetString("tokens.keysDir"))
+	if err != nil {
+		logrus.Fatalf("error loading signing keys: %s", err)
+	}
+	tokenManager := tokens.NewTokenManager(tokens.Config{
+		Keys:       signingKeys,
+		MachineTTL: machineTTL,
+	})
+
+	machines := service.NewMachinesService(repos.Machines, repos.Accounts, repos.Users, repos.Cassettes,
+		repos.MachineHealth, repos.MachineSessions, repos.MachineJournal, repos.CashoutCodes, nil, nil, nil,
+		tokenManager, nil, service.MachinesConfig{})
+	token, err := machines.IssueToken(context.Background(), machineId)
+	if err != nil {
+		logrus.Fatalf("error issuing machine token: %s", err)
+	}
+
+	fmt.Println(token)
+}
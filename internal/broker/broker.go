@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
@@ -14,10 +16,33 @@ var (
 	emailVerificationQueue = "queue:verification:email"
 	cashoutQueue           = "queue:cashout"
 	depositQueue           = "queue:deposit"
+	passwordResetQueue     = "queue:password-reset:email"
+	emailChangeQueue       = "queue:email-change:email"
+	emailChangeNoticeQueue = "queue:email-change:notice"
+	signInLockoutQueue     = "queue:signin:lockout"
+	exportQueue            = "queue:export"
+	exportReadyQueue       = "queue:export:ready"
+	accountErasedQueue     = "queue:account:erased"
+	newLoginQueue          = "queue:signin:new-device"
+	stepUpCodeQueue        = "queue:step-up:code"
+	magicLinkQueue         = "queue:magic-link:email"
+	lowCashQueue           = "queue:machine:low-cash"
+	machineOfflineQueue    = "queue:machine:offline"
+	discrepancyQueue       = "queue:machine:discrepancy"
+	depositReviewQueue     = "queue:deposit:review"
+	passkeyAddedQueue      = "queue:passkey:added"
+)
+
+var notificationsKey = "notifications:%s"
+
+const (
+	notificationsLimit = 500
+	notificationsTTL   = 365 * 24 * time.Hour
 )
 
 var (
 	ErrInternal = errors.New("error internal")
+	ErrNoTask   = errors.New("no task")
 )
 
 type BrokerInterface interface {
@@ -26,6 +51,25 @@ type BrokerInterface interface {
 		amount int, newMoney int) error
 	WriteDepositTask(ctx context.Context, machineId uuid.UUID, email string, accId uuid.UUID,
 		amount int, newMoney int) error
+	WritePasswordResetTask(ctx context.Context, email string, token string) error
+	WriteEmailChangeTask(ctx context.Context, email string, token string) error
+	WriteEmailChangeNoticeTask(ctx context.Context, oldEmail string, newEmail string) error
+	WriteSignInLockoutTask(ctx context.Context, email string, ip string) error
+	WriteExportTask(ctx context.Context, exportId uuid.UUID) error
+	ReadExportTask(ctx context.Context, timeout time.Duration) (uuid.UUID, error)
+	WriteExportReadyTask(ctx context.Context, email string, exportId uuid.UUID) error
+	WriteAccountErasedTask(ctx context.Context, email string) error
+	WriteNewLoginTask(ctx context.Context, email string, userAgent string, ip string) error
+	WriteStepUpCodeTask(ctx context.Context, email string, code string, amount int) error
+	WriteMagicLinkTask(ctx context.Context, email string, token string) error
+	WriteLowCashTask(ctx context.Context, machineId uuid.UUID, denomination int, count int) error
+	WriteMachineOfflineTask(ctx context.Context, machineId uuid.UUID, name string, lastSeenAt time.Time) error
+	WriteDiscrepancyTask(ctx context.Context, machineId uuid.UUID, settlementId uuid.UUID, expected int,
+		counted int) error
+	WriteDepositReviewTask(ctx context.Context, machineId uuid.UUID, accId uuid.UUID, amount int) error
+	WritePasskeyAddedTask(ctx context.Context, email string, name string) error
+	GetNotifications(ctx context.Context, email string) ([]Notification, error)
+	DeleteNotifications(ctx context.Context, email string) error
 }
 
 type Broker struct {
@@ -56,8 +100,65 @@ func (b *Broker) writeTask(ctx context.Context, key string, data interface{}) er
 	return nil
 }
 
+// notify writes task of notification for the user and keeps the notification
+// in history of the recipient. Failure to keep history doesn't fail the task.
+func (b *Broker) notify(ctx context.Context, key string, email string, kind string,
+	data interface{}) error {
+	if err := b.writeTask(ctx, key, data); err != nil {
+		return err
+	}
+
+	notification, err := json.Marshal(Notification{
+		Kind:   kind,
+		SentAt: time.Now(),
+	})
+	if err != nil {
+		logrus.Errorf("error marshaling notification %s: %s", kind, err)
+		return nil
+	}
+	historyKey := fmt.Sprintf(notificationsKey, email)
+	_, err = b.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, historyKey, notification)
+		pipe.LTrim(ctx, historyKey, 0, notificationsLimit-1)
+		pipe.Expire(ctx, historyKey, notificationsTTL)
+		return nil
+	})
+	if err != nil {
+		logrus.Errorf("error saving notification %s into redis: %s", kind, err)
+	}
+	return nil
+}
+
+// GetNotifications returns notifications sent to the email, newest first.
+func (b *Broker) GetNotifications(ctx context.Context, email string) ([]Notification, error) {
+	notifications := []Notification{}
+
+	result, err := b.rdb.LRange(ctx, fmt.Sprintf(notificationsKey, email), 0, -1).Result()
+	if err != nil {
+		logrus.Errorf("error getting notifications from redis: %s", err)
+		return notifications, ErrInternal
+	}
+	for _, data := range result {
+		var notification Notification
+		if err := json.Unmarshal([]byte(data), &notification); err != nil {
+			logrus.Errorf("error unmarshaling notification: %s", err)
+			return notifications, ErrInternal
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, nil
+}
+
+func (b *Broker) DeleteNotifications(ctx context.Context, email string) error {
+	if err := b.rdb.Del(ctx, fmt.Sprintf(notificationsKey, email)).Err(); err != nil {
+		logrus.Errorf("error deleting notifications from redis: %s", err)
+		return ErrInternal
+	}
+	return nil
+}
+
 func (b *Broker) WriteVerificationTask(ctx context.Context, email string) error {
-	return b.writeTask(ctx, emailVerificationQueue, sendEmailVerificationMessageTask{
+	return b.notify(ctx, emailVerificationQueue, email, NotificationVerification, sendEmailVerificationMessageTask{
 		Email: email,
 	})
 }
@@ -71,7 +172,7 @@ func (b *Broker) WriteCashoutTask(ctx context.Context, machineId uuid.UUID,
 		Amount:    amount,
 		NewMoney:  newMoney,
 	}
-	return b.writeTask(ctx, cashoutQueue, data)
+	return b.notify(ctx, cashoutQueue, email, NotificationCashout, data)
 }
 
 func (b *Broker) WriteDepositTask(ctx context.Context, machineId uuid.UUID, email string,
@@ -83,5 +184,138 @@ func (b *Broker) WriteDepositTask(ctx context.Context, machineId uuid.UUID, emai
 		Amount:    amount,
 		NewMoney:  newMoney,
 	}
-	return b.writeTask(ctx, depositQueue, data)
+	return b.notify(ctx, depositQueue, email, NotificationDeposit, data)
+}
+
+func (b *Broker) WritePasswordResetTask(ctx context.Context, email string, token string) error {
+	return b.notify(ctx, passwordResetQueue, email, NotificationPasswordReset, passwordResetTask{
+		Email: email,
+		Token: token,
+	})
+}
+
+func (b *Broker) WriteEmailChangeTask(ctx context.Context, email string, token string) error {
+	return b.notify(ctx, emailChangeQueue, email, NotificationEmailChange, emailChangeTask{
+		Email: email,
+		Token: token,
+	})
+}
+
+func (b *Broker) WriteEmailChangeNoticeTask(ctx context.Context, oldEmail string,
+	newEmail string) error {
+	return b.notify(ctx, emailChangeNoticeQueue, oldEmail, NotificationEmailChangeNotice, emailChangeNoticeTask{
+		OldEmail: oldEmail,
+		NewEmail: newEmail,
+	})
+}
+
+func (b *Broker) WriteSignInLockoutTask(ctx context.Context, email string, ip string) error {
+	return b.notify(ctx, signInLockoutQueue, email, NotificationSignInLockout, signInLockoutTask{
+		Email: email,
+		Ip:    ip,
+	})
+}
+
+func (b *Broker) WriteExportTask(ctx context.Context, exportId uuid.UUID) error {
+	return b.writeTask(ctx, exportQueue, exportTask{
+		ExportId: exportId,
+	})
+}
+
+// ReadExportTask waits for export task up to timeout. It returns ErrNoTask if
+// the queue stayed empty.
+func (b *Broker) ReadExportTask(ctx context.Context, timeout time.Duration) (uuid.UUID, error) {
+	result, err := b.rdb.BLPop(ctx, timeout, exportQueue).Result()
+	if err != nil {
+		if errors.Is(redis.Nil, err) {
+			return uuid.UUID{}, ErrNoTask
+		}
+		logrus.Errorf("error reading task from %s: %s", exportQueue, err)
+		return uuid.UUID{}, ErrInternal
+	}
+	var task exportTask
+	if err := json.Unmarshal([]byte(result[1]), &task); err != nil {
+		logrus.Errorf("error unmarshaling task from %s: %s", exportQueue, err)
+		return uuid.UUID{}, ErrInternal
+	}
+	return task.ExportId, nil
+}
+
+func (b *Broker) WriteExportReadyTask(ctx context.Context, email string, exportId uuid.UUID) error {
+	return b.notify(ctx, exportReadyQueue, email, NotificationExportReady, exportReadyTask{
+		Email:    email,
+		ExportId: exportId,
+	})
+}
+
+func (b *Broker) WriteAccountErasedTask(ctx context.Context, email string) error {
+	return b.writeTask(ctx, accountErasedQueue, accountErasedTask{
+		Email: email,
+	})
+}
+
+func (b *Broker) WriteNewLoginTask(ctx context.Context, email string, userAgent string, ip string) error {
+	return b.notify(ctx, newLoginQueue, email, NotificationNewLogin, newLoginTask{
+		Email:     email,
+		UserAgent: userAgent,
+		Ip:        ip,
+	})
+}
+
+func (b *Broker) WriteStepUpCodeTask(ctx context.Context, email string, code string, amount int) error {
+	return b.notify(ctx, stepUpCodeQueue, email, NotificationStepUpCode, stepUpCodeTask{
+		Email:  email,
+		Code:   code,
+		Amount: amount,
+	})
+}
+
+func (b *Broker) WriteMagicLinkTask(ctx context.Context, email string, token string) error {
+	return b.notify(ctx, magicLinkQueue, email, NotificationMagicLink, magicLinkTask{
+		Email: email,
+		Token: token,
+	})
+}
+
+func (b *Broker) WriteLowCashTask(ctx context.Context, machineId uuid.UUID, denomination int, count int) error {
+	return b.writeTask(ctx, lowCashQueue, lowCashTask{
+		MachineId:    machineId,
+		Denomination: denomination,
+		Count:        count,
+	})
+}
+
+func (b *Broker) WriteMachineOfflineTask(ctx context.Context, machineId uuid.UUID, name string,
+	lastSeenAt time.Time) error {
+	return b.writeTask(ctx, machineOfflineQueue, machineOfflineTask{
+		MachineId:  machineId,
+		Name:       name,
+		LastSeenAt: lastSeenAt,
+	})
+}
+
+func (b *Broker) WriteDiscrepancyTask(ctx context.Context, machineId uuid.UUID, settlementId uuid.UUID,
+	expected int, counted int) error {
+	return b.writeTask(ctx, discrepancyQueue, discrepancyTask{
+		MachineId:    machineId,
+		SettlementId: settlementId,
+		Expected:     expected,
+		Counted:      counted,
+	})
+}
+
+func (b *Broker) WriteDepositReviewTask(ctx context.Context, machineId uuid.UUID, accId uuid.UUID,
+	amount int) error {
+	return b.writeTask(ctx, depositReviewQueue, depositReviewTask{
+		MachineId: machineId,
+		AccountId: accId,
+		Amount:    amount,
+	})
+}
+
+func (b *Broker) WritePasskeyAddedTask(ctx context.Context, email string, name string) error {
+	return b.notify(ctx, passkeyAddedQueue, email, NotificationPasskeyAdded, passkeyAddedTask{
+		Email: email,
+		Name:  name,
+	})
 }
@@ -1,6 +1,32 @@
 package broker
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	NotificationVerification      = "verification"
+	NotificationCashout           = "cashout"
+	NotificationDeposit           = "deposit"
+	NotificationPasswordReset     = "password_reset"
+	NotificationEmailChange       = "email_change"
+	NotificationEmailChangeNotice = "email_change_notice"
+	NotificationSignInLockout     = "sign_in_lockout"
+	NotificationExportReady       = "export_ready"
+	NotificationNewLogin          = "new_login"
+	NotificationStepUpCode        = "step_up_code"
+	NotificationMagicLink         = "magic_link"
+	NotificationPasskeyAdded      = "passkey_added"
+)
+
+// Notification is a message sent to the user. Only its kind is kept, content
+// may hold tokens and codes.
+type Notification struct {
+	Kind   string    `json:"kind"`
+	SentAt time.Time `json:"sentAt"`
+}
 
 type sendEmailVerificationMessageTask struct {
 	Email string `json:"email"`
@@ -29,3 +55,83 @@ type depositTask struct {
 	Amount    int       `json:"amount"`
 	NewMoney  int       `json:"new_money"`
 }
+
+type passwordResetTask struct {
+	Email string `json:"email"`
+	Token string `json:"token"`
+}
+
+type emailChangeTask struct {
+	Email string `json:"email"`
+	Token string `json:"token"`
+}
+
+type emailChangeNoticeTask struct {
+	OldEmail string `json:"oldEmail"`
+	NewEmail string `json:"newEmail"`
+}
+
+type signInLockoutTask struct {
+	Email string `json:"email"`
+	Ip    string `json:"ip"`
+}
+
+type exportTask struct {
+	ExportId uuid.UUID `json:"exportId"`
+}
+
+type exportReadyTask struct {
+	Email    string    `json:"email"`
+	ExportId uuid.UUID `json:"exportId"`
+}
+
+type accountErasedTask struct {
+	Email string `json:"email"`
+}
+
+type newLoginTask struct {
+	Email     string `json:"email"`
+	UserAgent string `json:"userAgent"`
+	Ip        string `json:"ip"`
+}
+
+type stepUpCodeTask struct {
+	Email  string `json:"email"`
+	Code   string `json:"code"`
+	Amount int    `json:"amount"`
+}
+
+type magicLinkTask struct {
+	Email string `json:"email"`
+	Token string `json:"token"`
+}
+
+type lowCashTask struct {
+	MachineId    uuid.UUID `json:"machineId"`
+	Denomination int       `json:"denomination"`
+	Count        int       `json:"count"`
+}
+
+type machineOfflineTask struct {
+	MachineId  uuid.UUID `json:"machineId"`
+	Name       string    `json:"name"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+}
+
+type discrepancyTask struct {
+	MachineId    uuid.UUID `json:"machineId"`
+	SettlementId uuid.UUID `json:"settlementId"`
+	Expected     int       `json:"expected"`
+	Counted      int       `json:"counted"`
+}
+
+type depositReviewTask struct {
+	MachineId uuid.UUID `json:"machineId"`
+	AccountId uuid.UUID `json:"accountId"`
+	Amount    int       `json:"amount"`
+}
+
+type passkeyAddedTask struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
@@ -6,14 +6,16 @@ type Account struct {
 	Id     uuid.UUID `db:"id"`
 	Money  int       `db:"money"`
 	UserId uuid.UUID `db:"user_id"`
+	Frozen bool      `db:"frozen"`
 }
 
 type AccountUpdate struct {
-	Money *int
+	Money  *int
+	Frozen *bool
 }
 
 func (a *AccountUpdate) Validate() bool {
-	if a.Money == nil {
+	if a.Money == nil && a.Frozen == nil {
 		return false
 	}
 	return true
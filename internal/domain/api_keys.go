@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	ScopeAccountsRead     = "accounts:read"
+	ScopeTransactionsRead = "transactions:read"
+	ScopeTransfersWrite   = "transfers:write"
+)
+
+func ValidateScopes(scopes []string) bool {
+	if len(scopes) == 0 {
+		return false
+	}
+	for _, scope := range scopes {
+		switch scope {
+		case ScopeAccountsRead, ScopeTransactionsRead, ScopeTransfersWrite:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func ValidateApiKeyName(name string) bool {
+	length := utf8.RuneCountInString(name)
+	return length >= 1 && length <= 64
+}
+
+// ApiKey gives third-party integration access to the user's data limited by
+// scopes and optionally by a single account. Only hash of the key is stored.
+type ApiKey struct {
+	Id         uuid.UUID      `db:"id"`
+	UserId     uuid.UUID      `db:"user_id"`
+	Name       string         `db:"name"`
+	Hash       string         `db:"key_hash"`
+	Scopes     pq.StringArray `db:"scopes"`
+	AccountId  uuid.NullUUID  `db:"account_id"`
+	ExpiresAt  *time.Time     `db:"expires_at"`
+	LastUsedAt *time.Time     `db:"last_used_at"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+func (k *ApiKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (k *ApiKey) AllowsAccount(accountId uuid.UUID) bool {
+	return !k.AccountId.Valid || k.AccountId.UUID == accountId
+}
+
+func (k *ApiKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CardStatusActive  = "active"
+	CardStatusBlocked = "blocked"
+)
+
+const (
+	PanLength = 16
+	PinLength = 4
+	// PanKeyMinLength is the shortest secret key of PAN hashes. PAN has few random
+	// digits, so the key is all that keeps hashes from being brute forced.
+	PanKeyMinLength = 32
+)
+
+func digitsOnly(value string) bool {
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// LuhnCheckDigit returns digit which makes payload followed by it pass the Luhn check.
+func LuhnCheckDigit(payload string) byte {
+	sum := 0
+	for i := len(payload) - 1; i >= 0; i-- {
+		digit := int(payload[i] - '0')
+		// doubled are digits at odd positions from the right once the check digit is appended
+		if (len(payload)-i)%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func ValidatePan(pan string) bool {
+	if len(pan) != PanLength || !digitsOnly(pan) {
+		return false
+	}
+	return LuhnCheckDigit(pan[:len(pan)-1]) == pan[len(pan)-1]
+}
+
+// ValidateBin checks issuer identification number. At least a few digits of PAN
+// are left to be random.
+func ValidateBin(bin string) bool {
+	return len(bin) >= 6 && len(bin) <= 8 && digitsOnly(bin)
+}
+
+func ValidatePanKey(key string) bool {
+	return len(key) >= PanKeyMinLength
+}
+
+func ValidatePin(pin string) bool {
+	return len(pin) == PinLength && digitsOnly(pin)
+}
+
+// ValidateCardExpiry checks expiry printed on the card as MM/YY.
+func ValidateCardExpiry(expiry string) bool {
+	_, err := time.Parse("01/06", expiry)
+	return err == nil
+}
+
+// Card is debit card linked to the account. PAN is known only to the holder,
+// the bank keeps its keyed hash and the last four digits. PinAttempts counts
+// wrong PINs in a row, PinLocked is set by service when they reached the limit.
+type Card struct {
+	Id          uuid.UUID `db:"id"`
+	UserId      uuid.UUID `db:"user_id"`
+	AccountId   uuid.UUID `db:"account_id"`
+	PanHash     string    `db:"pan_hash"`
+	Last4       string    `db:"last4"`
+	ExpiresOn   time.Time `db:"expires_on"`
+	Status      string    `db:"status"`
+	PinHash     string    `db:"pin_hash"`
+	PinAttempts int       `db:"pin_attempts"`
+	CreatedAt   time.Time `db:"created_at"`
+	PinLocked   bool      `db:"-"`
+}
+
+// Expiry returns expiry of the card as MM/YY.
+func (c *Card) Expiry() string {
+	return fmt.Sprintf("%02d/%02d", c.ExpiresOn.Month(), c.ExpiresOn.Year()%100)
+}
+
+// Expired reports whether the card is expired at t. The card is valid through
+// the whole month of expiry.
+func (c *Card) Expired(t time.Time) bool {
+	return !t.UTC().Before(c.ExpiresOn.AddDate(0, 1, 0))
+}
+
+// IssuedCard is the card with its PAN, which is shown only once when issued.
+type IssuedCard struct {
+	Card Card
+	Pan  string
+}
+
+// CardCredentials are read by ATM from the card and entered by the holder.
+type CardCredentials struct {
+	Pan    string
+	Expiry string
+	Pin    string
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CashoutCodePending   = "pending"
+	CashoutCodeRedeemed  = "redeemed"
+	CashoutCodeExpired   = "expired"
+	CashoutCodeCancelled = "cancelled"
+	// CashoutCodeBlocked is set after too many wrong secrets
+	CashoutCodeBlocked = "blocked"
+)
+
+const (
+	CashoutCodeLength   = 10
+	CashoutSecretLength = 6
+	// CashoutCodeKeyMinLength is the shortest secret key of code hashes. Codes
+	// are short numbers, so the key is all that keeps hashes from being brute
+	// forced.
+	CashoutCodeKeyMinLength = 32
+)
+
+// CashoutCode lets the user take cash at a machine without a card. Amount is
+// debited from the account when the code is created and credited back unless
+// the code is redeemed before it expires.
+type CashoutCode struct {
+	Id             uuid.UUID     `db:"id"`
+	UserId         uuid.UUID     `db:"user_id"`
+	AccountId      uuid.UUID     `db:"account_id"`
+	Amount         int           `db:"amount"`
+	CodeHash       string        `db:"code_hash"`
+	SecretHash     string        `db:"secret_hash"`
+	SecretAttempts int           `db:"secret_attempts"`
+	Status         string        `db:"status"`
+	ExpiresAt      time.Time     `db:"expires_at"`
+	MachineId      uuid.NullUUID `db:"machine_id"`
+	FinishedAt     *time.Time    `db:"finished_at"`
+	CreatedAt      time.Time     `db:"created_at"`
+}
+
+// IssuedCashoutCode holds code and secret which are shown to the user only once.
+type IssuedCashoutCode struct {
+	CashoutCode
+	Code   string
+	Secret string
+}
+
+func ValidateCashoutCode(code string) bool {
+	return len(code) == CashoutCodeLength && digitsOnly(code)
+}
+
+func ValidateCashoutCodeKey(key string) bool {
+	return len(key) >= CashoutCodeKeyMinLength
+}
+
+func ValidateCashoutSecret(secret string) bool {
+	return len(secret) == CashoutSecretLength && digitsOnly(secret)
+}
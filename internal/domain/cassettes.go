@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxDispenseNotes is how many notes the dispenser can give out at once.
+const MaxDispenseNotes = 40
+
+// Cassette holds notes of one denomination in the machine.
+type Cassette struct {
+	MachineId    uuid.UUID `db:"machine_id"`
+	Denomination int       `db:"denomination"`
+	Count        int       `db:"count"`
+	LowThreshold int       `db:"low_threshold"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+func (c *Cassette) Low() bool {
+	return c.Count < c.LowThreshold
+}
+
+type Note struct {
+	Denomination int `json:"denomination"`
+	Count        int `json:"count"`
+}
+
+// ValidateNotes checks that notes are positive and every denomination is listed once.
+func ValidateNotes(notes []Note) bool {
+	if len(notes) == 0 {
+		return false
+	}
+	seen := make(map[int]bool, len(notes))
+	for _, note := range notes {
+		if note.Denomination <= 0 || note.Count <= 0 || seen[note.Denomination] {
+			return false
+		}
+		seen[note.Denomination] = true
+	}
+	return true
+}
+
+func NotesAmount(notes []Note) int {
+	var amount int
+	for _, note := range notes {
+		amount += note.Denomination * note.Count
+	}
+	return amount
+}
+
+// PlanDispense finds the mix of notes paying exactly the amount from the cassettes
+// with the fewest notes. Greedy choice isn't enough since counts are limited,
+// e.g. 60 from one 50-note and three 20-notes, so bounded change-making is solved
+// in units of gcd of the denominations.
+func PlanDispense(cassettes []Cassette, amount int) ([]Note, bool) {
+	available := make([]Cassette, 0, len(cassettes))
+	unit := 0
+	maxDenomination := 0
+	for _, cassette := range cassettes {
+		if cassette.Count <= 0 {
+			continue
+		}
+		available = append(available, cassette)
+		unit = gcd(unit, cassette.Denomination)
+		if cassette.Denomination > maxDenomination {
+			maxDenomination = cassette.Denomination
+		}
+	}
+	if amount <= 0 || unit == 0 || amount%unit != 0 || amount > maxDenomination*MaxDispenseNotes {
+		return nil, false
+	}
+	sort.Slice(available, func(i, j int) bool {
+		return available[i].Denomination > available[j].Denomination
+	})
+
+	const unreachable = MaxDispenseNotes + 1
+	target := amount / unit
+	// best[v] is the fewest notes paying v units, take[i][v] is how many notes of
+	// the i-th cassette are used for it
+	best := make([]int, target+1)
+	for v := 1; v <= target; v++ {
+		best[v] = unreachable
+	}
+	take := make([][]int, len(available))
+	for i, cassette := range available {
+		step := cassette.Denomination / unit
+		limit := cassette.Count
+		if limit > MaxDispenseNotes {
+			limit = MaxDispenseNotes
+		}
+		take[i] = make([]int, target+1)
+		next := make([]int, target+1)
+		for v := 0; v <= target; v++ {
+			next[v] = best[v]
+			for k := 1; k <= limit && k*step <= v; k++ {
+				if notes := best[v-k*step] + k; notes < next[v] {
+					next[v] = notes
+					take[i][v] = k
+				}
+			}
+		}
+		best = next
+	}
+	if best[target] > MaxDispenseNotes {
+		return nil, false
+	}
+
+	notes := []Note{}
+	for i, v := len(available)-1, target; i >= 0; i-- {
+		if k := take[i][v]; k > 0 {
+			notes = append(notes, Note{
+				Denomination: available[i].Denomination,
+				Count:        k,
+			})
+			v -= k * available[i].Denomination / unit
+		}
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].Denomination > notes[j].Denomination
+	})
+	return notes, true
+}
+
+func gcd(a int, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
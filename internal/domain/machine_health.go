@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DeviceStatusOk      = "ok"
+	DeviceStatusWarning = "warning"
+	DeviceStatusFault   = "fault"
+	DeviceStatusUnknown = "unknown"
+)
+
+// ValidateDeviceStatus accepts statuses the machine may report about its devices.
+func ValidateDeviceStatus(status string) bool {
+	switch status {
+	case DeviceStatusOk, DeviceStatusWarning, DeviceStatusFault:
+		return true
+	}
+	return false
+}
+
+func ValidateSoftwareVersion(version string) bool {
+	return len(version) >= 1 && len(version) <= 64
+}
+
+// CashLevels are numbers of notes by denomination as sensed by the machine.
+type CashLevels []Note
+
+func (l CashLevels) Validate() bool {
+	seen := make(map[int]bool, len(l))
+	for _, note := range l {
+		if note.Denomination <= 0 || note.Count < 0 || seen[note.Denomination] {
+			return false
+		}
+		seen[note.Denomination] = true
+	}
+	return true
+}
+
+func (l CashLevels) Value() (driver.Value, error) {
+	if l == nil {
+		l = CashLevels{}
+	}
+	return json.Marshal(l)
+}
+
+func (l *CashLevels) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, l)
+	case string:
+		return json.Unmarshal([]byte(src), l)
+	case nil:
+		*l = CashLevels{}
+		return nil
+	}
+	return errors.New("unsupported type of cash levels")
+}
+
+// MachineHeartbeat is state the machine reports periodically.
+type MachineHeartbeat struct {
+	MachineId       uuid.UUID
+	Dispenser       string
+	CardReader      string
+	SoftwareVersion string
+	CashLevels      CashLevels
+}
+
+// MachineHealth is the last known state of the machine. Machines which never sent
+// heartbeat have unknown device statuses and no LastSeenAt.
+type MachineHealth struct {
+	MachineId       uuid.UUID  `db:"machine_id"`
+	Name            string     `db:"name"`
+	Status          string     `db:"status"`
+	Dispenser       string     `db:"dispenser"`
+	CardReader      string     `db:"card_reader"`
+	SoftwareVersion string     `db:"software_version"`
+	CashLevels      CashLevels `db:"cash_levels"`
+	LowCash         bool       `db:"low_cash"`
+	Online          bool       `db:"online"`
+	LastSeenAt      *time.Time `db:"last_seen_at"`
+	OfflineSince    *time.Time `db:"offline_since"`
+}
+
+func (h *MachineHealth) Faulty() bool {
+	return h.Dispenser == DeviceStatusFault || h.CardReader == DeviceStatusFault
+}
+
+// FleetHealth summarizes health of machines in service. Machines lists the ones
+// needing attention: offline, faulty or low on cash.
+type FleetHealth struct {
+	Total       int
+	Online      int
+	Offline     int
+	NeverSeen   int
+	Maintenance int
+	Faulty      int
+	LowCash     int
+	Machines    []MachineHealth
+}
@@ -0,0 +1,90 @@
+package domain
+
+import "math"
+
+const earthRadius = 6371000.0
+
+// Distance returns great-circle distance between two points in meters.
+func Distance(latitude1 float64, longitude1 float64, latitude2 float64, longitude2 float64) float64 {
+	phi1 := latitude1 * math.Pi / 180
+	phi2 := latitude2 * math.Pi / 180
+	deltaPhi := (latitude2 - latitude1) * math.Pi / 180
+	deltaLambda := (longitude2 - longitude1) * math.Pi / 180
+
+	a := math.Sin(deltaPhi/2)*math.Sin(deltaPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(deltaLambda/2)*math.Sin(deltaLambda/2)
+	return 2 * earthRadius * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// BoundingBox contains every point within the radius around the center. When
+// the box crosses the antimeridian MinLongitude is greater than MaxLongitude.
+type BoundingBox struct {
+	MinLatitude  float64
+	MaxLatitude  float64
+	MinLongitude float64
+	MaxLongitude float64
+}
+
+func NewBoundingBox(latitude float64, longitude float64, radius float64) BoundingBox {
+	deltaLatitude := radius / earthRadius * 180 / math.Pi
+	box := BoundingBox{
+		MinLatitude:  latitude - deltaLatitude,
+		MaxLatitude:  latitude + deltaLatitude,
+		MinLongitude: -180,
+		MaxLongitude: 180,
+	}
+	// the circle covers the pole, so every longitude is within the radius
+	if box.MinLatitude <= -90 || box.MaxLatitude >= 90 {
+		box.MinLatitude = math.Max(box.MinLatitude, -90)
+		box.MaxLatitude = math.Min(box.MaxLatitude, 90)
+		return box
+	}
+
+	deltaLongitude := math.Asin(math.Min(1, math.Sin(radius/earthRadius)/math.Cos(latitude*math.Pi/180))) *
+		180 / math.Pi
+	if deltaLongitude >= 180 {
+		return box
+	}
+	box.MinLongitude = normalizeLongitude(longitude - deltaLongitude)
+	box.MaxLongitude = normalizeLongitude(longitude + deltaLongitude)
+	return box
+}
+
+func normalizeLongitude(longitude float64) float64 {
+	if longitude < -180 {
+		return longitude + 360
+	}
+	if longitude > 180 {
+		return longitude - 360
+	}
+	return longitude
+}
+
+// MachineSearch is search of machines around the point. Empty Operation means
+// machines supporting any operation.
+type MachineSearch struct {
+	Latitude  float64
+	Longitude float64
+	Radius    float64
+	Operation string
+	Limit     int
+}
+
+// NearbyMachine is active machine with its current state as known by the bank.
+// Distance is in meters from the point of the search.
+type NearbyMachine struct {
+	Machine
+	Online   bool    `db:"online"`
+	Faulty   bool    `db:"faulty"`
+	HasCash  bool    `db:"has_cash"`
+	LowCash  bool    `db:"low_cash"`
+	Distance float64 `db:"-"`
+}
+
+// Available reports whether the operation can be done at the machine right now.
+func (m *NearbyMachine) Available(operation string) bool {
+	if !m.Online || m.Faulty || !m.Supports(operation) {
+		return false
+	}
+	return operation != MachineOperationCashOut || m.HasCash
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MachineSessionClosed   = "closed"
+	MachineSessionTimeout  = "timeout"
+	MachineSessionReplaced = "replaced"
+)
+
+// Channels the machine session is opened through: REST API of the machine or
+// ISO 8583 acquirer gateway.
+const (
+	MachineSessionChannelMachine = "machine"
+	MachineSessionChannelGateway = "gateway"
+)
+
+const (
+	JournalSessionOpen   = "session_open"
+	JournalBalance       = "balance"
+	JournalMiniStatement = "mini_statement"
+	JournalCashOut       = "cash_out"
+	JournalDeposit       = "deposit"
+	JournalSessionClose  = "session_close"
+	// JournalReversal returns cash out the machine failed to dispense
+	JournalReversal = "reversal"
+)
+
+const (
+	JournalApproved = "approved"
+	JournalDeclined = "declined"
+)
+
+// MachineSession is service of one card at the machine, from the card is
+// authenticated until it's returned. The session is bound to the machine which
+// opened it and expires after idle timeout, ExpiresAt is set by service.
+// Channel is the way the session is opened, see MachineSessionChannelMachine.
+type MachineSession struct {
+	Id             uuid.UUID  `db:"id"`
+	MachineId      uuid.UUID  `db:"machine_id"`
+	Channel        string     `db:"channel"`
+	CardId         uuid.UUID  `db:"card_id"`
+	UserId         uuid.UUID  `db:"user_id"`
+	AccountId      uuid.UUID  `db:"account_id"`
+	OpenedAt       time.Time  `db:"opened_at"`
+	LastActivityAt time.Time  `db:"last_activity_at"`
+	ClosedAt       *time.Time `db:"closed_at"`
+	CloseReason    *string    `db:"close_reason"`
+	ExpiresAt      time.Time  `db:"-"`
+}
+
+// JournalEntry is operation made at the machine, either in the card session,
+// by cashout code or deposit by third party to the account of someone else.
+// Notes are dispensed or accepted by the machine. Reason is set for declined
+// operations. Reference is assigned to the operation by the machine, it's
+// unique among approved operations of the machine. Flagged operations are
+// waiting for review.
+type JournalEntry struct {
+	Id            uuid.UUID     `db:"id"`
+	SessionId     uuid.NullUUID `db:"session_id"`
+	CashoutCodeId uuid.NullUUID `db:"cashout_code_id"`
+	MachineId     uuid.UUID     `db:"machine_id"`
+	AccountId     uuid.UUID     `db:"account_id"`
+	Operation     string        `db:"operation"`
+	Amount        int           `db:"amount"`
+	Notes         CashLevels    `db:"notes"`
+	Outcome       string        `db:"outcome"`
+	Reason        *string       `db:"reason"`
+	Reference     *string       `db:"reference"`
+	ThirdParty    bool          `db:"third_party"`
+	Flagged       bool          `db:"flagged"`
+	CreatedAt     time.Time     `db:"created_at"`
+}
+
+// JournalTotals sums up cash operations of the machine over a period.
+type JournalTotals struct {
+	Dispensed      int `db:"dispensed"`
+	DispensedCount int `db:"dispensed_count"`
+	Deposited      int `db:"deposited"`
+	DepositedCount int `db:"deposited_count"`
+	DeclinedCount  int `db:"declined_count"`
+}
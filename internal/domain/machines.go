@@ -1,7 +1,106 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"time"
+	"unicode/utf8"
 
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	MachineStatusActive         = "active"
+	MachineStatusMaintenance    = "maintenance"
+	MachineStatusDecommissioned = "decommissioned"
+)
+
+const (
+	MachineOperationCashOut = "cash_out"
+	MachineOperationDeposit = "deposit"
+)
+
+// ValidateMachineStatus accepts statuses which can be set by update, machine is
+// decommissioned only once and separately.
+func ValidateMachineStatus(status string) bool {
+	return status == MachineStatusActive || status == MachineStatusMaintenance
+}
+
+func ValidateMachineOperations(operations []string) bool {
+	if len(operations) == 0 {
+		return false
+	}
+	seen := make(map[string]bool, len(operations))
+	for _, operation := range operations {
+		switch operation {
+		case MachineOperationCashOut, MachineOperationDeposit:
+		default:
+			return false
+		}
+		if seen[operation] {
+			return false
+		}
+		seen[operation] = true
+	}
+	return true
+}
+
+func ValidateMachineName(name string) bool {
+	length := utf8.RuneCountInString(name)
+	return length >= 1 && length <= 128
+}
+
+func ValidateMachineAddress(address string) bool {
+	length := utf8.RuneCountInString(address)
+	return length >= 1 && length <= 256
+}
+
+func ValidateCoordinates(latitude float64, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+// ValidateCurrency checks ISO 4217 alphabetic code.
+func ValidateCurrency(currency string) bool {
+	if len(currency) != 3 {
+		return false
+	}
+	for _, c := range currency {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Machine is ATM of the bank. TokenId is id of the only valid machine token.
 type Machine struct {
-	Id uuid.UUID `db:"id"`
+	Id               uuid.UUID      `db:"id"`
+	TokenId          uuid.NullUUID  `db:"token_id"`
+	Name             string         `db:"name"`
+	Address          string         `db:"address"`
+	Latitude         float64        `db:"latitude"`
+	Longitude        float64        `db:"longitude"`
+	Operations       pq.StringArray `db:"operations"`
+	Currency         string         `db:"currency"`
+	Status           string         `db:"status"`
+	CreatedAt        time.Time      `db:"created_at"`
+	DecommissionedAt *time.Time     `db:"decommissioned_at"`
+}
+
+func (m *Machine) Supports(operation string) bool {
+	for _, op := range m.Operations {
+		if op == operation {
+			return true
+		}
+	}
+	return false
+}
+
+type MachineUpdate struct {
+	Name       *string
+	Address    *string
+	Latitude   *float64
+	Longitude  *float64
+	Operations *[]string
+	Currency   *string
+	Status     *string
 }
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func ValidatePasskeyName(name string) bool {
+	length := utf8.RuneCountInString(name)
+	return length >= 1 && length <= 64
+}
+
+// Passkey is WebAuthn credential of the user. SignCount is the last signature
+// counter reported by the authenticator, it must grow with every assertion.
+type Passkey struct {
+	Id              uuid.UUID      `db:"id"`
+	UserId          uuid.UUID      `db:"user_id"`
+	Name            string         `db:"name"`
+	CredentialId    []byte         `db:"credential_id"`
+	PublicKey       []byte         `db:"public_key"`
+	AttestationType string         `db:"attestation_type"`
+	AAGUID          []byte         `db:"aaguid"`
+	SignCount       int64          `db:"sign_count"`
+	Transports      pq.StringArray `db:"transports"`
+	BackupEligible  bool           `db:"backup_eligible"`
+	LastUsedAt      *time.Time     `db:"last_used_at"`
+	CreatedAt       time.Time      `db:"created_at"`
+}
+
+// PasskeyCeremony holds options for navigator.credentials API of the browser and
+// token identifying the ceremony when the browser finishes it.
+type PasskeyCeremony struct {
+	Token   string
+	Options json.RawMessage
+}
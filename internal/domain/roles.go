@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RoleCustomer = "customer"
+	RoleSupport  = "support"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+func ValidateRole(role string) bool {
+	switch role {
+	case RoleCustomer, RoleSupport, RoleOperator, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// Principal is the authenticated caller. ApiKey is set when the user's api key
+// is used instead of access token.
+type Principal struct {
+	UserId    uuid.UUID
+	Role      string
+	SessionId uuid.UUID
+	ApiKey    *ApiKey
+}
+
+type AdminAction struct {
+	Id         uuid.UUID `db:"id"`
+	ActorId    uuid.UUID `db:"actor_id"`
+	Action     string    `db:"action"`
+	TargetType string    `db:"target_type"`
+	TargetId   string    `db:"target_id"`
+	Details    string    `db:"details"`
+	CreatedAt  time.Time `db:"created_at"`
+}
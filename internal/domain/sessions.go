@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Device describes client the user signs in from.
+type Device struct {
+	Ip        string
+	UserAgent string
+}
+
+// Session is created on every sign in. Access token refers to its session by id.
+type Session struct {
+	Id         uuid.UUID  `db:"id"`
+	UserId     uuid.UUID  `db:"user_id"`
+	UserAgent  string     `db:"user_agent"`
+	Ip         string     `db:"ip"`
+	CreatedAt  time.Time  `db:"created_at"`
+	LastSeenAt time.Time  `db:"last_seen_at"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+}
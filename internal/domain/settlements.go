@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MachineSettlement reconciles cash of the machine at the end of a business
+// day. Expected is inventory recorded by the bank, Counted is cash found in
+// the machine by staff. Period starts where the previous settlement ended.
+type MachineSettlement struct {
+	Id          uuid.UUID  `db:"id"`
+	MachineId   uuid.UUID  `db:"machine_id"`
+	PeriodStart time.Time  `db:"period_start"`
+	PeriodEnd   time.Time  `db:"period_end"`
+	Expected    CashLevels `db:"expected"`
+	Counted     CashLevels `db:"counted"`
+	Discrepancy bool       `db:"discrepancy"`
+	SettledBy   uuid.UUID  `db:"settled_by"`
+	CreatedAt   time.Time  `db:"created_at"`
+	JournalTotals
+}
+
+// CashDifference is counted minus expected notes of one denomination.
+type CashDifference struct {
+	Denomination int
+	Expected     int
+	Counted      int
+}
+
+func (d *CashDifference) Difference() int {
+	return d.Counted - d.Expected
+}
+
+// Differences compares expected and counted notes by denomination, highest first.
+func (s *MachineSettlement) Differences() []CashDifference {
+	byDenomination := map[int]*CashDifference{}
+	get := func(denomination int) *CashDifference {
+		difference, ok := byDenomination[denomination]
+		if !ok {
+			difference = &CashDifference{Denomination: denomination}
+			byDenomination[denomination] = difference
+		}
+		return difference
+	}
+	for _, note := range s.Expected {
+		get(note.Denomination).Expected += note.Count
+	}
+	for _, note := range s.Counted {
+		get(note.Denomination).Counted += note.Count
+	}
+
+	differences := make([]CashDifference, 0, len(byDenomination))
+	for _, difference := range byDenomination {
+		differences = append(differences, *difference)
+	}
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Denomination > differences[j].Denomination
+	})
+	return differences
+}
+
+// ExpectedAmount and CountedAmount are cash totals in money.
+func (s *MachineSettlement) ExpectedAmount() int {
+	return NotesAmount(s.Expected)
+}
+
+func (s *MachineSettlement) CountedAmount() int {
+	return NotesAmount(s.Counted)
+}
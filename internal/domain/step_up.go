@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tier of the user defines amount of transfer which requires step-up confirmation.
+const (
+	TierStandard = "standard"
+	TierPremium  = "premium"
+)
+
+var Tiers = []string{TierStandard, TierPremium}
+
+// Step-up confirmation methods. Passkey is preferred, then TOTP if the user
+// has 2FA enabled, then code sent by email.
+const (
+	StepUpMethodEmail   = "email"
+	StepUpMethodTotp    = "totp"
+	StepUpMethodPasskey = "passkey"
+)
+
+// PendingTransfer is a transfer waiting for confirmation with second factor.
+type PendingTransfer struct {
+	Id        uuid.UUID `json:"id"`
+	UserId    uuid.UUID `json:"userId"`
+	AccountId uuid.UUID `json:"accountId"`
+	To        uuid.UUID `json:"to"`
+	Amount    int       `json:"amount"`
+	Method    string    `json:"method"`
+	CodeHash  string    `json:"codeHash,omitempty"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// Ceremony is state of passkey assertion, PasskeyOptions are sent to the browser
+	Ceremony       json.RawMessage `json:"ceremony,omitempty"`
+	PasskeyOptions json.RawMessage `json:"-"`
+}
+
+// StepUpProof confirms pending operation with either code or passkey assertion.
+type StepUpProof struct {
+	Code             string
+	PasskeyAssertion []byte
+}
+
+// TransferResult holds pending transfer if the transfer requires step-up
+// confirmation, otherwise the transfer is done.
+type TransferResult struct {
+	Pending *PendingTransfer
+}
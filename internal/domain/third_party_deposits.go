@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// DepositRecipient is shown to the depositor at the machine to confirm the
+// account before cash is inserted. Name is masked, e.g. "Иван П. С.".
+type DepositRecipient struct {
+	AccountId uuid.UUID
+	Name      string
+}
+
+// MaskName leaves the name of the user and initials of the patronymic and the
+// surname.
+func MaskName(user User) string {
+	parts := []string{}
+	if user.Name != "" {
+		parts = append(parts, user.Name)
+	}
+	for _, name := range []string{user.Patronyc, user.Surname} {
+		if initial, _ := utf8.DecodeRuneInString(name); initial != utf8.RuneError {
+			parts = append(parts, string(initial)+".")
+		}
+	}
+	return strings.Join(parts, " ")
+}
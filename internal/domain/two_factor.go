@@ -0,0 +1,21 @@
+package domain
+
+import "github.com/google/uuid"
+
+type TwoFactor struct {
+	UserId  uuid.UUID `db:"user_id"`
+	Secret  string    `db:"secret"`
+	Enabled bool      `db:"enabled"`
+}
+
+type TwoFactorEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// SignInResult holds either access token or, if the user has 2FA enabled,
+// token of the second step challenge.
+type SignInResult struct {
+	AccessToken    string
+	TwoFactorToken string
+}
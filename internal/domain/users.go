@@ -1,6 +1,14 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"net/mail"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
 
 type User struct {
 	Id       uuid.UUID `db:"id"`
@@ -10,6 +18,11 @@ type User struct {
 	Email    string    `db:"email"`
 	Password string    `db:"hash_password"`
 	Verified bool      `db:"verified"`
+	Role     string    `db:"role"`
+	Tier     string    `db:"tier"`
+	// ErasedAt is set when the user closed relationship with the bank and
+	// personal data was pseudonymized
+	ErasedAt *time.Time `db:"erased_at"`
 }
 
 type UserUpdate struct {
@@ -19,12 +32,53 @@ type UserUpdate struct {
 	Email    *string
 	Password *string
 	Verified *bool
+	Role     *string
 }
 
 func (u *UserUpdate) Validate() bool {
 	if u.Surname == nil && u.Name == nil && u.Patronyc == nil && u.Email == nil &&
-		u.Password == nil && u.Verified == nil {
+		u.Password == nil && u.Verified == nil && u.Role == nil {
 		return false
 	}
 	return true
 }
+
+const (
+	maxNameLen     = 64
+	minPasswordLen = 8
+	maxPasswordLen = 128
+	maxEmailLen    = 254
+)
+
+func ValidateName(name string) bool {
+	if strings.TrimSpace(name) == "" || utf8.RuneCountInString(name) > maxNameLen {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && r != '-' && r != ' ' && r != '\'' {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidatePatronyc allows empty patronyc, not everyone has one.
+func ValidatePatronyc(patronyc string) bool {
+	return patronyc == "" || ValidateName(patronyc)
+}
+
+func ValidateEmail(email string) bool {
+	if len(email) > maxEmailLen {
+		return false
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return false
+	}
+	return addr.Address == email
+}
+
+func ValidatePassword(password string) bool {
+	length := utf8.RuneCountInString(password)
+	return length >= minPasswordLen && length <= maxPasswordLen
+}
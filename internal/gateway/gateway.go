@@ -0,0 +1,247 @@
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/iso8583"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var ErrInsecureConfig = errors.New("gateway requires TLS with client certificates")
+
+// terminalConn is state of the connection. certTerminal is terminal id from
+// client certificate, terminal is set when the connection signs on.
+type terminalConn struct {
+	certTerminal string
+	terminal     string
+}
+
+type Config struct {
+	// TLS must require and verify client certificates, common name of the
+	// certificate is the terminal id the connection may sign on as
+	TLS *tls.Config
+	// LengthHeader is size of binary length prefix of messages, 2 or 4 bytes
+	LengthHeader int
+	HexBitmap    bool
+	// Messages are request MTIs served, requests of other types are declined
+	Messages []string
+	// Terminals map card acceptor terminal id (field 41) to the machine
+	Terminals map[string]uuid.UUID
+	// Currency is ISO 4217 numeric code of accounts
+	Currency string
+	// MinorUnits is exponent of amounts in messages, 2 if amounts are in kopecks
+	MinorUnits int
+	// IdleTimeout closes connection which sent nothing for the duration
+	IdleTimeout time.Duration
+}
+
+// Gateway serves ISO 8583 requests of ATMs over mutual TLS, every message is
+// framed with binary length prefix. Requests of one connection are served in
+// order. A connection serves one terminal: it signs on with 0800 as the
+// terminal of its client certificate and other requests must come from it.
+// Card requests at one machine are served one at a time, as the machine serves
+// one card at a time.
+type Gateway struct {
+	cfg      Config
+	spec     *iso8583.Spec
+	machines service.Machines
+	cards    service.Cards
+	messages map[string]bool
+	// machineLocks has lock of every machine of Terminals
+	machineLocks map[uuid.UUID]*sync.Mutex
+}
+
+func NewGateway(cfg Config, machines service.Machines, cards service.Cards) *Gateway {
+	spec := iso8583.DefaultSpec()
+	spec.HexBitmap = cfg.HexBitmap
+
+	messages := make(map[string]bool, len(cfg.Messages))
+	for _, mti := range cfg.Messages {
+		messages[mti] = true
+	}
+
+	machineLocks := make(map[uuid.UUID]*sync.Mutex, len(cfg.Terminals))
+	for _, machineId := range cfg.Terminals {
+		machineLocks[machineId] = &sync.Mutex{}
+	}
+
+	return &Gateway{
+		cfg:          cfg,
+		spec:         spec,
+		machines:     machines,
+		cards:        cards,
+		messages:     messages,
+		machineLocks: machineLocks,
+	}
+}
+
+// Serve accepts TLS connections until ctx is done and waits for the
+// connections to be closed.
+func (g *Gateway) Serve(ctx context.Context, listener net.Listener) error {
+	if g.cfg.TLS == nil || g.cfg.TLS.ClientAuth != tls.RequireAndVerifyClientCert {
+		return ErrInsecureConfig
+	}
+	listener = tls.NewListener(listener, g.cfg.TLS)
+
+	var conns sync.WaitGroup
+	defer conns.Wait()
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		conns.Add(1)
+		go func() {
+			defer conns.Done()
+			g.serveConn(ctx, conn)
+		}()
+	}
+}
+
+func (g *Gateway) serveConn(ctx context.Context, conn net.Conn) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	remote := conn.RemoteAddr().String()
+	// a malformed request must cost only its connection, not the gateway
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("error serving gateway connection from %s: panic: %v", remote, r)
+		}
+	}()
+
+	peer, err := g.handshake(conn)
+	if err != nil {
+		logrus.Errorf("error gateway handshake with %s: %s", remote, err)
+		return
+	}
+	logrus.Printf("gateway: connection from %s as %s", remote, peer.certTerminal)
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(g.cfg.IdleTimeout)); err != nil {
+			logrus.Errorf("error setting gateway read deadline: %s", err)
+			return
+		}
+		data, err := iso8583.ReadFrame(conn, g.cfg.LengthHeader)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+				logrus.Errorf("error reading gateway message from %s: %s", remote, err)
+			}
+			return
+		}
+
+		response := g.handle(ctx, peer, data)
+		if response == nil {
+			continue
+		}
+		packed, err := g.spec.Pack(response)
+		if err != nil {
+			logrus.Errorf("error packing gateway response %s: %s", response.MTI, err)
+			continue
+		}
+		if err := iso8583.WriteFrame(conn, g.cfg.LengthHeader, packed); err != nil {
+			logrus.Errorf("error writing gateway response to %s: %s", remote, err)
+			return
+		}
+	}
+}
+
+// handle returns response to the request. Nil is returned when the request
+// can't be answered, i.e. MTI is unreadable.
+func (g *Gateway) handle(ctx context.Context, peer *terminalConn, data []byte) *iso8583.Message {
+	request, err := g.spec.Unpack(data)
+	if err != nil {
+		logrus.Errorf("error unpacking gateway request: %s", err)
+		if len(data) < 4 || !isRequestMTI(string(data[:4])) {
+			return nil
+		}
+		response := iso8583.NewMessage(iso8583.ResponseMTI(string(data[:4])))
+		response.Set(fieldResponseCode, codeFormatError)
+		return response
+	}
+	if !isRequestMTI(request.MTI) {
+		logrus.Errorf("error gateway request %s is not a request", request.MTI)
+		return nil
+	}
+
+	// repeats (last digit 1) are served as the original requests
+	mti := request.MTI[:3] + "0"
+	if !g.messages[mti] {
+		response := request.Response(echoFields...)
+		response.Set(fieldResponseCode, codeInvalidTransaction)
+		return response
+	}
+
+	if mti == "0800" {
+		return g.networkManagement(peer, request)
+	}
+	// financial requests are served only for the terminal signed on the connection
+	if peer.terminal == "" || request.Get(fieldTerminal) != peer.terminal {
+		response := request.Response(echoFields...)
+		response.Set(fieldResponseCode, codeNotPermittedTerminal)
+		return response
+	}
+
+	switch mti {
+	case "0100":
+		return g.authorize(ctx, request)
+	case "0200":
+		return g.financial(ctx, request)
+	case "0420":
+		return g.reverse(ctx, request)
+	}
+	response := request.Response(echoFields...)
+	response.Set(fieldResponseCode, codeInvalidTransaction)
+	return response
+}
+
+// isRequestMTI reports whether the MTI is a request or advice from acquirer.
+func isRequestMTI(mti string) bool {
+	if len(mti) != 4 || mti[0] != '0' {
+		return false
+	}
+	return (mti[2]-'0')%2 == 0 && (mti[3] == '0' || mti[3] == '1')
+}
+
+// handshake completes TLS handshake within idle timeout and returns state of
+// the connection authenticated by client certificate.
+func (g *Gateway) handshake(conn net.Conn) (*terminalConn, error) {
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return nil, ErrInsecureConfig
+	}
+	if err := tlsConn.SetDeadline(time.Now().Add(g.cfg.IdleTimeout)); err != nil {
+		return nil, err
+	}
+	if err := tlsConn.Handshake(); err != nil {
+		return nil, err
+	}
+	if err := tlsConn.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	certificates := tlsConn.ConnectionState().PeerCertificates
+	if len(certificates) == 0 || certificates[0].Subject.CommonName == "" {
+		return nil, ErrInsecureConfig
+	}
+	return &terminalConn{certTerminal: certificates[0].Subject.CommonName}, nil
+}
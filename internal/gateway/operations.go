@@ -0,0 +1,456 @@
+package gateway
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/iso8583"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	fieldPan                = 2
+	fieldProcessingCode     = 3
+	fieldAmount             = 4
+	fieldTransmissionTime   = 7
+	fieldStan               = 11
+	fieldLocalTime          = 12
+	fieldLocalDate          = 13
+	fieldExpiry             = 14
+	fieldTrack2             = 35
+	fieldReference          = 37
+	fieldAuthorizationId    = 38
+	fieldResponseCode       = 39
+	fieldTerminal           = 41
+	fieldCardAcceptor       = 42
+	fieldPrivateData        = 48
+	fieldCurrency           = 49
+	fieldPinBlock           = 52
+	fieldAdditionalAmounts  = 54
+	fieldNetworkInformation = 70
+	fieldReplacementAmounts = 95
+)
+
+// echoFields are returned in the response as they came in the request.
+var echoFields = []int{fieldProcessingCode, fieldAmount, fieldTransmissionTime, fieldStan, fieldLocalTime,
+	fieldLocalDate, fieldReference, fieldTerminal, fieldCardAcceptor, fieldCurrency}
+
+// Transaction types, first two digits of processing code.
+const (
+	transactionCashOut = "01"
+	transactionDeposit = "21"
+	transactionBalance = "31"
+)
+
+// Network management information codes.
+const (
+	networkSignOn  = "001"
+	networkSignOff = "002"
+	networkEcho    = "301"
+)
+
+const (
+	codeApproved             = "00"
+	codeDoNotHonor           = "05"
+	codeInvalidTransaction   = "12"
+	codeInvalidAmount        = "13"
+	codeInvalidCard          = "14"
+	codeOriginalNotFound     = "25"
+	codeFormatError          = "30"
+	codeInsufficientFunds    = "51"
+	codeExpiredCard          = "54"
+	codeIncorrectPin         = "55"
+	codeNotPermittedToHolder = "57"
+	codeNotPermittedTerminal = "58"
+	codeRestrictedCard       = "62"
+	codePinTriesExceeded     = "75"
+	codeIssuerUnavailable    = "91"
+	codeDuplicate            = "94"
+	codeSystemMalfunction    = "96"
+)
+
+var (
+	errFormat             = errors.New("request format error")
+	errInvalidAmount      = errors.New("invalid amount")
+	errUnknownTerminal    = errors.New("unknown terminal")
+	errUnsupportedRequest = errors.New("unsupported request")
+)
+
+// responseCode maps error of the request to ISO 8583 response code.
+func responseCode(err error) string {
+	switch {
+	case err == nil:
+		return codeApproved
+	case errors.Is(errFormat, err):
+		return codeFormatError
+	case errors.Is(errInvalidAmount, err), errors.Is(service.ErrAmountNotDispensable, err),
+		errors.Is(service.ErrInvalidNotes, err):
+		return codeInvalidAmount
+	case errors.Is(errUnsupportedRequest, err):
+		return codeInvalidTransaction
+	case errors.Is(errUnknownTerminal, err), errors.Is(service.ErrMachineNotFound, err),
+		errors.Is(service.ErrMachineNotActive, err), errors.Is(service.ErrOperationNotSupported, err):
+		return codeNotPermittedTerminal
+	case errors.Is(service.ErrCardInvalid, err):
+		return codeInvalidCard
+	case errors.Is(service.ErrCardExpired, err):
+		return codeExpiredCard
+	case errors.Is(service.ErrWrongPin, err):
+		return codeIncorrectPin
+	case errors.Is(service.ErrPinLocked, err):
+		return codePinTriesExceeded
+	case errors.Is(service.ErrCardBlocked, err):
+		return codeRestrictedCard
+	case errors.Is(service.ErrAccountFrozen, err):
+		return codeNotPermittedToHolder
+	case errors.Is(service.ErrInsufficientFunds, err):
+		return codeInsufficientFunds
+	case errors.Is(service.ErrAccountNotFound, err), errors.Is(service.ErrUserNotFound, err):
+		return codeDoNotHonor
+	case errors.Is(service.ErrOriginalNotFound, err):
+		return codeOriginalNotFound
+	case errors.Is(service.ErrDuplicateTransaction, err):
+		return codeDuplicate
+	case errors.Is(service.ErrMachineBusy, err), errors.Is(service.ErrMachineSessionNotFound, err):
+		return codeIssuerUnavailable
+	}
+	return codeSystemMalfunction
+}
+
+func (g *Gateway) machineId(request *iso8583.Message) (uuid.UUID, error) {
+	id, ok := g.cfg.Terminals[request.Get(fieldTerminal)]
+	if !ok {
+		return id, errUnknownTerminal
+	}
+	return id, nil
+}
+
+// amount converts amount of the request in minor units to account money.
+func (g *Gateway) amount(request *iso8583.Message) (int, error) {
+	if !request.Has(fieldAmount) {
+		return 0, errFormat
+	}
+	if request.Has(fieldCurrency) && request.Get(fieldCurrency) != g.cfg.Currency {
+		return 0, errUnsupportedRequest
+	}
+	minor, err := strconv.Atoi(request.Get(fieldAmount))
+	if err != nil {
+		return 0, errFormat
+	}
+	scale := 1
+	for i := 0; i < g.cfg.MinorUnits; i++ {
+		scale *= 10
+	}
+	if minor <= 0 || minor%scale != 0 {
+		return 0, errInvalidAmount
+	}
+	return minor / scale, nil
+}
+
+// balance formats additional amounts field with available balance of the account.
+func (g *Gateway) balance(account domain.Account) string {
+	sign := "C"
+	money := account.Money
+	if money < 0 {
+		sign = "D"
+		money = -money
+	}
+	for i := 0; i < g.cfg.MinorUnits; i++ {
+		money *= 10
+	}
+	return fmt.Sprintf("0002%s%s%012d", g.cfg.Currency, sign, money)
+}
+
+// cardCredentials reads card from PAN and expiry fields or from track 2, PIN
+// from format 0 PIN block.
+func cardCredentials(request *iso8583.Message) (domain.CardCredentials, error) {
+	var credentials domain.CardCredentials
+
+	pan, expiry := request.Get(fieldPan), request.Get(fieldExpiry)
+	if track2 := request.Get(fieldTrack2); track2 != "" {
+		separator := strings.IndexAny(track2, "=D")
+		if separator < 0 || len(track2) < separator+5 {
+			return credentials, errFormat
+		}
+		pan, expiry = track2[:separator], track2[separator+1:separator+5]
+	}
+	if pan == "" || len(expiry) != 4 || !request.Has(fieldPinBlock) {
+		return credentials, errFormat
+	}
+
+	pin, err := iso8583.DecodePinBlock([]byte(request.Get(fieldPinBlock)), pan)
+	if err != nil {
+		return credentials, errFormat
+	}
+
+	credentials.Pan = pan
+	// expiry is YYMM in ISO 8583
+	credentials.Expiry = expiry[2:] + "/" + expiry[:2]
+	credentials.Pin = pin
+	return credentials, nil
+}
+
+// parseNotes reads notes from private data as "5000x2,1000x3".
+func parseNotes(value string) ([]domain.Note, error) {
+	parts := strings.Split(value, ",")
+	notes := make([]domain.Note, len(parts))
+	for i, part := range parts {
+		denomination, count, ok := strings.Cut(part, "x")
+		if !ok {
+			return nil, errFormat
+		}
+		var err error
+		if notes[i].Denomination, err = strconv.Atoi(denomination); err != nil {
+			return nil, errFormat
+		}
+		if notes[i].Count, err = strconv.Atoi(count); err != nil {
+			return nil, errFormat
+		}
+	}
+	return notes, nil
+}
+
+func formatNotes(notes []domain.Note) string {
+	parts := make([]string, len(notes))
+	for i, note := range notes {
+		parts[i] = fmt.Sprintf("%dx%d", note.Denomination, note.Count)
+	}
+	return strings.Join(parts, ",")
+}
+
+func generateAuthorizationId() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n), nil
+}
+
+// withSession authenticates card of the request and serves it in the gateway
+// session of the machine, the session is closed afterwards. Requests at the
+// machine wait for each other, card served at the machine itself isn't cut off
+// and the request is declined instead.
+func (g *Gateway) withSession(ctx context.Context, machineId uuid.UUID, request *iso8583.Message,
+	fn func(session domain.MachineSession) error) error {
+	credentials, err := cardCredentials(request)
+	if err != nil {
+		return err
+	}
+	card, err := g.cards.AuthenticateCard(ctx, credentials)
+	if err != nil {
+		return err
+	}
+
+	lock := g.machineLocks[machineId]
+	lock.Lock()
+	defer lock.Unlock()
+
+	session, err := g.machines.OpenGatewaySession(ctx, machineId, card)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := g.machines.CloseMachineSession(ctx, machineId, session.Id); err != nil {
+			logrus.Errorf("error closing gateway machine session: %s", err)
+		}
+	}()
+	return fn(session)
+}
+
+// respond finishes the response with response code of err and authorization
+// id if the request is approved.
+func respond(response *iso8583.Message, err error) *iso8583.Message {
+	code := responseCode(err)
+	if code == codeSystemMalfunction {
+		logrus.Errorf("error serving gateway request: %s", err)
+	}
+	response.Set(fieldResponseCode, code)
+	if code == codeApproved {
+		authorizationId, err := generateAuthorizationId()
+		if err != nil {
+			logrus.Errorf("error generating authorization id: %s", err)
+			response.Set(fieldResponseCode, codeSystemMalfunction)
+			return response
+		}
+		response.Set(fieldAuthorizationId, authorizationId)
+	}
+	return response
+}
+
+// authorize serves 0100: balance inquiry, or check that cash out of the
+// amount would be approved without debiting the account.
+func (g *Gateway) authorize(ctx context.Context, request *iso8583.Message) *iso8583.Message {
+	response := request.Response(echoFields...)
+	machineId, err := g.machineId(request)
+	if err != nil {
+		return respond(response, err)
+	}
+
+	err = g.withSession(ctx, machineId, request, func(session domain.MachineSession) error {
+		switch transactionType(request) {
+		case transactionBalance:
+			account, err := g.machines.MachineBalance(ctx, machineId, session.Id)
+			if err != nil {
+				return err
+			}
+			response.Set(fieldAdditionalAmounts, g.balance(account))
+			return nil
+		case transactionCashOut:
+			amount, err := g.amount(request)
+			if err != nil {
+				return err
+			}
+			account, err := g.machines.MachineBalance(ctx, machineId, session.Id)
+			if err != nil {
+				return err
+			}
+			if account.Money < amount {
+				return service.ErrInsufficientFunds
+			}
+			response.Set(fieldAdditionalAmounts, g.balance(account))
+			return nil
+		}
+		return errUnsupportedRequest
+	})
+	return respond(response, err)
+}
+
+// financial serves 0200: cash out, deposit and balance inquiry. Notes to
+// dispense and accepted notes are passed in private data. Retrieval reference
+// number is required for cash out, it's used by reversal.
+func (g *Gateway) financial(ctx context.Context, request *iso8583.Message) *iso8583.Message {
+	response := request.Response(echoFields...)
+	machineId, err := g.machineId(request)
+	if err != nil {
+		return respond(response, err)
+	}
+
+	err = g.withSession(ctx, machineId, request, func(session domain.MachineSession) error {
+		switch transactionType(request) {
+		case transactionCashOut:
+			amount, err := g.amount(request)
+			if err != nil {
+				return err
+			}
+			reference := request.Get(fieldReference)
+			if reference == "" {
+				return errFormat
+			}
+			notes, err := g.machines.MachineCashOut(ctx, machineId, session.Id, amount, reference)
+			if err != nil {
+				return err
+			}
+			response.Set(fieldPrivateData, formatNotes(notes))
+			return nil
+		case transactionDeposit:
+			amount, err := g.amount(request)
+			if err != nil {
+				return err
+			}
+			notes, err := parseNotes(request.Get(fieldPrivateData))
+			if err != nil {
+				return err
+			}
+			if err := g.machines.MachineDeposit(ctx, machineId, session.Id, amount, notes); err != nil {
+				return err
+			}
+			account, err := g.machines.MachineBalance(ctx, machineId, session.Id)
+			if err != nil {
+				return err
+			}
+			response.Set(fieldAdditionalAmounts, g.balance(account))
+			return nil
+		case transactionBalance:
+			account, err := g.machines.MachineBalance(ctx, machineId, session.Id)
+			if err != nil {
+				return err
+			}
+			response.Set(fieldAdditionalAmounts, g.balance(account))
+			return nil
+		}
+		return errUnsupportedRequest
+	})
+	return respond(response, err)
+}
+
+// reverse serves 0420 for cash out the machine failed to dispense. The
+// original is found by retrieval reference number among operations of the
+// machine of the signed on terminal, so a terminal can reverse only its own
+// cash outs. Only full reversals are supported, partial dispense in
+// replacement amounts is declined.
+func (g *Gateway) reverse(ctx context.Context, request *iso8583.Message) *iso8583.Message {
+	response := request.Response(echoFields...)
+	machineId, err := g.machineId(request)
+	if err != nil {
+		return respond(response, err)
+	}
+	if transactionType(request) != transactionCashOut {
+		return respond(response, errUnsupportedRequest)
+	}
+	reference := request.Get(fieldReference)
+	if reference == "" {
+		return respond(response, errFormat)
+	}
+	if replacement := request.Get(fieldReplacementAmounts); replacement != "" {
+		dispensed, err := strconv.Atoi(replacement[:min(12, len(replacement))])
+		if err != nil {
+			return respond(response, errFormat)
+		}
+		if dispensed != 0 {
+			return respond(response, errUnsupportedRequest)
+		}
+	}
+
+	err = g.machines.ReverseMachineCashOut(ctx, machineId, reference)
+	return respond(response, err)
+}
+
+// networkManagement serves 0800 sign on, sign off and echo test. Sign on binds
+// the connection to the terminal, only the terminal of client certificate may
+// be signed on.
+func (g *Gateway) networkManagement(peer *terminalConn, request *iso8583.Message) *iso8583.Message {
+	response := request.Response(fieldTransmissionTime, fieldStan, fieldTerminal, fieldNetworkInformation)
+	terminal := request.Get(fieldTerminal)
+
+	switch request.Get(fieldNetworkInformation) {
+	case networkSignOn:
+		if _, err := g.machineId(request); err != nil {
+			response.Set(fieldResponseCode, responseCode(err))
+			return response
+		}
+		if terminal != peer.certTerminal || (peer.terminal != "" && peer.terminal != terminal) {
+			logrus.Errorf("error gateway sign on as %s by certificate of %s", terminal, peer.certTerminal)
+			response.Set(fieldResponseCode, codeNotPermittedTerminal)
+			return response
+		}
+		peer.terminal = terminal
+	case networkSignOff:
+		if peer.terminal == "" || terminal != peer.terminal {
+			response.Set(fieldResponseCode, codeNotPermittedTerminal)
+			return response
+		}
+		peer.terminal = ""
+	case networkEcho:
+	default:
+		response.Set(fieldResponseCode, codeInvalidTransaction)
+		return response
+	}
+	response.Set(fieldResponseCode, codeApproved)
+	return response
+}
+
+func transactionType(request *iso8583.Message) string {
+	processingCode := request.Get(fieldProcessingCode)
+	if len(processingCode) < 2 {
+		return ""
+	}
+	return processingCode[:2]
+}
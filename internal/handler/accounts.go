@@ -10,6 +10,8 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const accountMovementsDefaultLimit = 20
+
 func (h *Handler) CreateAccount(ctx echo.Context) error {
 	userId, err := h.authorization(ctx)
 	if err != nil {
@@ -54,12 +56,16 @@ func (h *Handler) GetAllAccounts(ctx echo.Context) error {
 		return httpInternalError()
 	}
 
-	accountsReturn := make([]Account, len(accounts))
-	for i, acc := range accounts {
-		accountsReturn[i] = Account{
-			Id:    acc.Id,
-			Money: int32(acc.Money),
+	accountsReturn := make([]Account, 0, len(accounts))
+	for _, acc := range accounts {
+		if !accountAllowed(ctx, acc.Id) {
+			continue
 		}
+		accountsReturn = append(accountsReturn, Account{
+			Id:     acc.Id,
+			Money:  int32(acc.Money),
+			Frozen: acc.Frozen,
+		})
 	}
 
 	return ctx.JSON(200, map[string]interface{}{
@@ -73,6 +79,10 @@ func (h *Handler) GetAccountInfo(ctx echo.Context, accountId openapi_types.UUID)
 		return err
 	}
 
+	if !accountAllowed(ctx, accountId) {
+		return httpErrAccountNotFound()
+	}
+
 	account, err := h.services.Accounts.Get(ctx.Request().Context(), userId, accountId)
 	if err != nil {
 		logrus.Errorf("error get account (handler): %s", err)
@@ -85,14 +95,60 @@ func (h *Handler) GetAccountInfo(ctx echo.Context, accountId openapi_types.UUID)
 		return httpInternalError()
 	}
 
-	return ctx.JSON(500, map[string]interface{}{
+	return ctx.JSON(200, map[string]interface{}{
 		"account": Account{
-			Id:    accountId,
-			Money: int32(account.Money),
+			Id:     accountId,
+			Money:  int32(account.Money),
+			Frozen: account.Frozen,
 		},
 	})
 }
 
+func (h *Handler) GetAccountMovements(ctx echo.Context, accountId openapi_types.UUID,
+	params GetAccountMovementsParams) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !accountAllowed(ctx, accountId) {
+		return httpErrAccountNotFound()
+	}
+
+	limit := accountMovementsDefaultLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	var offset int
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+
+	movements, err := h.services.Accounts.GetMovements(ctx.Request().Context(), userId, accountId, limit, offset)
+	if err != nil {
+		logrus.Errorf("error get account movements (handler): %s", err)
+		if errors.Is(service.ErrAccountNotFound, err) {
+			return httpErrAccountNotFound()
+		}
+		return httpInternalError()
+	}
+
+	movementsReturn := make([]AccountMovement, len(movements))
+	for i, movement := range movements {
+		movementsReturn[i] = AccountMovement{
+			Id:         movement.Id,
+			Operation:  AccountMovementOperation(movement.Operation),
+			Amount:     int32(movement.Amount),
+			ThirdParty: movement.ThirdParty,
+			CreatedAt:  movement.CreatedAt,
+		}
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"movements": movementsReturn,
+	})
+}
+
 func (h *Handler) DeleteAccount(ctx echo.Context, accountId openapi_types.UUID) error {
 	userId, err := h.authorization(ctx)
 	if err != nil {
@@ -125,24 +181,86 @@ func (h *Handler) Transfer(ctx echo.Context, accountId openapi_types.UUID) error
 	if err := ctx.Bind(&transferInfo); err != nil {
 		return httpBadRequest()
 	}
+	if !accountAllowed(ctx, accountId) || !accountAllowed(ctx, transferInfo.To) {
+		return httpErrAccountNotFound()
+	}
 
-	err = h.services.Transfer(ctx.Request().Context(), userId, accountId, transferInfo.To,
+	result, err := h.services.Transfer(ctx.Request().Context(), userId, accountId, transferInfo.To,
 		int(transferInfo.Amount))
 	if err != nil {
 		logrus.Errorf("error transfer (handler): %s", err)
-		if errors.Is(service.ErrUserNotFound, err) {
-			return httpErrUserNotFound()
+		return httpErrTransfer(err)
+	}
+
+	if result.Pending != nil {
+		pending := PendingTransfer{
+			OperationId: result.Pending.Id,
+			Method:      PendingTransferMethod(result.Pending.Method),
+			ExpiresAt:   result.Pending.ExpiresAt,
 		}
-		if errors.Is(service.ErrAccountNotFound, err) {
-			return httpErrAccountNotFound()
+		if result.Pending.PasskeyOptions != nil {
+			pending.Options = &result.Pending.PasskeyOptions
 		}
-		if errors.Is(service.ErrInsufficientFunds, err) {
-			return echo.NewHTTPError(409, "Insufficient funds in the account")
+		return ctx.JSON(202, pending)
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
+
+func (h *Handler) ConfirmTransfer(ctx echo.Context, operationId openapi_types.UUID) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+	var data StepUpConfirm
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	var proof domain.StepUpProof
+	if data.Code != nil {
+		proof.Code = *data.Code
+	}
+	if data.Credential != nil {
+		proof.PasskeyAssertion = *data.Credential
+	}
+
+	err = h.services.ConfirmTransfer(ctx.Request().Context(), userId, operationId, proof)
+	if err != nil {
+		logrus.Errorf("error confirm transfer (handler): %s", err)
+		if errors.Is(service.ErrOperationNotFound, err) {
+			return echo.NewHTTPError(404, Message{
+				Message: "Operation not found or expired",
+			})
 		}
-		return httpInternalError()
+		if errors.Is(service.ErrInvalidCode, err) || errors.Is(service.ErrPasskeyInvalid, err) {
+			return httpErrInvalidCode()
+		}
+		if errors.Is(service.ErrTooManyRequests, err) {
+			return httpTooManyRequests()
+		}
+		return httpErrTransfer(err)
 	}
 
 	return ctx.JSON(200, Message{
 		Message: "ok",
 	})
 }
+
+func httpErrTransfer(err error) error {
+	if errors.Is(service.ErrUserNotFound, err) {
+		return httpErrUserNotFound()
+	}
+	if errors.Is(service.ErrAccountNotFound, err) {
+		return httpErrAccountNotFound()
+	}
+	if errors.Is(service.ErrInsufficientFunds, err) {
+		return echo.NewHTTPError(409, "Insufficient funds in the account")
+	}
+	if errors.Is(service.ErrAccountFrozen, err) {
+		return httpErrAccountFrozen()
+	}
+	return httpInternalError()
+}
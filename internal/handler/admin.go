@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/sirupsen/logrus"
+)
+
+const adminSearchUsersDefaultLimit = 20
+
+func (h *Handler) AdminSearchUsers(ctx echo.Context, params AdminSearchUsersParams) error {
+	actorId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var query string
+	if params.Query != nil {
+		query = *params.Query
+	}
+	limit := adminSearchUsersDefaultLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	var offset int
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+
+	users, err := h.services.Admin.SearchUsers(ctx.Request().Context(), actorId, query, limit, offset)
+	if err != nil {
+		logrus.Errorf("error admin search users (handler): %s", err)
+		return httpInternalError()
+	}
+
+	usersReturn := make([]User, len(users))
+	for i, user := range users {
+		usersReturn[i] = toUser(user)
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"users": usersReturn,
+	})
+}
+
+func (h *Handler) AdminGetUserAccounts(ctx echo.Context, userId openapi_types.UUID) error {
+	actorId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := h.services.Admin.GetUserAccounts(ctx.Request().Context(), actorId, userId)
+	if err != nil {
+		logrus.Errorf("error admin get user accounts (handler): %s", err)
+		if errors.Is(service.ErrUserNotFound, err) {
+			return httpErrUserNotFound()
+		}
+		return httpInternalError()
+	}
+
+	accountsReturn := make([]Account, len(accounts))
+	for i, acc := range accounts {
+		accountsReturn[i] = Account{
+			Id:     acc.Id,
+			Money:  int32(acc.Money),
+			Frozen: acc.Frozen,
+		}
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"accounts": accountsReturn,
+	})
+}
+
+func (h *Handler) AdminResendVerify(ctx echo.Context, userId openapi_types.UUID) error {
+	actorId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.services.Admin.ResendVerification(ctx.Request().Context(), actorId, userId)
+	if err != nil {
+		logrus.Errorf("error admin resend verification (handler): %s", err)
+		if errors.Is(service.ErrUserNotFound, err) {
+			return httpErrUserNotFound()
+		}
+		if errors.Is(service.ErrEmailAlreadyVerified, err) {
+			return echo.NewHTTPError(409, Message{
+				Message: "Email already verified",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
+
+func (h *Handler) AdminSetRole(ctx echo.Context, userId openapi_types.UUID) error {
+	actorId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data AdminSetRoleJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	err = h.services.Admin.SetRole(ctx.Request().Context(), actorId, userId, string(data.Role))
+	if err != nil {
+		logrus.Errorf("error admin set role (handler): %s", err)
+		if errors.Is(service.ErrInvalidRole, err) {
+			return echo.NewHTTPError(400, Message{
+				Message: "Invalid role",
+			})
+		}
+		if errors.Is(service.ErrUserNotFound, err) {
+			return httpErrUserNotFound()
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
+
+func (h *Handler) AdminFreezeAccount(ctx echo.Context, accountId openapi_types.UUID) error {
+	actorId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.services.Admin.FreezeAccount(ctx.Request().Context(), actorId, accountId)
+	if err != nil {
+		logrus.Errorf("error admin freeze account (handler): %s", err)
+		if errors.Is(service.ErrAccountNotFound, err) {
+			return httpErrAccountNotFound()
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
+
+func (h *Handler) AdminUnfreezeAccount(ctx echo.Context, accountId openapi_types.UUID) error {
+	actorId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.services.Admin.UnfreezeAccount(ctx.Request().Context(), actorId, accountId)
+	if err != nil {
+		logrus.Errorf("error admin unfreeze account (handler): %s", err)
+		if errors.Is(service.ErrAccountNotFound, err) {
+			return httpErrAccountNotFound()
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
+
+func toUser(user domain.User) User {
+	return User{
+		Id:       user.Id,
+		Email:    openapi_types.Email(user.Email),
+		Surname:  user.Surname,
+		Name:     user.Name,
+		Patronyc: user.Patronyc,
+		Verified: user.Verified,
+		Role:     Role(user.Role),
+	}
+}
@@ -0,0 +1,331 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/sirupsen/logrus"
+)
+
+const adminMachinesDefaultLimit = 20
+
+func toMachine(machine domain.Machine) Machine {
+	operations := make([]MachineOperation, len(machine.Operations))
+	for i, operation := range machine.Operations {
+		operations[i] = MachineOperation(operation)
+	}
+	return Machine{
+		Id:               machine.Id,
+		Name:             machine.Name,
+		Address:          machine.Address,
+		Latitude:         machine.Latitude,
+		Longitude:        machine.Longitude,
+		Operations:       operations,
+		Currency:         machine.Currency,
+		Status:           MachineStatus(machine.Status),
+		CreatedAt:        machine.CreatedAt,
+		DecommissionedAt: machine.DecommissionedAt,
+	}
+}
+
+func fromMachineOperations(operations []MachineOperation) []string {
+	result := make([]string, len(operations))
+	for i, operation := range operations {
+		result[i] = string(operation)
+	}
+	return result
+}
+
+func httpErrMachine(err error) error {
+	if errors.Is(service.ErrMachineNotFound, err) {
+		return echo.NewHTTPError(404, Message{
+			Message: "Machine not found",
+		})
+	}
+	if errors.Is(service.ErrMachineDecommissioned, err) {
+		return echo.NewHTTPError(409, Message{
+			Message: "Machine is decommissioned",
+		})
+	}
+	if errors.Is(service.ErrInvalidNotes, err) {
+		return echo.NewHTTPError(400, Message{
+			Message: "Invalid notes",
+		})
+	}
+	if errors.Is(service.ErrInvalidMachine, err) {
+		return echo.NewHTTPError(400, Message{
+			Message: "Invalid machine data",
+		})
+	}
+	return httpInternalError()
+}
+
+func (h *Handler) AdminGetMachines(ctx echo.Context, params AdminGetMachinesParams) error {
+	if _, err := h.authorization(ctx); err != nil {
+		return err
+	}
+
+	var status string
+	if params.Status != nil {
+		status = string(*params.Status)
+	}
+	limit := adminMachinesDefaultLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	var offset int
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+
+	machines, err := h.services.Admin.GetMachines(ctx.Request().Context(), status, limit, offset)
+	if err != nil {
+		logrus.Errorf("error admin get machines (handler): %s", err)
+		return httpInternalError()
+	}
+
+	machinesReturn := make([]Machine, len(machines))
+	for i, machine := range machines {
+		machinesReturn[i] = toMachine(machine)
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"machines": machinesReturn,
+	})
+}
+
+func (h *Handler) AdminCreateMachine(ctx echo.Context) error {
+	actorId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data AdminCreateMachineJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+	machine := domain.Machine{
+		Name:       data.Name,
+		Address:    data.Address,
+		Latitude:   data.Latitude,
+		Longitude:  data.Longitude,
+		Operations: fromMachineOperations(data.Operations),
+		Currency:   data.Currency,
+	}
+	if data.Status != nil {
+		machine.Status = string(*data.Status)
+	}
+
+	machine, token, err := h.services.Admin.CreateMachine(ctx.Request().Context(), actorId, machine)
+	if err != nil {
+		logrus.Errorf("error admin create machine (handler): %s", err)
+		return httpErrMachine(err)
+	}
+
+	return ctx.JSON(201, MachineCredential{
+		Machine: toMachine(machine),
+		Token:   token,
+	})
+}
+
+func (h *Handler) AdminUpdateMachine(ctx echo.Context, machineId openapi_types.UUID) error {
+	actorId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data AdminUpdateMachineJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+	update := domain.MachineUpdate{
+		Name:      data.Name,
+		Address:   data.Address,
+		Latitude:  data.Latitude,
+		Longitude: data.Longitude,
+		Currency:  data.Currency,
+	}
+	if data.Operations != nil {
+		operations := fromMachineOperations(*data.Operations)
+		update.Operations = &operations
+	}
+	if data.Status != nil {
+		status := string(*data.Status)
+		update.Status = &status
+	}
+
+	machine, err := h.services.Admin.UpdateMachine(ctx.Request().Context(), actorId, machineId, update)
+	if err != nil {
+		logrus.Errorf("error admin update machine (handler): %s", err)
+		return httpErrMachine(err)
+	}
+
+	return ctx.JSON(200, toMachine(machine))
+}
+
+func (h *Handler) AdminDecommissionMachine(ctx echo.Context, machineId openapi_types.UUID) error {
+	actorId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.services.Admin.DecommissionMachine(ctx.Request().Context(), actorId, machineId)
+	if err != nil {
+		logrus.Errorf("error admin decommission machine (handler): %s", err)
+		return httpErrMachine(err)
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
+
+func (h *Handler) AdminResetMachineToken(ctx echo.Context, machineId openapi_types.UUID) error {
+	actorId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	token, err := h.services.Admin.ResetMachineToken(ctx.Request().Context(), actorId, machineId)
+	if err != nil {
+		logrus.Errorf("error admin reset machine token (handler): %s", err)
+		return httpErrMachine(err)
+	}
+
+	return ctx.JSON(200, ReturnToken{
+		Token: token,
+	})
+}
+
+func toCassettes(cassettes []domain.Cassette) []Cassette {
+	cassettesReturn := make([]Cassette, len(cassettes))
+	for i, cassette := range cassettes {
+		cassettesReturn[i] = Cassette{
+			Denomination: cassette.Denomination,
+			Count:        cassette.Count,
+			LowThreshold: cassette.LowThreshold,
+			Low:          cassette.Low(),
+			UpdatedAt:    cassette.UpdatedAt,
+		}
+	}
+	return cassettesReturn
+}
+
+func (h *Handler) AdminGetCassettes(ctx echo.Context, machineId openapi_types.UUID) error {
+	if _, err := h.authorization(ctx); err != nil {
+		return err
+	}
+
+	cassettes, err := h.services.Admin.GetCassettes(ctx.Request().Context(), machineId)
+	if err != nil {
+		logrus.Errorf("error admin get cassettes (handler): %s", err)
+		return httpErrMachine(err)
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"cassettes": toCassettes(cassettes),
+	})
+}
+
+func (h *Handler) AdminReplenishCassettes(ctx echo.Context, machineId openapi_types.UUID) error {
+	actorId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data AdminReplenishCassettesJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	cassettes, err := h.services.Admin.ReplenishCassettes(ctx.Request().Context(), actorId, machineId,
+		fromNotes(data.Notes))
+	if err != nil {
+		logrus.Errorf("error admin replenish cassettes (handler): %s", err)
+		return httpErrMachine(err)
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"cassettes": toCassettes(cassettes),
+	})
+}
+
+func (h *Handler) AdminCountCassettes(ctx echo.Context, machineId openapi_types.UUID) error {
+	actorId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data AdminCountCassettesJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+	counted := make([]domain.Cassette, len(data.Cassettes))
+	for i, cassette := range data.Cassettes {
+		counted[i] = domain.Cassette{
+			Denomination: cassette.Denomination,
+			Count:        cassette.Count,
+			LowThreshold: cassette.LowThreshold,
+		}
+	}
+
+	cassettes, err := h.services.Admin.CountCassettes(ctx.Request().Context(), actorId, machineId, counted)
+	if err != nil {
+		logrus.Errorf("error admin count cassettes (handler): %s", err)
+		return httpErrMachine(err)
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"cassettes": toCassettes(cassettes),
+	})
+}
+
+func (h *Handler) AdminGetFleetHealth(ctx echo.Context) error {
+	if _, err := h.authorization(ctx); err != nil {
+		return err
+	}
+
+	fleet, err := h.services.Admin.GetFleetHealth(ctx.Request().Context())
+	if err != nil {
+		logrus.Errorf("error admin get fleet health (handler): %s", err)
+		return httpInternalError()
+	}
+
+	machines := make([]MachineHealth, len(fleet.Machines))
+	for i, machine := range fleet.Machines {
+		cashLevels := make([]CashLevel, len(machine.CashLevels))
+		for j, level := range machine.CashLevels {
+			cashLevels[j] = CashLevel{
+				Denomination: level.Denomination,
+				Count:        level.Count,
+			}
+		}
+		machines[i] = MachineHealth{
+			MachineId:       machine.MachineId,
+			Name:            machine.Name,
+			Status:          MachineStatus(machine.Status),
+			Dispenser:       MachineHealthDispenser(machine.Dispenser),
+			CardReader:      MachineHealthCardReader(machine.CardReader),
+			SoftwareVersion: machine.SoftwareVersion,
+			CashLevels:      cashLevels,
+			LowCash:         machine.LowCash,
+			Online:          machine.Online,
+			LastSeenAt:      machine.LastSeenAt,
+			OfflineSince:    machine.OfflineSince,
+		}
+	}
+
+	return ctx.JSON(200, FleetHealth{
+		Total:       fleet.Total,
+		Online:      fleet.Online,
+		Offline:     fleet.Offline,
+		NeverSeen:   fleet.NeverSeen,
+		Maintenance: fleet.Maintenance,
+		Faulty:      fleet.Faulty,
+		LowCash:     fleet.LowCash,
+		Machines:    machines,
+	})
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/sirupsen/logrus"
+)
+
+func (h *Handler) GetApiKeys(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys, err := h.services.ApiKeys.GetApiKeys(ctx.Request().Context(), userId)
+	if err != nil {
+		logrus.Errorf("error get api keys (handler): %s", err)
+		return httpInternalError()
+	}
+
+	keysReturn := make([]ApiKey, len(keys))
+	for i, key := range keys {
+		keysReturn[i] = toApiKey(key)
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"apiKeys": keysReturn,
+	})
+}
+
+func (h *Handler) CreateApiKey(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data CreateApiKeyJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	key := domain.ApiKey{
+		Name:      data.Name,
+		Scopes:    make([]string, len(data.Scopes)),
+		ExpiresAt: data.ExpiresAt,
+	}
+	for i, scope := range data.Scopes {
+		key.Scopes[i] = string(scope)
+	}
+	if data.AccountId != nil {
+		key.AccountId = uuid.NullUUID{UUID: *data.AccountId, Valid: true}
+	}
+
+	key, secret, err := h.services.ApiKeys.CreateApiKey(ctx.Request().Context(), userId, key)
+	if err != nil {
+		logrus.Errorf("error create api key (handler): %s", err)
+		if errors.Is(service.ErrInvalidApiKeyName, err) {
+			return echo.NewHTTPError(400, Message{
+				Message: "Name must be from 1 to 64 characters",
+			})
+		}
+		if errors.Is(service.ErrInvalidScopes, err) {
+			return echo.NewHTTPError(400, Message{
+				Message: "Invalid scopes",
+			})
+		}
+		if errors.Is(service.ErrInvalidExpiry, err) {
+			return echo.NewHTTPError(400, Message{
+				Message: "Expiry must be in the future",
+			})
+		}
+		if errors.Is(service.ErrAccountNotFound, err) {
+			return httpErrAccountNotFound()
+		}
+		if errors.Is(service.ErrTooManyApiKeys, err) {
+			return echo.NewHTTPError(409, Message{
+				Message: "Too many api keys",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, ApiKeyCreated{
+		Key:    secret,
+		ApiKey: toApiKey(key),
+	})
+}
+
+func (h *Handler) DeleteApiKey(ctx echo.Context, keyId openapi_types.UUID) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.services.ApiKeys.DeleteApiKey(ctx.Request().Context(), userId, keyId)
+	if err != nil {
+		logrus.Errorf("error delete api key (handler): %s", err)
+		if errors.Is(service.ErrApiKeyNotFound, err) {
+			return echo.NewHTTPError(404, Message{
+				Message: "Api key not found",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
+
+func toApiKey(key domain.ApiKey) ApiKey {
+	apiKey := ApiKey{
+		Id:         key.Id,
+		Name:       key.Name,
+		Scopes:     make([]ApiKeyScope, len(key.Scopes)),
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+	for i, scope := range key.Scopes {
+		apiKey.Scopes[i] = ApiKeyScope(scope)
+	}
+	if key.AccountId.Valid {
+		apiKey.AccountId = &key.AccountId.UUID
+	}
+	return apiKey
+}
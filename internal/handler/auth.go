@@ -25,6 +25,9 @@ func (h *Handler) SignUp(ctx echo.Context) error {
 	})
 	if err != nil {
 		logrus.Errorf("error signup (handler): %s", err)
+		if httpErr := httpErrInvalidUserData(err); httpErr != nil {
+			return httpErr
+		}
 		if errors.Is(service.ErrEmailAlreadyInUse, err) {
 			return ctx.JSON(409, Message{
 				Message: "Email already in use",
@@ -44,9 +47,14 @@ func (h *Handler) SignIn(ctx echo.Context) error {
 		return httpBadRequest()
 	}
 
-	token, err := h.services.Auth.SignIn(ctx.Request().Context(), string(user.Email), user.Password)
+	result, err := h.services.Auth.SignIn(ctx.Request().Context(), string(user.Email), user.Password,
+		device(ctx))
 	if err != nil {
 		logrus.Errorf("error sign up (handler): %s", err)
+		var lockedErr *service.LockedError
+		if errors.As(err, &lockedErr) {
+			return httpErrLocked(ctx, lockedErr.RetryAfter)
+		}
 		if errors.Is(service.ErrInvalidEmailOrPassword, err) {
 			return ctx.JSON(401, Message{
 				Message: "Invalid email or password",
@@ -55,8 +63,14 @@ func (h *Handler) SignIn(ctx echo.Context) error {
 		return httpInternalError()
 	}
 
+	if result.TwoFactorToken != "" {
+		return ctx.JSON(202, TwoFactorChallenge{
+			TwoFactorToken: result.TwoFactorToken,
+		})
+	}
+
 	return ctx.JSON(200, ReturnToken{
-		Token: token,
+		Token: result.AccessToken,
 	})
 }
 
@@ -83,6 +97,7 @@ func (h *Handler) GetMe(ctx echo.Context) error {
 			Name:     user.Name,
 			Patronyc: user.Patronyc,
 			Verified: user.Verified,
+			Role:     Role(user.Role),
 		},
 	})
 }
@@ -134,3 +149,159 @@ func (h *Handler) ResendVerify(ctx echo.Context) error {
 		Message: "ok",
 	})
 }
+
+func (h *Handler) RequestPasswordReset(ctx echo.Context) error {
+	var data PasswordResetRequest
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	err := h.services.Auth.RequestPasswordReset(ctx.Request().Context(), string(data.Email),
+		ctx.RealIP())
+	if err != nil {
+		logrus.Errorf("error request password reset (handler): %s", err)
+		if errors.Is(service.ErrTooManyRequests, err) {
+			return httpTooManyRequests()
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
+
+func (h *Handler) ConfirmPasswordReset(ctx echo.Context) error {
+	var data PasswordResetConfirm
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+	if data.Token == "" {
+		return httpBadRequest()
+	}
+
+	err := h.services.Auth.ConfirmPasswordReset(ctx.Request().Context(), data.Token, data.Password)
+	if err != nil {
+		logrus.Errorf("error confirm password reset (handler): %s", err)
+		if httpErr := httpErrInvalidUserData(err); httpErr != nil {
+			return httpErr
+		}
+		if errors.Is(service.ErrTokenInvalid, err) {
+			return echo.NewHTTPError(401, Message{
+				Message: "Token is invalid or expired",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
+
+func (h *Handler) UpdateMe(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data UpdateMeJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	update := domain.UserUpdate{
+		Surname:  data.Surname,
+		Name:     data.Name,
+		Patronyc: data.Patronyc,
+	}
+	if data.Email != nil {
+		email := string(*data.Email)
+		update.Email = &email
+	}
+
+	user, err := h.services.Auth.UpdateProfile(ctx.Request().Context(), userId, update)
+	if err != nil {
+		logrus.Errorf("error update profile (handler): %s", err)
+		if httpErr := httpErrInvalidUserData(err); httpErr != nil {
+			return httpErr
+		}
+		if errors.Is(service.ErrUserNotFound, err) {
+			return httpErrUserNotFound()
+		}
+		if errors.Is(service.ErrEmailAlreadyInUse, err) {
+			return echo.NewHTTPError(409, Message{
+				Message: "Email already in use",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"user": User{
+			Email:    types.Email(user.Email),
+			Id:       userId,
+			Surname:  user.Surname,
+			Name:     user.Name,
+			Patronyc: user.Patronyc,
+			Verified: user.Verified,
+			Role:     Role(user.Role),
+		},
+	})
+}
+
+func (h *Handler) ChangePassword(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data ChangePasswordJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	token, err := h.services.Auth.ChangePassword(ctx.Request().Context(), userId,
+		data.CurrentPassword, data.NewPassword, device(ctx))
+	if err != nil {
+		logrus.Errorf("error change password (handler): %s", err)
+		if httpErr := httpErrInvalidUserData(err); httpErr != nil {
+			return httpErr
+		}
+		if errors.Is(service.ErrUserNotFound, err) {
+			return httpErrUserNotFound()
+		}
+		if errors.Is(service.ErrWrongPassword, err) {
+			return echo.NewHTTPError(403, Message{
+				Message: "Wrong current password",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, ReturnToken{
+		Token: token,
+	})
+}
+
+func (h *Handler) ConfirmEmailChange(ctx echo.Context, params ConfirmEmailChangeParams) error {
+	err := h.services.Auth.ConfirmEmailChange(ctx.Request().Context(), params.Token)
+	if err != nil {
+		logrus.Errorf("error confirm email change (handler): %s", err)
+		if errors.Is(service.ErrTokenInvalid, err) {
+			return echo.NewHTTPError(401, Message{
+				Message: "Token is invalid or expired",
+			})
+		}
+		if errors.Is(service.ErrEmailAlreadyInUse, err) {
+			return echo.NewHTTPError(409, Message{
+				Message: "Email already in use",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
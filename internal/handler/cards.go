@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/sirupsen/logrus"
+)
+
+func toCard(card domain.Card) Card {
+	return Card{
+		Id:        card.Id,
+		AccountId: card.AccountId,
+		Last4:     card.Last4,
+		Expiry:    card.Expiry(),
+		Status:    CardStatus(card.Status),
+		PinLocked: card.PinLocked,
+		CreatedAt: card.CreatedAt,
+	}
+}
+
+func httpErrCardNotFound() error {
+	return echo.NewHTTPError(404, Message{
+		Message: "Card not found",
+	})
+}
+
+func httpErrCardBlocked() error {
+	return echo.NewHTTPError(403, Message{
+		Message: "Card is blocked",
+	})
+}
+
+func httpErrInvalidPin() error {
+	return echo.NewHTTPError(400, Message{
+		Message: "PIN must be 4 digits",
+	})
+}
+
+func (h *Handler) GetCards(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	cards, err := h.services.GetCards(ctx.Request().Context(), userId)
+	if err != nil {
+		logrus.Errorf("error get cards (handler): %s", err)
+		return httpInternalError()
+	}
+
+	cardsReturn := make([]Card, len(cards))
+	for i, card := range cards {
+		cardsReturn[i] = toCard(card)
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"cards": cardsReturn,
+	})
+}
+
+func (h *Handler) IssueCard(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data IssueCardJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	issued, err := h.services.IssueCard(ctx.Request().Context(), userId, data.AccountId, data.Pin)
+	if err != nil {
+		logrus.Errorf("error issue card (handler): %s", err)
+		if errors.Is(service.ErrInvalidPin, err) {
+			return httpErrInvalidPin()
+		}
+		if errors.Is(service.ErrAccountNotFound, err) {
+			return httpErrAccountNotFound()
+		}
+		if errors.Is(service.ErrAccountFrozen, err) {
+			return httpErrAccountFrozen()
+		}
+		if errors.Is(service.ErrTooManyCards, err) {
+			return echo.NewHTTPError(409, Message{
+				Message: "Too many cards for the account",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(201, IssuedCard{
+		Card: toCard(issued.Card),
+		Pan:  issued.Pan,
+	})
+}
+
+func (h *Handler) BlockCard(ctx echo.Context, cardId openapi_types.UUID) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.services.BlockCard(ctx.Request().Context(), userId, cardId)
+	if err != nil {
+		logrus.Errorf("error block card (handler): %s", err)
+		if errors.Is(service.ErrCardNotFound, err) {
+			return httpErrCardNotFound()
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
+
+func (h *Handler) SetCardPin(ctx echo.Context, cardId openapi_types.UUID) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data SetCardPinJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	err = h.services.SetCardPin(ctx.Request().Context(), userId, cardId, data.Password, data.Pin)
+	if err != nil {
+		logrus.Errorf("error set card pin (handler): %s", err)
+		if errors.Is(service.ErrInvalidPin, err) {
+			return httpErrInvalidPin()
+		}
+		if errors.Is(service.ErrWrongPassword, err) {
+			return echo.NewHTTPError(403, Message{
+				Message: "Wrong password",
+			})
+		}
+		if errors.Is(service.ErrCardBlocked, err) {
+			return httpErrCardBlocked()
+		}
+		if errors.Is(service.ErrUserNotFound, err) {
+			return httpErrUserNotFound()
+		}
+		if errors.Is(service.ErrCardNotFound, err) {
+			return httpErrCardNotFound()
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
+
+// authenticateCard checks card inserted into the machine and PIN entered by the holder.
+func (h *Handler) authenticateCard(ctx echo.Context, credentials CardCredentials) (domain.Card, error) {
+	card, err := h.services.AuthenticateCard(ctx.Request().Context(), domain.CardCredentials{
+		Pan:    credentials.Pan,
+		Expiry: credentials.Expiry,
+		Pin:    credentials.Pin,
+	})
+	if err != nil {
+		logrus.Errorf("error authenticate card (handler): %s", err)
+		if errors.Is(service.ErrCardInvalid, err) {
+			return card, echo.NewHTTPError(401, Message{
+				Message: "Card is not recognized",
+			})
+		}
+		if errors.Is(service.ErrWrongPin, err) {
+			return card, echo.NewHTTPError(401, Message{
+				Message: "Wrong PIN",
+			})
+		}
+		if errors.Is(service.ErrPinLocked, err) {
+			return card, echo.NewHTTPError(403, Message{
+				Message: "PIN is locked after too many wrong attempts",
+			})
+		}
+		if errors.Is(service.ErrCardBlocked, err) {
+			return card, httpErrCardBlocked()
+		}
+		if errors.Is(service.ErrCardExpired, err) {
+			return card, echo.NewHTTPError(403, Message{
+				Message: "Card is expired",
+			})
+		}
+		return card, httpInternalError()
+	}
+	return card, nil
+}
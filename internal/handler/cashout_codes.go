@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/sirupsen/logrus"
+)
+
+func toCashoutCode(code domain.CashoutCode) CashoutCode {
+	return CashoutCode{
+		Id:        code.Id,
+		AccountId: code.AccountId,
+		Amount:    code.Amount,
+		Status:    CashoutCodeStatus(code.Status),
+		ExpiresAt: code.ExpiresAt,
+		CreatedAt: code.CreatedAt,
+	}
+}
+
+func (h *Handler) GetCashoutCodes(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	codes, err := h.services.GetCashoutCodes(ctx.Request().Context(), userId)
+	if err != nil {
+		logrus.Errorf("error get cashout codes (handler): %s", err)
+		return httpInternalError()
+	}
+
+	codesReturn := make([]CashoutCode, len(codes))
+	for i, code := range codes {
+		codesReturn[i] = toCashoutCode(code)
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"codes": codesReturn,
+	})
+}
+
+func (h *Handler) CreateCashoutCode(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data CreateCashoutCodeJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	issued, err := h.services.CreateCashoutCode(ctx.Request().Context(), userId, data.AccountId, data.Amount)
+	if err != nil {
+		logrus.Errorf("error create cashout code (handler): %s", err)
+		if errors.Is(service.ErrInvalidAmount, err) {
+			return echo.NewHTTPError(400, Message{
+				Message: "Amount exceeds the cashout code limit",
+			})
+		}
+		if errors.Is(service.ErrAccountNotFound, err) {
+			return httpErrAccountNotFound()
+		}
+		if errors.Is(service.ErrAccountFrozen, err) {
+			return httpErrAccountFrozen()
+		}
+		if errors.Is(service.ErrInsufficientFunds, err) {
+			return echo.NewHTTPError(409, Message{
+				Message: "Insufficient funds in the account",
+			})
+		}
+		if errors.Is(service.ErrTooManyCashoutCodes, err) {
+			return echo.NewHTTPError(409, Message{
+				Message: "Too many unused cashout codes",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(201, IssuedCashoutCode{
+		CashoutCode: toCashoutCode(issued.CashoutCode),
+		Code:        issued.Code,
+		Secret:      issued.Secret,
+	})
+}
+
+func (h *Handler) CancelCashoutCode(ctx echo.Context, codeId openapi_types.UUID) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.services.CancelCashoutCode(ctx.Request().Context(), userId, codeId)
+	if err != nil {
+		logrus.Errorf("error cancel cashout code (handler): %s", err)
+		if errors.Is(service.ErrCashoutCodeNotFound, err) {
+			return echo.NewHTTPError(404, Message{
+				Message: "Cashout code not found",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
+
+func (h *Handler) RedeemCashoutCode(ctx echo.Context) error {
+	var data RedeemCashoutCodeJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	notes, err := h.services.RedeemCashoutCode(ctx.Request().Context(), machineId(ctx), data.Code, data.Secret)
+	if err != nil {
+		logrus.Errorf("error redeem cashout code (handler): %s", err)
+		if errors.Is(service.ErrCashoutCodeInvalid, err) {
+			return echo.NewHTTPError(401, Message{
+				Message: "Cashout code or secret is invalid",
+			})
+		}
+		if errors.Is(service.ErrTooManyRequests, err) {
+			return httpTooManyRequests()
+		}
+		return httpErrMachineOperation(err)
+	}
+
+	return ctx.JSON(200, CashoutResult{
+		Notes: toNotes(notes),
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/IvanMeln1k/go-bank-app-bank/pkg/tokens"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/sirupsen/logrus"
 )
 
 type Handler struct {
@@ -27,9 +28,17 @@ func NewHandler(deps Deps) *Handler {
 func (h *Handler) InitRoutes() *echo.Echo {
 	e := echo.New()
 	e.Use(middleware.CORS())
-	// e.Use(h.getUserIdentityMiddleware())
 
-	RegisterHandlers(e, h)
+	spec, err := GetSwagger()
+	if err != nil {
+		logrus.Fatalf("error loading openapi spec: %s", err)
+	}
+	router := newSpecRouter(e, spec, map[string]securityAuthenticator{
+		"BearerAuth":  h.userIdentity,
+		"ApiKeyAuth":  h.apiKeyIdentity,
+		"MachineAuth": h.machineIdentity,
+	})
+	RegisterHandlers(router, h)
 
 	return e
 }
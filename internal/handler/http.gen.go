@@ -7,11 +7,13 @@ import (
 	"bytes"
 	"compress/gzip"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
@@ -20,240 +22,2280 @@ import (
 )
 
 const (
-	BearerAuthScopes = "BearerAuth.Scopes"
+	ApiKeyAuthScopes  = "ApiKeyAuth.Scopes"
+	BearerAuthScopes  = "BearerAuth.Scopes"
+	MachineAuthScopes = "MachineAuth.Scopes"
+)
+
+// Defines values for AccountMovementOperation.
+const (
+	AccountMovementOperationCashOut  AccountMovementOperation = "cash_out"
+	AccountMovementOperationDeposit  AccountMovementOperation = "deposit"
+	AccountMovementOperationReversal AccountMovementOperation = "reversal"
+)
+
+// Defines values for ApiKeyScope.
+const (
+	AccountsRead     ApiKeyScope = "accounts:read"
+	TransactionsRead ApiKeyScope = "transactions:read"
+	TransfersWrite   ApiKeyScope = "transfers:write"
+)
+
+// Defines values for CardStatus.
+const (
+	CardStatusActive  CardStatus = "active"
+	CardStatusBlocked CardStatus = "blocked"
+)
+
+// Defines values for CashoutCodeStatus.
+const (
+	CashoutCodeStatusBlocked   CashoutCodeStatus = "blocked"
+	CashoutCodeStatusCancelled CashoutCodeStatus = "cancelled"
+	CashoutCodeStatusExpired   CashoutCodeStatus = "expired"
+	CashoutCodeStatusPending   CashoutCodeStatus = "pending"
+	CashoutCodeStatusRedeemed  CashoutCodeStatus = "redeemed"
+)
+
+// Defines values for DataExportStatusStatus.
+const (
+	Failed  DataExportStatusStatus = "failed"
+	Pending DataExportStatusStatus = "pending"
+	Ready   DataExportStatusStatus = "ready"
+)
+
+// Defines values for DeviceStatus.
+const (
+	DeviceStatusFault   DeviceStatus = "fault"
+	DeviceStatusOk      DeviceStatus = "ok"
+	DeviceStatusWarning DeviceStatus = "warning"
+)
+
+// Defines values for JournalEntryOperation.
+const (
+	JournalEntryOperationBalance       JournalEntryOperation = "balance"
+	JournalEntryOperationCashOut       JournalEntryOperation = "cash_out"
+	JournalEntryOperationDeposit       JournalEntryOperation = "deposit"
+	JournalEntryOperationMiniStatement JournalEntryOperation = "mini_statement"
+	JournalEntryOperationReversal      JournalEntryOperation = "reversal"
+	JournalEntryOperationSessionClose  JournalEntryOperation = "session_close"
+	JournalEntryOperationSessionOpen   JournalEntryOperation = "session_open"
+)
+
+// Defines values for JournalEntryOutcome.
+const (
+	Approved JournalEntryOutcome = "approved"
+	Declined JournalEntryOutcome = "declined"
+)
+
+// Defines values for MachineHealthCardReader.
+const (
+	MachineHealthCardReaderFault   MachineHealthCardReader = "fault"
+	MachineHealthCardReaderOk      MachineHealthCardReader = "ok"
+	MachineHealthCardReaderUnknown MachineHealthCardReader = "unknown"
+	MachineHealthCardReaderWarning MachineHealthCardReader = "warning"
+)
+
+// Defines values for MachineHealthDispenser.
+const (
+	Fault   MachineHealthDispenser = "fault"
+	Ok      MachineHealthDispenser = "ok"
+	Unknown MachineHealthDispenser = "unknown"
+	Warning MachineHealthDispenser = "warning"
+)
+
+// Defines values for MachineOperation.
+const (
+	MachineOperationCashOut MachineOperation = "cash_out"
+	MachineOperationDeposit MachineOperation = "deposit"
+)
+
+// Defines values for MachineStatus.
+const (
+	MachineStatusActive         MachineStatus = "active"
+	MachineStatusDecommissioned MachineStatus = "decommissioned"
+	MachineStatusMaintenance    MachineStatus = "maintenance"
+)
+
+// Defines values for MachineUpdateStatus.
+const (
+	MachineUpdateStatusActive      MachineUpdateStatus = "active"
+	MachineUpdateStatusMaintenance MachineUpdateStatus = "maintenance"
+)
+
+// Defines values for PendingTransferMethod.
+const (
+	PendingTransferMethodEmail   PendingTransferMethod = "email"
+	PendingTransferMethodPasskey PendingTransferMethod = "passkey"
+	PendingTransferMethodTotp    PendingTransferMethod = "totp"
+)
+
+// Defines values for Role.
+const (
+	Admin    Role = "admin"
+	Customer Role = "customer"
+	Operator Role = "operator"
+	Support  Role = "support"
+)
+
+// Defines values for StatementEntryOperation.
+const (
+	StatementEntryOperationCashOut StatementEntryOperation = "cash_out"
+	StatementEntryOperationDeposit StatementEntryOperation = "deposit"
 )
 
 // Account defines model for Account.
 type Account struct {
-	Id    openapi_types.UUID `json:"id"`
-	Money int32              `json:"money"`
+	Frozen bool               `json:"frozen"`
+	Id     openapi_types.UUID `json:"id"`
+	Money  int32              `json:"money"`
 }
 
+// AccountMovement defines model for AccountMovement.
+type AccountMovement struct {
+	Amount    int32              `json:"amount"`
+	CreatedAt time.Time          `json:"createdAt"`
+	Id        openapi_types.UUID `json:"id"`
+
+	// Operation reversal возвращает на счёт выдачу, которую банкомат не смог выполнить
+	Operation AccountMovementOperation `json:"operation"`
+
+	// ThirdParty Взнос сделан другим человеком
+	ThirdParty bool `json:"thirdParty"`
+}
+
+// AccountMovementOperation reversal возвращает на счёт выдачу, которую банкомат не смог выполнить
+type AccountMovementOperation string
+
+// ApiKey defines model for ApiKey.
+type ApiKey struct {
+	AccountId  *openapi_types.UUID `json:"accountId,omitempty"`
+	CreatedAt  time.Time           `json:"createdAt"`
+	ExpiresAt  *time.Time          `json:"expiresAt,omitempty"`
+	Id         openapi_types.UUID  `json:"id"`
+	LastUsedAt *time.Time          `json:"lastUsedAt,omitempty"`
+	Name       string              `json:"name"`
+	Scopes     []ApiKeyScope       `json:"scopes"`
+}
+
+// ApiKeyCreate defines model for ApiKeyCreate.
+type ApiKeyCreate struct {
+	AccountId *openapi_types.UUID `json:"accountId,omitempty"`
+	ExpiresAt *time.Time          `json:"expiresAt,omitempty"`
+	Name      string              `json:"name"`
+	Scopes    []ApiKeyScope       `json:"scopes"`
+}
+
+// ApiKeyCreated defines model for ApiKeyCreated.
+type ApiKeyCreated struct {
+	ApiKey ApiKey `json:"apiKey"`
+	Key    string `json:"key"`
+}
+
+// ApiKeyScope defines model for ApiKeyScope.
+type ApiKeyScope string
+
 // AuthSchema defines model for AuthSchema.
 type AuthSchema struct {
 	Email    openapi_types.Email `json:"email"`
 	Password string              `json:"password"`
 }
 
-// CashoutRequest defines model for CashoutRequest.
-type CashoutRequest struct {
-	Amount int32 `json:"amount"`
-}
+// Card defines model for Card.
+type Card struct {
+	AccountId openapi_types.UUID `json:"accountId"`
+	CreatedAt time.Time          `json:"createdAt"`
+
+	// Expiry Срок действия в формате MM/YY
+	Expiry string             `json:"expiry"`
+	Id     openapi_types.UUID `json:"id"`
+
+	// Last4 Последние 4 цифры номера карты
+	Last4 string `json:"last4"`
+
+	// PinLocked PIN-код заблокирован после неверных вводов, нужно сменить PIN-код
+	PinLocked bool       `json:"pinLocked"`
+	Status    CardStatus `json:"status"`
+}
+
+// CardBalance defines model for CardBalance.
+type CardBalance struct {
+	AccountId openapi_types.UUID `json:"accountId"`
+	Money     int32              `json:"money"`
+}
+
+// CardCredentials Данные карты, считанные банкоматом, и PIN-код, введённый держателем
+type CardCredentials struct {
+	Expiry string `json:"expiry"`
+	Pan    string `json:"pan"`
+	Pin    string `json:"pin"`
+}
+
+// CardIssue defines model for CardIssue.
+type CardIssue struct {
+	AccountId openapi_types.UUID `json:"accountId"`
+	Pin       string             `json:"pin"`
+}
+
+// CardPinChange defines model for CardPinChange.
+type CardPinChange struct {
+	Password string `json:"password"`
+	Pin      string `json:"pin"`
+}
+
+// CardStatus defines model for CardStatus.
+type CardStatus string
+
+// CashDifference defines model for CashDifference.
+type CashDifference struct {
+	Counted      int `json:"counted"`
+	Denomination int `json:"denomination"`
+	Difference   int `json:"difference"`
+	Expected     int `json:"expected"`
+}
+
+// CashLevel defines model for CashLevel.
+type CashLevel struct {
+	Count        int `json:"count"`
+	Denomination int `json:"denomination"`
+}
+
+// CashoutCode defines model for CashoutCode.
+type CashoutCode struct {
+	AccountId openapi_types.UUID `json:"accountId"`
+	Amount    int                `json:"amount"`
+	CreatedAt time.Time          `json:"createdAt"`
+	ExpiresAt time.Time          `json:"expiresAt"`
+	Id        openapi_types.UUID `json:"id"`
+	Status    CashoutCodeStatus  `json:"status"`
+}
+
+// CashoutCodeCreate defines model for CashoutCodeCreate.
+type CashoutCodeCreate struct {
+	AccountId openapi_types.UUID `json:"accountId"`
+	Amount    int                `json:"amount"`
+}
+
+// CashoutCodeRedemption defines model for CashoutCodeRedemption.
+type CashoutCodeRedemption struct {
+	Code   string `json:"code"`
+	Secret string `json:"secret"`
+}
+
+// CashoutCodeStatus defines model for CashoutCodeStatus.
+type CashoutCodeStatus string
+
+// CashoutRequest defines model for CashoutRequest.
+type CashoutRequest struct {
+	Amount int32 `json:"amount"`
+}
+
+// CashoutResult defines model for CashoutResult.
+type CashoutResult struct {
+	Notes []Note `json:"notes"`
+}
+
+// Cassette defines model for Cassette.
+type Cassette struct {
+	Count        int       `json:"count"`
+	Denomination int       `json:"denomination"`
+	Low          bool      `json:"low"`
+	LowThreshold int       `json:"lowThreshold"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// CassetteCount defines model for CassetteCount.
+type CassetteCount struct {
+	Cassettes []struct {
+		Count        int `json:"count"`
+		Denomination int `json:"denomination"`
+		LowThreshold int `json:"lowThreshold"`
+	} `json:"cassettes"`
+}
+
+// CassetteReplenishment defines model for CassetteReplenishment.
+type CassetteReplenishment struct {
+	Notes []Note `json:"notes"`
+}
+
+// DataExportStatus defines model for DataExportStatus.
+type DataExportStatus struct {
+	Id     openapi_types.UUID     `json:"id"`
+	Status DataExportStatusStatus `json:"status"`
+}
+
+// DataExportStatusStatus defines model for DataExportStatus.Status.
+type DataExportStatusStatus string
+
+// DepositRecipient defines model for DepositRecipient.
+type DepositRecipient struct {
+	AccountId openapi_types.UUID `json:"accountId"`
+
+	// Name Маскированное имя владельца счёта, например "Иван П. С."
+	Name string `json:"name"`
+}
+
+// DepositRequest defines model for DepositRequest.
+type DepositRequest struct {
+	Amount int32 `json:"amount"`
+
+	// Notes Принятые купюры, их сумма должна совпадать с amount
+	Notes []Note `json:"notes"`
+}
+
+// DeviceStatus defines model for DeviceStatus.
+type DeviceStatus string
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FleetHealth defines model for FleetHealth.
+type FleetHealth struct {
+	Faulty      int             `json:"faulty"`
+	LowCash     int             `json:"lowCash"`
+	Machines    []MachineHealth `json:"machines"`
+	Maintenance int             `json:"maintenance"`
+	NeverSeen   int             `json:"neverSeen"`
+	Offline     int             `json:"offline"`
+	Online      int             `json:"online"`
+	Total       int             `json:"total"`
+}
+
+// IssuedCard defines model for IssuedCard.
+type IssuedCard struct {
+	Card Card `json:"card"`
+
+	// Pan Номер карты, показывается только при выпуске
+	Pan string `json:"pan"`
+}
+
+// IssuedCashoutCode defines model for IssuedCashoutCode.
+type IssuedCashoutCode struct {
+	CashoutCode CashoutCode `json:"cashoutCode"`
+
+	// Code Код для ввода в банкомате, показывается только при создании
+	Code string `json:"code"`
+
+	// Secret Секрет, вводится в банкомате вместе с кодом
+	Secret string `json:"secret"`
+}
+
+// JournalEntry defines model for JournalEntry.
+type JournalEntry struct {
+	AccountId openapi_types.UUID `json:"accountId"`
+	Amount    int32              `json:"amount"`
+
+	// CashoutCodeId Код снятия, если наличные выданы без карты
+	CashoutCodeId *openapi_types.UUID `json:"cashoutCodeId,omitempty"`
+	CreatedAt     time.Time           `json:"createdAt"`
+
+	// Flagged Операция отмечена для проверки
+	Flagged   bool                  `json:"flagged"`
+	Id        openapi_types.UUID    `json:"id"`
+	Notes     []Note                `json:"notes"`
+	Operation JournalEntryOperation `json:"operation"`
+	Outcome   JournalEntryOutcome   `json:"outcome"`
+
+	// Reason Причина отказа
+	Reason *string `json:"reason,omitempty"`
+
+	// Reference Ссылка на операцию, присвоенная банкоматом
+	Reference *string `json:"reference,omitempty"`
+
+	// SessionId Сессия карты, если операция сделана по карте
+	SessionId *openapi_types.UUID `json:"sessionId,omitempty"`
+
+	// ThirdParty Наличные внесены на счёт третьим лицом без карты
+	ThirdParty bool `json:"thirdParty"`
+}
+
+// JournalEntryOperation defines model for JournalEntry.Operation.
+type JournalEntryOperation string
+
+// JournalEntryOutcome defines model for JournalEntry.Outcome.
+type JournalEntryOutcome string
+
+// Jwk defines model for Jwk.
+type Jwk struct {
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	X   string `json:"x"`
+}
+
+// Jwks defines model for Jwks.
+type Jwks struct {
+	Keys []Jwk `json:"keys"`
+}
+
+// Machine defines model for Machine.
+type Machine struct {
+	Address          string             `json:"address"`
+	CreatedAt        time.Time          `json:"createdAt"`
+	Currency         string             `json:"currency"`
+	DecommissionedAt *time.Time         `json:"decommissionedAt,omitempty"`
+	Id               openapi_types.UUID `json:"id"`
+	Latitude         float64            `json:"latitude"`
+	Longitude        float64            `json:"longitude"`
+	Name             string             `json:"name"`
+	Operations       []MachineOperation `json:"operations"`
+	Status           MachineStatus      `json:"status"`
+}
+
+// MachineCreate defines model for MachineCreate.
+type MachineCreate struct {
+	Address    string             `json:"address"`
+	Currency   string             `json:"currency"`
+	Latitude   float64            `json:"latitude"`
+	Longitude  float64            `json:"longitude"`
+	Name       string             `json:"name"`
+	Operations []MachineOperation `json:"operations"`
+
+	// Status Вывод из эксплуатации выполняется отдельным запросом
+	Status *MachineUpdateStatus `json:"status,omitempty"`
+}
+
+// MachineCredential defines model for MachineCredential.
+type MachineCredential struct {
+	Machine Machine `json:"machine"`
+	Token   string  `json:"token"`
+}
+
+// MachineHealth defines model for MachineHealth.
+type MachineHealth struct {
+	CardReader      MachineHealthCardReader `json:"cardReader"`
+	CashLevels      []CashLevel             `json:"cashLevels"`
+	Dispenser       MachineHealthDispenser  `json:"dispenser"`
+	LastSeenAt      *time.Time              `json:"lastSeenAt,omitempty"`
+	LowCash         bool                    `json:"lowCash"`
+	MachineId       openapi_types.UUID      `json:"machineId"`
+	Name            string                  `json:"name"`
+	OfflineSince    *time.Time              `json:"offlineSince,omitempty"`
+	Online          bool                    `json:"online"`
+	SoftwareVersion string                  `json:"softwareVersion"`
+	Status          MachineStatus           `json:"status"`
+}
+
+// MachineHealthCardReader defines model for MachineHealth.CardReader.
+type MachineHealthCardReader string
+
+// MachineHealthDispenser defines model for MachineHealth.Dispenser.
+type MachineHealthDispenser string
+
+// MachineHeartbeat defines model for MachineHeartbeat.
+type MachineHeartbeat struct {
+	CardReader DeviceStatus `json:"cardReader"`
+
+	// CashLevels Количество купюр в кассетах по данным датчиков банкомата
+	CashLevels      []CashLevel  `json:"cashLevels"`
+	Dispenser       DeviceStatus `json:"dispenser"`
+	SoftwareVersion string       `json:"softwareVersion"`
+}
+
+// MachineOperation defines model for MachineOperation.
+type MachineOperation string
+
+// MachineSession defines model for MachineSession.
+type MachineSession struct {
+	// ExpiresAt Сессия закрывается, если до этого момента не будет операций. Каждая операция продлевает сессию
+	ExpiresAt time.Time          `json:"expiresAt"`
+	Id        openapi_types.UUID `json:"id"`
+	OpenedAt  time.Time          `json:"openedAt"`
+}
+
+// MachineStatus defines model for MachineStatus.
+type MachineStatus string
+
+// MachineUpdate defines model for MachineUpdate.
+type MachineUpdate struct {
+	Address    *string             `json:"address,omitempty"`
+	Currency   *string             `json:"currency,omitempty"`
+	Latitude   *float64            `json:"latitude,omitempty"`
+	Longitude  *float64            `json:"longitude,omitempty"`
+	Name       *string             `json:"name,omitempty"`
+	Operations *[]MachineOperation `json:"operations,omitempty"`
+
+	// Status Вывод из эксплуатации выполняется отдельным запросом
+	Status *MachineUpdateStatus `json:"status,omitempty"`
+}
+
+// MachineUpdateStatus Вывод из эксплуатации выполняется отдельным запросом
+type MachineUpdateStatus string
+
+// MagicLinkRequest defines model for MagicLinkRequest.
+type MagicLinkRequest struct {
+	Email openapi_types.Email `json:"email"`
+}
+
+// MagicLinkSignIn defines model for MagicLinkSignIn.
+type MagicLinkSignIn struct {
+	Token string `json:"token"`
+}
+
+// Message defines model for Message.
+type Message struct {
+	Message string `json:"message"`
+}
+
+// NearbyMachine defines model for NearbyMachine.
+type NearbyMachine struct {
+	Address string `json:"address"`
+
+	// AvailableOperations Операции, которые сейчас можно выполнить в банкомате
+	AvailableOperations []MachineOperation `json:"availableOperations"`
+	Currency            string             `json:"currency"`
+
+	// Distance Расстояние до банкомата в метрах
+	Distance int `json:"distance"`
+
+	// HasCash В кассетах есть наличные
+	HasCash   bool               `json:"hasCash"`
+	Id        openapi_types.UUID `json:"id"`
+	Latitude  float64            `json:"latitude"`
+	Longitude float64            `json:"longitude"`
+
+	// LowCash Наличные заканчиваются
+	LowCash bool   `json:"lowCash"`
+	Name    string `json:"name"`
+
+	// Online Банкомат на связи
+	Online     bool               `json:"online"`
+	Operations []MachineOperation `json:"operations"`
+}
+
+// Note defines model for Note.
+type Note struct {
+	Count        int `json:"count"`
+	Denomination int `json:"denomination"`
+}
+
+// Passkey defines model for Passkey.
+type Passkey struct {
+	CreatedAt  time.Time          `json:"createdAt"`
+	Id         openapi_types.UUID `json:"id"`
+	LastUsedAt *time.Time         `json:"lastUsedAt,omitempty"`
+	Name       string             `json:"name"`
+}
+
+// PasskeyAssertion defines model for PasskeyAssertion.
+type PasskeyAssertion struct {
+	CeremonyToken string `json:"ceremonyToken"`
+
+	// Credential PublicKeyCredential, полученный от navigator.credentials API, в JSON
+	Credential WebAuthnCredential `json:"credential"`
+}
+
+// PasskeyCeremony defines model for PasskeyCeremony.
+type PasskeyCeremony struct {
+	CeremonyToken string `json:"ceremonyToken"`
+
+	// Options Параметры для navigator.credentials API
+	Options WebAuthnOptions `json:"options"`
+}
+
+// PasskeyReauth defines model for PasskeyReauth.
+type PasskeyReauth struct {
+	// Code Код TOTP или код восстановления, обязателен при включённой двухфакторной аутентификации
+	Code     *string `json:"code,omitempty"`
+	Password string  `json:"password"`
+}
+
+// PasskeyRegistration defines model for PasskeyRegistration.
+type PasskeyRegistration struct {
+	CeremonyToken string `json:"ceremonyToken"`
+
+	// Credential PublicKeyCredential, полученный от navigator.credentials API, в JSON
+	Credential WebAuthnCredential `json:"credential"`
+	Name       string             `json:"name"`
+}
+
+// PasswordChange defines model for PasswordChange.
+type PasswordChange struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+// PasswordConfirm defines model for PasswordConfirm.
+type PasswordConfirm struct {
+	Password string `json:"password"`
+}
+
+// PasswordResetConfirm defines model for PasswordResetConfirm.
+type PasswordResetConfirm struct {
+	Password string `json:"password"`
+	Token    string `json:"token"`
+}
+
+// PasswordResetRequest defines model for PasswordResetRequest.
+type PasswordResetRequest struct {
+	Email openapi_types.Email `json:"email"`
+}
+
+// PendingTransfer defines model for PendingTransfer.
+type PendingTransfer struct {
+	ExpiresAt   time.Time             `json:"expiresAt"`
+	Method      PendingTransferMethod `json:"method"`
+	OperationId openapi_types.UUID    `json:"operationId"`
+
+	// Options Параметры для navigator.credentials API
+	Options *WebAuthnOptions `json:"options,omitempty"`
+}
+
+// PendingTransferMethod defines model for PendingTransfer.Method.
+type PendingTransferMethod string
+
+// ProfileUpdate defines model for ProfileUpdate.
+type ProfileUpdate struct {
+	Email    *openapi_types.Email `json:"email,omitempty"`
+	Name     *string              `json:"name,omitempty"`
+	Patronyc *string              `json:"patronyc,omitempty"`
+	Surname  *string              `json:"surname,omitempty"`
+}
+
+// RecoveryCodes defines model for RecoveryCodes.
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// ReturnId defines model for ReturnId.
+type ReturnId struct {
+	Id openapi_types.UUID `json:"id"`
+}
+
+// ReturnToken defines model for ReturnToken.
+type ReturnToken struct {
+	Token string `json:"token"`
+}
+
+// Role defines model for Role.
+type Role string
+
+// RoleUpdate defines model for RoleUpdate.
+type RoleUpdate struct {
+	Role Role `json:"role"`
+}
+
+// Session defines model for Session.
+type Session struct {
+	CreatedAt  time.Time          `json:"createdAt"`
+	Current    bool               `json:"current"`
+	Id         openapi_types.UUID `json:"id"`
+	Ip         string             `json:"ip"`
+	LastSeenAt time.Time          `json:"lastSeenAt"`
+	UserAgent  string             `json:"userAgent"`
+}
+
+// Settlement defines model for Settlement.
+type Settlement struct {
+	// CountedAmount Наличные по пересчёту
+	CountedAmount int       `json:"countedAmount"`
+	CreatedAt     time.Time `json:"createdAt"`
+
+	// DeclinedCount Отклонённых выдач и взносов за период
+	DeclinedCount int `json:"declinedCount"`
+
+	// Deposited Внесено за период
+	Deposited      int              `json:"deposited"`
+	DepositedCount int              `json:"depositedCount"`
+	Differences    []CashDifference `json:"differences"`
+	Discrepancy    bool             `json:"discrepancy"`
+
+	// Dispensed Выдано за период
+	Dispensed      int `json:"dispensed"`
+	DispensedCount int `json:"dispensedCount"`
+
+	// ExpectedAmount Наличные по учёту
+	ExpectedAmount int                `json:"expectedAmount"`
+	Id             openapi_types.UUID `json:"id"`
+	MachineId      openapi_types.UUID `json:"machineId"`
+	PeriodEnd      time.Time          `json:"periodEnd"`
+	PeriodStart    time.Time          `json:"periodStart"`
+	SettledBy      openapi_types.UUID `json:"settledBy"`
+}
+
+// SettlementCount defines model for SettlementCount.
+type SettlementCount struct {
+	// Counted Купюры, найденные в банкомате. Не указанные номиналы считаются пустыми
+	Counted []struct {
+		Count        int `json:"count"`
+		Denomination int `json:"denomination"`
+	} `json:"counted"`
+}
+
+// StatementEntry defines model for StatementEntry.
+type StatementEntry struct {
+	Amount    int32                   `json:"amount"`
+	CreatedAt time.Time               `json:"createdAt"`
+	Operation StatementEntryOperation `json:"operation"`
+}
+
+// StatementEntryOperation defines model for StatementEntry.Operation.
+type StatementEntryOperation string
+
+// StepUpConfirm Для методов email и totp указывается code, для passkey — credential
+type StepUpConfirm struct {
+	Code *string `json:"code,omitempty"`
+
+	// Credential PublicKeyCredential, полученный от navigator.credentials API, в JSON
+	Credential *WebAuthnCredential `json:"credential,omitempty"`
+}
+
+// ThirdPartyDepositRequest defines model for ThirdPartyDepositRequest.
+type ThirdPartyDepositRequest struct {
+	AccountId openapi_types.UUID `json:"accountId"`
+	Amount    int32              `json:"amount"`
+
+	// Notes Принятые купюры, их сумма должна совпадать с amount
+	Notes []Note `json:"notes"`
+}
+
+// TransferInfo defines model for TransferInfo.
+type TransferInfo struct {
+	Amount int32              `json:"amount"`
+	To     openapi_types.UUID `json:"to"`
+}
+
+// TwoFactorChallenge defines model for TwoFactorChallenge.
+type TwoFactorChallenge struct {
+	TwoFactorToken string `json:"twoFactorToken"`
+}
+
+// TwoFactorCode defines model for TwoFactorCode.
+type TwoFactorCode struct {
+	Code string `json:"code"`
+}
+
+// TwoFactorEnrollment defines model for TwoFactorEnrollment.
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+}
+
+// TwoFactorSignIn defines model for TwoFactorSignIn.
+type TwoFactorSignIn struct {
+	Code           string `json:"code"`
+	TwoFactorToken string `json:"twoFactorToken"`
+}
+
+// User defines model for User.
+type User struct {
+	Email    openapi_types.Email `json:"email"`
+	Id       openapi_types.UUID  `json:"id"`
+	Name     string              `json:"name"`
+	Patronyc string              `json:"patronyc"`
+	Role     Role                `json:"role"`
+	Surname  string              `json:"surname"`
+	Verified bool                `json:"verified"`
+}
+
+// UserWithPassword defines model for UserWithPassword.
+type UserWithPassword struct {
+	Email    openapi_types.Email `json:"email"`
+	Name     string              `json:"name"`
+	Password string              `json:"password"`
+	Patronyc string              `json:"patronyc"`
+	Surname  string              `json:"surname"`
+}
+
+// ValidationError defines model for ValidationError.
+type ValidationError struct {
+	Errors  *[]FieldError `json:"errors,omitempty"`
+	Message string        `json:"message"`
+}
+
+// WebAuthnCredential PublicKeyCredential, полученный от navigator.credentials API, в JSON
+type WebAuthnCredential = json.RawMessage
+
+// WebAuthnOptions Параметры для navigator.credentials API
+type WebAuthnOptions = json.RawMessage
+
+// AdminGetMachinesParams defines parameters for AdminGetMachines.
+type AdminGetMachinesParams struct {
+	Status *MachineStatus `form:"status,omitempty" json:"status,omitempty"`
+	Limit  *int           `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset *int           `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// AdminGetMachineJournalParams defines parameters for AdminGetMachineJournal.
+type AdminGetMachineJournalParams struct {
+	From   *time.Time `form:"from,omitempty" json:"from,omitempty"`
+	To     *time.Time `form:"to,omitempty" json:"to,omitempty"`
+	Limit  *int       `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset *int       `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// AdminGetMachineSettlementsParams defines parameters for AdminGetMachineSettlements.
+type AdminGetMachineSettlementsParams struct {
+	Limit  *int `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// AdminGetSettlementsParams defines parameters for AdminGetSettlements.
+type AdminGetSettlementsParams struct {
+	Discrepancy *bool `form:"discrepancy,omitempty" json:"discrepancy,omitempty"`
+	Limit       *int  `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset      *int  `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// AdminSearchUsersParams defines parameters for AdminSearchUsers.
+type AdminSearchUsersParams struct {
+	Query  *string `form:"query,omitempty" json:"query,omitempty"`
+	Limit  *int    `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset *int    `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// GetAccountMovementsParams defines parameters for GetAccountMovements.
+type GetAccountMovementsParams struct {
+	Limit  *int `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// FindMachinesParams defines parameters for FindMachines.
+type FindMachinesParams struct {
+	Lat float64 `form:"lat" json:"lat"`
+	Lon float64 `form:"lon" json:"lon"`
+
+	// Radius Радиус поиска в метрах
+	Radius   *int              `form:"radius,omitempty" json:"radius,omitempty"`
+	Supports *MachineOperation `form:"supports,omitempty" json:"supports,omitempty"`
+	Limit    *int              `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ConfirmEmailChangeParams defines parameters for ConfirmEmailChange.
+type ConfirmEmailChangeParams struct {
+	Token string `form:"token" json:"token"`
+}
+
+// VerifyEmailParams defines parameters for VerifyEmail.
+type VerifyEmailParams struct {
+	Token string `form:"token" json:"token"`
+}
+
+// AdminCreateMachineJSONRequestBody defines body for AdminCreateMachine for application/json ContentType.
+type AdminCreateMachineJSONRequestBody = MachineCreate
+
+// AdminUpdateMachineJSONRequestBody defines body for AdminUpdateMachine for application/json ContentType.
+type AdminUpdateMachineJSONRequestBody = MachineUpdate
+
+// AdminCountCassettesJSONRequestBody defines body for AdminCountCassettes for application/json ContentType.
+type AdminCountCassettesJSONRequestBody = CassetteCount
+
+// AdminReplenishCassettesJSONRequestBody defines body for AdminReplenishCassettes for application/json ContentType.
+type AdminReplenishCassettesJSONRequestBody = CassetteReplenishment
+
+// AdminSettleMachineJSONRequestBody defines body for AdminSettleMachine for application/json ContentType.
+type AdminSettleMachineJSONRequestBody = SettlementCount
+
+// AdminSetRoleJSONRequestBody defines body for AdminSetRole for application/json ContentType.
+type AdminSetRoleJSONRequestBody = RoleUpdate
+
+// TransferJSONRequestBody defines body for Transfer for application/json ContentType.
+type TransferJSONRequestBody = TransferInfo
+
+// CreateApiKeyJSONRequestBody defines body for CreateApiKey for application/json ContentType.
+type CreateApiKeyJSONRequestBody = ApiKeyCreate
+
+// IssueCardJSONRequestBody defines body for IssueCard for application/json ContentType.
+type IssueCardJSONRequestBody = CardIssue
+
+// SetCardPinJSONRequestBody defines body for SetCardPin for application/json ContentType.
+type SetCardPinJSONRequestBody = CardPinChange
+
+// CreateCashoutCodeJSONRequestBody defines body for CreateCashoutCode for application/json ContentType.
+type CreateCashoutCodeJSONRequestBody = CashoutCodeCreate
+
+// ConfirmTransferJSONRequestBody defines body for ConfirmTransfer for application/json ContentType.
+type ConfirmTransferJSONRequestBody = StepUpConfirm
+
+// ConfirmTwoFactorJSONRequestBody defines body for ConfirmTwoFactor for application/json ContentType.
+type ConfirmTwoFactorJSONRequestBody = TwoFactorCode
+
+// DisableTwoFactorJSONRequestBody defines body for DisableTwoFactor for application/json ContentType.
+type DisableTwoFactorJSONRequestBody = TwoFactorCode
+
+// RequestMagicLinkJSONRequestBody defines body for RequestMagicLink for application/json ContentType.
+type RequestMagicLinkJSONRequestBody = MagicLinkRequest
+
+// SignInMagicLinkJSONRequestBody defines body for SignInMagicLink for application/json ContentType.
+type SignInMagicLinkJSONRequestBody = MagicLinkSignIn
+
+// UpdateMeJSONRequestBody defines body for UpdateMe for application/json ContentType.
+type UpdateMeJSONRequestBody = ProfileUpdate
+
+// EraseMeJSONRequestBody defines body for EraseMe for application/json ContentType.
+type EraseMeJSONRequestBody = PasswordConfirm
+
+// ChangePasswordJSONRequestBody defines body for ChangePassword for application/json ContentType.
+type ChangePasswordJSONRequestBody = PasswordChange
+
+// BeginPasskeyRegistrationJSONRequestBody defines body for BeginPasskeyRegistration for application/json ContentType.
+type BeginPasskeyRegistrationJSONRequestBody = PasskeyReauth
+
+// FinishPasskeyRegistrationJSONRequestBody defines body for FinishPasskeyRegistration for application/json ContentType.
+type FinishPasskeyRegistrationJSONRequestBody = PasskeyRegistration
+
+// FinishPasskeySignInJSONRequestBody defines body for FinishPasskeySignIn for application/json ContentType.
+type FinishPasskeySignInJSONRequestBody = PasskeyAssertion
+
+// DeletePasskeyJSONRequestBody defines body for DeletePasskey for application/json ContentType.
+type DeletePasskeyJSONRequestBody = PasskeyReauth
+
+// ConfirmPasswordResetJSONRequestBody defines body for ConfirmPasswordReset for application/json ContentType.
+type ConfirmPasswordResetJSONRequestBody = PasswordResetConfirm
+
+// RequestPasswordResetJSONRequestBody defines body for RequestPasswordReset for application/json ContentType.
+type RequestPasswordResetJSONRequestBody = PasswordResetRequest
+
+// SignInJSONRequestBody defines body for SignIn for application/json ContentType.
+type SignInJSONRequestBody = AuthSchema
+
+// SignInTwoFactorJSONRequestBody defines body for SignInTwoFactor for application/json ContentType.
+type SignInTwoFactorJSONRequestBody = TwoFactorSignIn
+
+// SignUpJSONRequestBody defines body for SignUp for application/json ContentType.
+type SignUpJSONRequestBody = UserWithPassword
+
+// RedeemCashoutCodeJSONRequestBody defines body for RedeemCashoutCode for application/json ContentType.
+type RedeemCashoutCodeJSONRequestBody = CashoutCodeRedemption
+
+// MachineThirdPartyDepositJSONRequestBody defines body for MachineThirdPartyDeposit for application/json ContentType.
+type MachineThirdPartyDepositJSONRequestBody = ThirdPartyDepositRequest
+
+// MachineHeartbeatJSONRequestBody defines body for MachineHeartbeat for application/json ContentType.
+type MachineHeartbeatJSONRequestBody = MachineHeartbeat
+
+// OpenMachineSessionJSONRequestBody defines body for OpenMachineSession for application/json ContentType.
+type OpenMachineSessionJSONRequestBody = CardCredentials
+
+// MachineSessionCashOutJSONRequestBody defines body for MachineSessionCashOut for application/json ContentType.
+type MachineSessionCashOutJSONRequestBody = CashoutRequest
+
+// MachineSessionDepositJSONRequestBody defines body for MachineSessionDeposit for application/json ContentType.
+type MachineSessionDepositJSONRequestBody = DepositRequest
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+
+	// (GET /.well-known/jwks.json)
+	GetJwks(ctx echo.Context) error
+
+	// (POST /admin/accounts/{accountId}/freeze)
+	AdminFreezeAccount(ctx echo.Context, accountId openapi_types.UUID) error
+
+	// (POST /admin/accounts/{accountId}/unfreeze)
+	AdminUnfreezeAccount(ctx echo.Context, accountId openapi_types.UUID) error
+
+	// (GET /admin/fleet/health)
+	AdminGetFleetHealth(ctx echo.Context) error
+
+	// (GET /admin/machines)
+	AdminGetMachines(ctx echo.Context, params AdminGetMachinesParams) error
+
+	// (POST /admin/machines)
+	AdminCreateMachine(ctx echo.Context) error
+
+	// (PATCH /admin/machines/{machineId})
+	AdminUpdateMachine(ctx echo.Context, machineId openapi_types.UUID) error
+
+	// (GET /admin/machines/{machineId}/cassettes)
+	AdminGetCassettes(ctx echo.Context, machineId openapi_types.UUID) error
+
+	// (PUT /admin/machines/{machineId}/cassettes)
+	AdminCountCassettes(ctx echo.Context, machineId openapi_types.UUID) error
+
+	// (POST /admin/machines/{machineId}/cassettes/replenish)
+	AdminReplenishCassettes(ctx echo.Context, machineId openapi_types.UUID) error
+
+	// (POST /admin/machines/{machineId}/decommission)
+	AdminDecommissionMachine(ctx echo.Context, machineId openapi_types.UUID) error
+
+	// (GET /admin/machines/{machineId}/journal)
+	AdminGetMachineJournal(ctx echo.Context, machineId openapi_types.UUID, params AdminGetMachineJournalParams) error
+
+	// (GET /admin/machines/{machineId}/settlements)
+	AdminGetMachineSettlements(ctx echo.Context, machineId openapi_types.UUID, params AdminGetMachineSettlementsParams) error
+
+	// (POST /admin/machines/{machineId}/settlements)
+	AdminSettleMachine(ctx echo.Context, machineId openapi_types.UUID) error
+
+	// (POST /admin/machines/{machineId}/token)
+	AdminResetMachineToken(ctx echo.Context, machineId openapi_types.UUID) error
+
+	// (GET /admin/settlements)
+	AdminGetSettlements(ctx echo.Context, params AdminGetSettlementsParams) error
+
+	// (GET /admin/users)
+	AdminSearchUsers(ctx echo.Context, params AdminSearchUsersParams) error
+
+	// (GET /admin/users/{userId}/accounts)
+	AdminGetUserAccounts(ctx echo.Context, userId openapi_types.UUID) error
+
+	// (POST /admin/users/{userId}/resend-verify)
+	AdminResendVerify(ctx echo.Context, userId openapi_types.UUID) error
+
+	// (PUT /admin/users/{userId}/role)
+	AdminSetRole(ctx echo.Context, userId openapi_types.UUID) error
+
+	// (GET /api/v1/accounts)
+	GetAllAccounts(ctx echo.Context) error
+
+	// (POST /api/v1/accounts)
+	CreateAccount(ctx echo.Context) error
+
+	// (DELETE /api/v1/accounts/{accountId})
+	DeleteAccount(ctx echo.Context, accountId openapi_types.UUID) error
+
+	// (GET /api/v1/accounts/{accountId})
+	GetAccountInfo(ctx echo.Context, accountId openapi_types.UUID) error
+
+	// (GET /api/v1/accounts/{accountId}/movements)
+	GetAccountMovements(ctx echo.Context, accountId openapi_types.UUID, params GetAccountMovementsParams) error
+
+	// (PUT /api/v1/accounts/{accountId}/transfer)
+	Transfer(ctx echo.Context, accountId openapi_types.UUID) error
+
+	// (GET /api/v1/api-keys)
+	GetApiKeys(ctx echo.Context) error
+
+	// (POST /api/v1/api-keys)
+	CreateApiKey(ctx echo.Context) error
+
+	// (DELETE /api/v1/api-keys/{keyId})
+	DeleteApiKey(ctx echo.Context, keyId openapi_types.UUID) error
+
+	// (GET /api/v1/cards)
+	GetCards(ctx echo.Context) error
+
+	// (POST /api/v1/cards)
+	IssueCard(ctx echo.Context) error
+
+	// (POST /api/v1/cards/{cardId}/block)
+	BlockCard(ctx echo.Context, cardId openapi_types.UUID) error
+
+	// (PUT /api/v1/cards/{cardId}/pin)
+	SetCardPin(ctx echo.Context, cardId openapi_types.UUID) error
+
+	// (GET /api/v1/cashout-codes)
+	GetCashoutCodes(ctx echo.Context) error
+
+	// (POST /api/v1/cashout-codes)
+	CreateCashoutCode(ctx echo.Context) error
+
+	// (DELETE /api/v1/cashout-codes/{codeId})
+	CancelCashoutCode(ctx echo.Context, codeId openapi_types.UUID) error
+
+	// (GET /api/v1/machines)
+	FindMachines(ctx echo.Context, params FindMachinesParams) error
+
+	// (POST /api/v1/transfers/{operationId}/confirm)
+	ConfirmTransfer(ctx echo.Context, operationId openapi_types.UUID) error
+
+	// (POST /auth/2fa/confirm)
+	ConfirmTwoFactor(ctx echo.Context) error
+
+	// (POST /auth/2fa/disable)
+	DisableTwoFactor(ctx echo.Context) error
+
+	// (POST /auth/2fa/enroll)
+	EnrollTwoFactor(ctx echo.Context) error
+
+	// (GET /auth/email-change/confirm)
+	ConfirmEmailChange(ctx echo.Context, params ConfirmEmailChangeParams) error
+
+	// (POST /auth/magic-link/request)
+	RequestMagicLink(ctx echo.Context) error
+
+	// (POST /auth/magic-link/sign-in)
+	SignInMagicLink(ctx echo.Context) error
+
+	// (GET /auth/me)
+	GetMe(ctx echo.Context) error
+
+	// (PATCH /auth/me)
+	UpdateMe(ctx echo.Context) error
+
+	// (POST /auth/me/erase)
+	EraseMe(ctx echo.Context) error
+
+	// (POST /auth/me/export)
+	RequestDataExport(ctx echo.Context) error
+
+	// (GET /auth/me/export/{exportId})
+	GetDataExport(ctx echo.Context, exportId openapi_types.UUID) error
+
+	// (PUT /auth/me/password)
+	ChangePassword(ctx echo.Context) error
+
+	// (GET /auth/passkeys)
+	GetPasskeys(ctx echo.Context) error
+
+	// (POST /auth/passkeys/register/begin)
+	BeginPasskeyRegistration(ctx echo.Context) error
+
+	// (POST /auth/passkeys/register/finish)
+	FinishPasskeyRegistration(ctx echo.Context) error
+
+	// (POST /auth/passkeys/sign-in/begin)
+	BeginPasskeySignIn(ctx echo.Context) error
+
+	// (POST /auth/passkeys/sign-in/finish)
+	FinishPasskeySignIn(ctx echo.Context) error
+
+	// (DELETE /auth/passkeys/{passkeyId})
+	DeletePasskey(ctx echo.Context, passkeyId openapi_types.UUID) error
+
+	// (POST /auth/password-reset/confirm)
+	ConfirmPasswordReset(ctx echo.Context) error
+
+	// (POST /auth/password-reset/request)
+	RequestPasswordReset(ctx echo.Context) error
+
+	// (POST /auth/resend-verify)
+	ResendVerify(ctx echo.Context) error
+
+	// (GET /auth/sessions)
+	GetSessions(ctx echo.Context) error
+
+	// (DELETE /auth/sessions/{sessionId})
+	RevokeSession(ctx echo.Context, sessionId openapi_types.UUID) error
+
+	// (POST /auth/sign-in)
+	SignIn(ctx echo.Context) error
+
+	// (POST /auth/sign-in/2fa)
+	SignInTwoFactor(ctx echo.Context) error
+
+	// (POST /auth/sign-up)
+	SignUp(ctx echo.Context) error
+
+	// (GET /auth/verify-email)
+	VerifyEmail(ctx echo.Context, params VerifyEmailParams) error
+
+	// (POST /machines/cashout-codes/redeem)
+	RedeemCashoutCode(ctx echo.Context) error
+
+	// (GET /machines/deposit-recipients/{accountId})
+	GetDepositRecipient(ctx echo.Context, accountId openapi_types.UUID) error
+
+	// (POST /machines/deposits)
+	MachineThirdPartyDeposit(ctx echo.Context) error
+
+	// (POST /machines/heartbeat)
+	MachineHeartbeat(ctx echo.Context) error
+
+	// (POST /machines/sessions)
+	OpenMachineSession(ctx echo.Context) error
+
+	// (DELETE /machines/sessions/{sessionId})
+	CloseMachineSession(ctx echo.Context, sessionId openapi_types.UUID) error
+
+	// (GET /machines/sessions/{sessionId}/balance)
+	MachineSessionBalance(ctx echo.Context, sessionId openapi_types.UUID) error
+
+	// (POST /machines/sessions/{sessionId}/cashOut)
+	MachineSessionCashOut(ctx echo.Context, sessionId openapi_types.UUID) error
+
+	// (POST /machines/sessions/{sessionId}/deposit)
+	MachineSessionDeposit(ctx echo.Context, sessionId openapi_types.UUID) error
+
+	// (GET /machines/sessions/{sessionId}/mini-statement)
+	MachineSessionMiniStatement(ctx echo.Context, sessionId openapi_types.UUID) error
+
+	// (POST /machines/token/refresh)
+	RefreshMachineToken(ctx echo.Context) error
+}
+
+// ServerInterfaceWrapper converts echo contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+// GetJwks converts echo context to params.
+func (w *ServerInterfaceWrapper) GetJwks(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetJwks(ctx)
+	return err
+}
+
+// AdminFreezeAccount converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminFreezeAccount(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "accountId" -------------
+	var accountId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "accountId", ctx.Param("accountId"), &accountId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter accountId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"support", "admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminFreezeAccount(ctx, accountId)
+	return err
+}
+
+// AdminUnfreezeAccount converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminUnfreezeAccount(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "accountId" -------------
+	var accountId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "accountId", ctx.Param("accountId"), &accountId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter accountId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"support", "admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminUnfreezeAccount(ctx, accountId)
+	return err
+}
+
+// AdminGetFleetHealth converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminGetFleetHealth(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"operator", "admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminGetFleetHealth(ctx)
+	return err
+}
+
+// AdminGetMachines converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminGetMachines(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"operator", "admin"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AdminGetMachinesParams
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", ctx.QueryParams(), &params.Offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter offset: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminGetMachines(ctx, params)
+	return err
+}
+
+// AdminCreateMachine converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminCreateMachine(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"operator", "admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminCreateMachine(ctx)
+	return err
+}
+
+// AdminUpdateMachine converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminUpdateMachine(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "machineId" -------------
+	var machineId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "machineId", ctx.Param("machineId"), &machineId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter machineId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"operator", "admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminUpdateMachine(ctx, machineId)
+	return err
+}
+
+// AdminGetCassettes converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminGetCassettes(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "machineId" -------------
+	var machineId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "machineId", ctx.Param("machineId"), &machineId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter machineId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"operator", "admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminGetCassettes(ctx, machineId)
+	return err
+}
+
+// AdminCountCassettes converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminCountCassettes(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "machineId" -------------
+	var machineId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "machineId", ctx.Param("machineId"), &machineId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter machineId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"operator", "admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminCountCassettes(ctx, machineId)
+	return err
+}
+
+// AdminReplenishCassettes converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminReplenishCassettes(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "machineId" -------------
+	var machineId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "machineId", ctx.Param("machineId"), &machineId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter machineId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"operator", "admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminReplenishCassettes(ctx, machineId)
+	return err
+}
+
+// AdminDecommissionMachine converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminDecommissionMachine(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "machineId" -------------
+	var machineId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "machineId", ctx.Param("machineId"), &machineId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter machineId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"operator", "admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminDecommissionMachine(ctx, machineId)
+	return err
+}
+
+// AdminGetMachineJournal converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminGetMachineJournal(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "machineId" -------------
+	var machineId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "machineId", ctx.Param("machineId"), &machineId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter machineId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"operator", "admin"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AdminGetMachineJournalParams
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", ctx.QueryParams(), &params.Offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter offset: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminGetMachineJournal(ctx, machineId, params)
+	return err
+}
+
+// AdminGetMachineSettlements converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminGetMachineSettlements(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "machineId" -------------
+	var machineId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "machineId", ctx.Param("machineId"), &machineId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter machineId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"operator", "admin"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AdminGetMachineSettlementsParams
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", ctx.QueryParams(), &params.Offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter offset: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminGetMachineSettlements(ctx, machineId, params)
+	return err
+}
+
+// AdminSettleMachine converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminSettleMachine(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "machineId" -------------
+	var machineId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "machineId", ctx.Param("machineId"), &machineId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter machineId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"operator", "admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminSettleMachine(ctx, machineId)
+	return err
+}
+
+// AdminResetMachineToken converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminResetMachineToken(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "machineId" -------------
+	var machineId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "machineId", ctx.Param("machineId"), &machineId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter machineId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"operator", "admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminResetMachineToken(ctx, machineId)
+	return err
+}
+
+// AdminGetSettlements converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminGetSettlements(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"operator", "admin"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AdminGetSettlementsParams
+	// ------------- Optional query parameter "discrepancy" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "discrepancy", ctx.QueryParams(), &params.Discrepancy)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter discrepancy: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", ctx.QueryParams(), &params.Offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter offset: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminGetSettlements(ctx, params)
+	return err
+}
+
+// AdminSearchUsers converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminSearchUsers(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"support", "admin"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AdminSearchUsersParams
+	// ------------- Optional query parameter "query" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "query", ctx.QueryParams(), &params.Query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter query: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", ctx.QueryParams(), &params.Offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter offset: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminSearchUsers(ctx, params)
+	return err
+}
+
+// AdminGetUserAccounts converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminGetUserAccounts(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "userId" -------------
+	var userId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userId", ctx.Param("userId"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"support", "admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminGetUserAccounts(ctx, userId)
+	return err
+}
+
+// AdminResendVerify converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminResendVerify(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "userId" -------------
+	var userId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userId", ctx.Param("userId"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"support", "admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminResendVerify(ctx, userId)
+	return err
+}
+
+// AdminSetRole converts echo context to params.
+func (w *ServerInterfaceWrapper) AdminSetRole(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "userId" -------------
+	var userId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userId", ctx.Param("userId"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AdminSetRole(ctx, userId)
+	return err
+}
+
+// GetAllAccounts converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAllAccounts(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"accounts:read"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAllAccounts(ctx)
+	return err
+}
+
+// CreateAccount converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateAccount(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CreateAccount(ctx)
+	return err
+}
+
+// DeleteAccount converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteAccount(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "accountId" -------------
+	var accountId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "accountId", ctx.Param("accountId"), &accountId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter accountId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteAccount(ctx, accountId)
+	return err
+}
+
+// GetAccountInfo converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAccountInfo(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "accountId" -------------
+	var accountId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "accountId", ctx.Param("accountId"), &accountId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter accountId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"accounts:read"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAccountInfo(ctx, accountId)
+	return err
+}
+
+// GetAccountMovements converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAccountMovements(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "accountId" -------------
+	var accountId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "accountId", ctx.Param("accountId"), &accountId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter accountId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"transactions:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAccountMovementsParams
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", ctx.QueryParams(), &params.Offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter offset: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAccountMovements(ctx, accountId, params)
+	return err
+}
+
+// Transfer converts echo context to params.
+func (w *ServerInterfaceWrapper) Transfer(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "accountId" -------------
+	var accountId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "accountId", ctx.Param("accountId"), &accountId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter accountId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"transfers:write"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.Transfer(ctx, accountId)
+	return err
+}
+
+// GetApiKeys converts echo context to params.
+func (w *ServerInterfaceWrapper) GetApiKeys(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetApiKeys(ctx)
+	return err
+}
+
+// CreateApiKey converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateApiKey(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CreateApiKey(ctx)
+	return err
+}
+
+// DeleteApiKey converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteApiKey(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "keyId" -------------
+	var keyId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "keyId", ctx.Param("keyId"), &keyId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter keyId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteApiKey(ctx, keyId)
+	return err
+}
+
+// GetCards converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCards(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCards(ctx)
+	return err
+}
+
+// IssueCard converts echo context to params.
+func (w *ServerInterfaceWrapper) IssueCard(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.IssueCard(ctx)
+	return err
+}
+
+// BlockCard converts echo context to params.
+func (w *ServerInterfaceWrapper) BlockCard(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "cardId" -------------
+	var cardId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "cardId", ctx.Param("cardId"), &cardId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cardId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.BlockCard(ctx, cardId)
+	return err
+}
+
+// SetCardPin converts echo context to params.
+func (w *ServerInterfaceWrapper) SetCardPin(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "cardId" -------------
+	var cardId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "cardId", ctx.Param("cardId"), &cardId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cardId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SetCardPin(ctx, cardId)
+	return err
+}
+
+// GetCashoutCodes converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCashoutCodes(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCashoutCodes(ctx)
+	return err
+}
+
+// CreateCashoutCode converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateCashoutCode(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CreateCashoutCode(ctx)
+	return err
+}
+
+// CancelCashoutCode converts echo context to params.
+func (w *ServerInterfaceWrapper) CancelCashoutCode(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "codeId" -------------
+	var codeId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "codeId", ctx.Param("codeId"), &codeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter codeId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CancelCashoutCode(ctx, codeId)
+	return err
+}
+
+// FindMachines converts echo context to params.
+func (w *ServerInterfaceWrapper) FindMachines(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params FindMachinesParams
+	// ------------- Required query parameter "lat" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "lat", ctx.QueryParams(), &params.Lat)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter lat: %s", err))
+	}
+
+	// ------------- Required query parameter "lon" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "lon", ctx.QueryParams(), &params.Lon)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter lon: %s", err))
+	}
+
+	// ------------- Optional query parameter "radius" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "radius", ctx.QueryParams(), &params.Radius)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter radius: %s", err))
+	}
+
+	// ------------- Optional query parameter "supports" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "supports", ctx.QueryParams(), &params.Supports)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter supports: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.FindMachines(ctx, params)
+	return err
+}
+
+// ConfirmTransfer converts echo context to params.
+func (w *ServerInterfaceWrapper) ConfirmTransfer(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "operationId" -------------
+	var operationId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "operationId", ctx.Param("operationId"), &operationId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter operationId: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"transfers:write"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ConfirmTransfer(ctx, operationId)
+	return err
+}
+
+// ConfirmTwoFactor converts echo context to params.
+func (w *ServerInterfaceWrapper) ConfirmTwoFactor(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ConfirmTwoFactor(ctx)
+	return err
+}
+
+// DisableTwoFactor converts echo context to params.
+func (w *ServerInterfaceWrapper) DisableTwoFactor(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DisableTwoFactor(ctx)
+	return err
+}
+
+// EnrollTwoFactor converts echo context to params.
+func (w *ServerInterfaceWrapper) EnrollTwoFactor(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.EnrollTwoFactor(ctx)
+	return err
+}
+
+// ConfirmEmailChange converts echo context to params.
+func (w *ServerInterfaceWrapper) ConfirmEmailChange(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ConfirmEmailChangeParams
+	// ------------- Required query parameter "token" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "token", ctx.QueryParams(), &params.Token)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ConfirmEmailChange(ctx, params)
+	return err
+}
+
+// RequestMagicLink converts echo context to params.
+func (w *ServerInterfaceWrapper) RequestMagicLink(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RequestMagicLink(ctx)
+	return err
+}
+
+// SignInMagicLink converts echo context to params.
+func (w *ServerInterfaceWrapper) SignInMagicLink(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SignInMagicLink(ctx)
+	return err
+}
+
+// GetMe converts echo context to params.
+func (w *ServerInterfaceWrapper) GetMe(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetMe(ctx)
+	return err
+}
+
+// UpdateMe converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateMe(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpdateMe(ctx)
+	return err
+}
+
+// EraseMe converts echo context to params.
+func (w *ServerInterfaceWrapper) EraseMe(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"user"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.EraseMe(ctx)
+	return err
+}
+
+// RequestDataExport converts echo context to params.
+func (w *ServerInterfaceWrapper) RequestDataExport(ctx echo.Context) error {
+	var err error
 
-// DepositRequest defines model for DepositRequest.
-type DepositRequest struct {
-	Amount int32 `json:"amount"`
-}
+	ctx.Set(BearerAuthScopes, []string{"user"})
 
-// Message defines model for Message.
-type Message struct {
-	Message string `json:"message"`
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RequestDataExport(ctx)
+	return err
 }
 
-// ReturnId defines model for ReturnId.
-type ReturnId struct {
-	Id openapi_types.UUID `json:"id"`
-}
+// GetDataExport converts echo context to params.
+func (w *ServerInterfaceWrapper) GetDataExport(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "exportId" -------------
+	var exportId openapi_types.UUID
 
-// ReturnToken defines model for ReturnToken.
-type ReturnToken struct {
-	Token string `json:"token"`
-}
+	err = runtime.BindStyledParameterWithOptions("simple", "exportId", ctx.Param("exportId"), &exportId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter exportId: %s", err))
+	}
 
-// TransferInfo defines model for TransferInfo.
-type TransferInfo struct {
-	Amount int32              `json:"amount"`
-	To     openapi_types.UUID `json:"to"`
-}
+	ctx.Set(BearerAuthScopes, []string{"user"})
 
-// User defines model for User.
-type User struct {
-	Email    openapi_types.Email `json:"email"`
-	Id       openapi_types.UUID  `json:"id"`
-	Name     string              `json:"name"`
-	Patronyc string              `json:"patronyc"`
-	Surname  string              `json:"surname"`
-	Verified bool                `json:"verified"`
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetDataExport(ctx, exportId)
+	return err
 }
 
-// UserWithPassword defines model for UserWithPassword.
-type UserWithPassword struct {
-	Email    openapi_types.Email `json:"email"`
-	Name     string              `json:"name"`
-	Password string              `json:"password"`
-	Patronyc string              `json:"patronyc"`
-	Surname  string              `json:"surname"`
-}
+// ChangePassword converts echo context to params.
+func (w *ServerInterfaceWrapper) ChangePassword(ctx echo.Context) error {
+	var err error
 
-// CashOutParams defines parameters for CashOut.
-type CashOutParams struct {
-	XMachineId openapi_types.UUID `form:"x-machine-id" json:"x-machine-id"`
-}
+	ctx.Set(BearerAuthScopes, []string{"user"})
 
-// DepositParams defines parameters for Deposit.
-type DepositParams struct {
-	XMachineId openapi_types.UUID `form:"x-machine-id" json:"x-machine-id"`
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ChangePassword(ctx)
+	return err
 }
 
-// VerifyEmailParams defines parameters for VerifyEmail.
-type VerifyEmailParams struct {
-	Token string `form:"token" json:"token"`
-}
+// GetPasskeys converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPasskeys(ctx echo.Context) error {
+	var err error
 
-// CashOutJSONRequestBody defines body for CashOut for application/json ContentType.
-type CashOutJSONRequestBody = CashoutRequest
+	ctx.Set(BearerAuthScopes, []string{"user"})
 
-// DepositJSONRequestBody defines body for Deposit for application/json ContentType.
-type DepositJSONRequestBody = DepositRequest
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPasskeys(ctx)
+	return err
+}
 
-// TransferJSONRequestBody defines body for Transfer for application/json ContentType.
-type TransferJSONRequestBody = TransferInfo
+// BeginPasskeyRegistration converts echo context to params.
+func (w *ServerInterfaceWrapper) BeginPasskeyRegistration(ctx echo.Context) error {
+	var err error
 
-// SignInJSONRequestBody defines body for SignIn for application/json ContentType.
-type SignInJSONRequestBody = AuthSchema
+	ctx.Set(BearerAuthScopes, []string{"user"})
 
-// SignUpJSONRequestBody defines body for SignUp for application/json ContentType.
-type SignUpJSONRequestBody = UserWithPassword
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.BeginPasskeyRegistration(ctx)
+	return err
+}
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
+// FinishPasskeyRegistration converts echo context to params.
+func (w *ServerInterfaceWrapper) FinishPasskeyRegistration(ctx echo.Context) error {
+	var err error
 
-	// (GET /api/v1/accounts)
-	GetAllAccounts(ctx echo.Context) error
+	ctx.Set(BearerAuthScopes, []string{"user"})
 
-	// (POST /api/v1/accounts)
-	CreateAccount(ctx echo.Context) error
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.FinishPasskeyRegistration(ctx)
+	return err
+}
 
-	// (DELETE /api/v1/accounts/{accountId})
-	DeleteAccount(ctx echo.Context, accountId openapi_types.UUID) error
+// BeginPasskeySignIn converts echo context to params.
+func (w *ServerInterfaceWrapper) BeginPasskeySignIn(ctx echo.Context) error {
+	var err error
 
-	// (GET /api/v1/accounts/{accountId})
-	GetAccountInfo(ctx echo.Context, accountId openapi_types.UUID) error
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.BeginPasskeySignIn(ctx)
+	return err
+}
 
-	// (PUT /api/v1/accounts/{accountId}/cashOut)
-	CashOut(ctx echo.Context, accountId openapi_types.UUID, params CashOutParams) error
+// FinishPasskeySignIn converts echo context to params.
+func (w *ServerInterfaceWrapper) FinishPasskeySignIn(ctx echo.Context) error {
+	var err error
 
-	// (PUT /api/v1/accounts/{accountId}/deposit)
-	Deposit(ctx echo.Context, accountId openapi_types.UUID, params DepositParams) error
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.FinishPasskeySignIn(ctx)
+	return err
+}
 
-	// (PUT /api/v1/accounts/{accountId}/transfer)
-	Transfer(ctx echo.Context, accountId openapi_types.UUID) error
+// DeletePasskey converts echo context to params.
+func (w *ServerInterfaceWrapper) DeletePasskey(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "passkeyId" -------------
+	var passkeyId openapi_types.UUID
 
-	// (GET /auth/me)
-	GetMe(ctx echo.Context) error
+	err = runtime.BindStyledParameterWithOptions("simple", "passkeyId", ctx.Param("passkeyId"), &passkeyId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter passkeyId: %s", err))
+	}
 
-	// (POST /auth/resend-verify)
-	ResendVerify(ctx echo.Context) error
+	ctx.Set(BearerAuthScopes, []string{"user"})
 
-	// (POST /auth/sign-in)
-	SignIn(ctx echo.Context) error
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeletePasskey(ctx, passkeyId)
+	return err
+}
 
-	// (POST /auth/sign-up)
-	SignUp(ctx echo.Context) error
+// ConfirmPasswordReset converts echo context to params.
+func (w *ServerInterfaceWrapper) ConfirmPasswordReset(ctx echo.Context) error {
+	var err error
 
-	// (GET /auth/verify-email)
-	VerifyEmail(ctx echo.Context, params VerifyEmailParams) error
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ConfirmPasswordReset(ctx)
+	return err
 }
 
-// ServerInterfaceWrapper converts echo contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler ServerInterface
+// RequestPasswordReset converts echo context to params.
+func (w *ServerInterfaceWrapper) RequestPasswordReset(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RequestPasswordReset(ctx)
+	return err
 }
 
-// GetAllAccounts converts echo context to params.
-func (w *ServerInterfaceWrapper) GetAllAccounts(ctx echo.Context) error {
+// ResendVerify converts echo context to params.
+func (w *ServerInterfaceWrapper) ResendVerify(ctx echo.Context) error {
 	var err error
 
 	ctx.Set(BearerAuthScopes, []string{"user"})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetAllAccounts(ctx)
+	err = w.Handler.ResendVerify(ctx)
 	return err
 }
 
-// CreateAccount converts echo context to params.
-func (w *ServerInterfaceWrapper) CreateAccount(ctx echo.Context) error {
+// GetSessions converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSessions(ctx echo.Context) error {
 	var err error
 
 	ctx.Set(BearerAuthScopes, []string{"user"})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.CreateAccount(ctx)
+	err = w.Handler.GetSessions(ctx)
 	return err
 }
 
-// DeleteAccount converts echo context to params.
-func (w *ServerInterfaceWrapper) DeleteAccount(ctx echo.Context) error {
+// RevokeSession converts echo context to params.
+func (w *ServerInterfaceWrapper) RevokeSession(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "accountId" -------------
-	var accountId openapi_types.UUID
+	// ------------- Path parameter "sessionId" -------------
+	var sessionId openapi_types.UUID
 
-	err = runtime.BindStyledParameterWithOptions("simple", "accountId", ctx.Param("accountId"), &accountId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "sessionId", ctx.Param("sessionId"), &sessionId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter accountId: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sessionId: %s", err))
 	}
 
 	ctx.Set(BearerAuthScopes, []string{"user"})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.DeleteAccount(ctx, accountId)
+	err = w.Handler.RevokeSession(ctx, sessionId)
 	return err
 }
 
-// GetAccountInfo converts echo context to params.
-func (w *ServerInterfaceWrapper) GetAccountInfo(ctx echo.Context) error {
+// SignIn converts echo context to params.
+func (w *ServerInterfaceWrapper) SignIn(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "accountId" -------------
-	var accountId openapi_types.UUID
 
-	err = runtime.BindStyledParameterWithOptions("simple", "accountId", ctx.Param("accountId"), &accountId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter accountId: %s", err))
-	}
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SignIn(ctx)
+	return err
+}
 
-	ctx.Set(BearerAuthScopes, []string{"user"})
+// SignInTwoFactor converts echo context to params.
+func (w *ServerInterfaceWrapper) SignInTwoFactor(ctx echo.Context) error {
+	var err error
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetAccountInfo(ctx, accountId)
+	err = w.Handler.SignInTwoFactor(ctx)
 	return err
 }
 
-// CashOut converts echo context to params.
-func (w *ServerInterfaceWrapper) CashOut(ctx echo.Context) error {
+// SignUp converts echo context to params.
+func (w *ServerInterfaceWrapper) SignUp(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "accountId" -------------
-	var accountId openapi_types.UUID
 
-	err = runtime.BindStyledParameterWithOptions("simple", "accountId", ctx.Param("accountId"), &accountId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter accountId: %s", err))
-	}
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SignUp(ctx)
+	return err
+}
 
-	ctx.Set(BearerAuthScopes, []string{"machine"})
+// VerifyEmail converts echo context to params.
+func (w *ServerInterfaceWrapper) VerifyEmail(ctx echo.Context) error {
+	var err error
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params CashOutParams
+	var params VerifyEmailParams
+	// ------------- Required query parameter "token" -------------
 
-	if cookie, err := ctx.Cookie("x-machine-id"); err == nil {
+	err = runtime.BindQueryParameter("form", true, true, "token", ctx.QueryParams(), &params.Token)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+	}
 
-		var value openapi_types.UUID
-		err = runtime.BindStyledParameterWithOptions("simple", "x-machine-id", cookie.Value, &value, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationCookie, Explode: true, Required: true})
-		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter x-machine-id: %s", err))
-		}
-		params.XMachineId = value
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.VerifyEmail(ctx, params)
+	return err
+}
 
-	} else {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query argument x-machine-id is required, but not found"))
-	}
+// RedeemCashoutCode converts echo context to params.
+func (w *ServerInterfaceWrapper) RedeemCashoutCode(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(MachineAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.CashOut(ctx, accountId, params)
+	err = w.Handler.RedeemCashoutCode(ctx)
 	return err
 }
 
-// Deposit converts echo context to params.
-func (w *ServerInterfaceWrapper) Deposit(ctx echo.Context) error {
+// GetDepositRecipient converts echo context to params.
+func (w *ServerInterfaceWrapper) GetDepositRecipient(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "accountId" -------------
 	var accountId openapi_types.UUID
@@ -263,102 +2305,144 @@ func (w *ServerInterfaceWrapper) Deposit(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter accountId: %s", err))
 	}
 
-	ctx.Set(BearerAuthScopes, []string{"machine"})
+	ctx.Set(MachineAuthScopes, []string{})
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params DepositParams
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetDepositRecipient(ctx, accountId)
+	return err
+}
 
-	if cookie, err := ctx.Cookie("x-machine-id"); err == nil {
+// MachineThirdPartyDeposit converts echo context to params.
+func (w *ServerInterfaceWrapper) MachineThirdPartyDeposit(ctx echo.Context) error {
+	var err error
 
-		var value openapi_types.UUID
-		err = runtime.BindStyledParameterWithOptions("simple", "x-machine-id", cookie.Value, &value, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationCookie, Explode: true, Required: true})
-		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter x-machine-id: %s", err))
-		}
-		params.XMachineId = value
+	ctx.Set(MachineAuthScopes, []string{})
 
-	} else {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query argument x-machine-id is required, but not found"))
-	}
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.MachineThirdPartyDeposit(ctx)
+	return err
+}
+
+// MachineHeartbeat converts echo context to params.
+func (w *ServerInterfaceWrapper) MachineHeartbeat(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(MachineAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.Deposit(ctx, accountId, params)
+	err = w.Handler.MachineHeartbeat(ctx)
 	return err
 }
 
-// Transfer converts echo context to params.
-func (w *ServerInterfaceWrapper) Transfer(ctx echo.Context) error {
+// OpenMachineSession converts echo context to params.
+func (w *ServerInterfaceWrapper) OpenMachineSession(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "accountId" -------------
-	var accountId openapi_types.UUID
 
-	err = runtime.BindStyledParameterWithOptions("simple", "accountId", ctx.Param("accountId"), &accountId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	ctx.Set(MachineAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.OpenMachineSession(ctx)
+	return err
+}
+
+// CloseMachineSession converts echo context to params.
+func (w *ServerInterfaceWrapper) CloseMachineSession(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "sessionId" -------------
+	var sessionId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "sessionId", ctx.Param("sessionId"), &sessionId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter accountId: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sessionId: %s", err))
 	}
 
-	ctx.Set(BearerAuthScopes, []string{"user"})
+	ctx.Set(MachineAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.Transfer(ctx, accountId)
+	err = w.Handler.CloseMachineSession(ctx, sessionId)
 	return err
 }
 
-// GetMe converts echo context to params.
-func (w *ServerInterfaceWrapper) GetMe(ctx echo.Context) error {
+// MachineSessionBalance converts echo context to params.
+func (w *ServerInterfaceWrapper) MachineSessionBalance(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "sessionId" -------------
+	var sessionId openapi_types.UUID
 
-	ctx.Set(BearerAuthScopes, []string{"user"})
+	err = runtime.BindStyledParameterWithOptions("simple", "sessionId", ctx.Param("sessionId"), &sessionId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sessionId: %s", err))
+	}
+
+	ctx.Set(MachineAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetMe(ctx)
+	err = w.Handler.MachineSessionBalance(ctx, sessionId)
 	return err
 }
 
-// ResendVerify converts echo context to params.
-func (w *ServerInterfaceWrapper) ResendVerify(ctx echo.Context) error {
+// MachineSessionCashOut converts echo context to params.
+func (w *ServerInterfaceWrapper) MachineSessionCashOut(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "sessionId" -------------
+	var sessionId openapi_types.UUID
 
-	ctx.Set(BearerAuthScopes, []string{"user"})
+	err = runtime.BindStyledParameterWithOptions("simple", "sessionId", ctx.Param("sessionId"), &sessionId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sessionId: %s", err))
+	}
+
+	ctx.Set(MachineAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.ResendVerify(ctx)
+	err = w.Handler.MachineSessionCashOut(ctx, sessionId)
 	return err
 }
 
-// SignIn converts echo context to params.
-func (w *ServerInterfaceWrapper) SignIn(ctx echo.Context) error {
+// MachineSessionDeposit converts echo context to params.
+func (w *ServerInterfaceWrapper) MachineSessionDeposit(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "sessionId" -------------
+	var sessionId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "sessionId", ctx.Param("sessionId"), &sessionId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sessionId: %s", err))
+	}
+
+	ctx.Set(MachineAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.SignIn(ctx)
+	err = w.Handler.MachineSessionDeposit(ctx, sessionId)
 	return err
 }
 
-// SignUp converts echo context to params.
-func (w *ServerInterfaceWrapper) SignUp(ctx echo.Context) error {
+// MachineSessionMiniStatement converts echo context to params.
+func (w *ServerInterfaceWrapper) MachineSessionMiniStatement(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "sessionId" -------------
+	var sessionId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "sessionId", ctx.Param("sessionId"), &sessionId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sessionId: %s", err))
+	}
+
+	ctx.Set(MachineAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.SignUp(ctx)
+	err = w.Handler.MachineSessionMiniStatement(ctx, sessionId)
 	return err
 }
 
-// VerifyEmail converts echo context to params.
-func (w *ServerInterfaceWrapper) VerifyEmail(ctx echo.Context) error {
+// RefreshMachineToken converts echo context to params.
+func (w *ServerInterfaceWrapper) RefreshMachineToken(ctx echo.Context) error {
 	var err error
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params VerifyEmailParams
-	// ------------- Required query parameter "token" -------------
-
-	err = runtime.BindQueryParameter("form", true, true, "token", ctx.QueryParams(), &params.Token)
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
-	}
+	ctx.Set(MachineAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.VerifyEmail(ctx, params)
+	err = w.Handler.RefreshMachineToken(ctx)
 	return err
 }
 
@@ -390,57 +2474,289 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/.well-known/jwks.json", wrapper.GetJwks)
+	router.POST(baseURL+"/admin/accounts/:accountId/freeze", wrapper.AdminFreezeAccount)
+	router.POST(baseURL+"/admin/accounts/:accountId/unfreeze", wrapper.AdminUnfreezeAccount)
+	router.GET(baseURL+"/admin/fleet/health", wrapper.AdminGetFleetHealth)
+	router.GET(baseURL+"/admin/machines", wrapper.AdminGetMachines)
+	router.POST(baseURL+"/admin/machines", wrapper.AdminCreateMachine)
+	router.PATCH(baseURL+"/admin/machines/:machineId", wrapper.AdminUpdateMachine)
+	router.GET(baseURL+"/admin/machines/:machineId/cassettes", wrapper.AdminGetCassettes)
+	router.PUT(baseURL+"/admin/machines/:machineId/cassettes", wrapper.AdminCountCassettes)
+	router.POST(baseURL+"/admin/machines/:machineId/cassettes/replenish", wrapper.AdminReplenishCassettes)
+	router.POST(baseURL+"/admin/machines/:machineId/decommission", wrapper.AdminDecommissionMachine)
+	router.GET(baseURL+"/admin/machines/:machineId/journal", wrapper.AdminGetMachineJournal)
+	router.GET(baseURL+"/admin/machines/:machineId/settlements", wrapper.AdminGetMachineSettlements)
+	router.POST(baseURL+"/admin/machines/:machineId/settlements", wrapper.AdminSettleMachine)
+	router.POST(baseURL+"/admin/machines/:machineId/token", wrapper.AdminResetMachineToken)
+	router.GET(baseURL+"/admin/settlements", wrapper.AdminGetSettlements)
+	router.GET(baseURL+"/admin/users", wrapper.AdminSearchUsers)
+	router.GET(baseURL+"/admin/users/:userId/accounts", wrapper.AdminGetUserAccounts)
+	router.POST(baseURL+"/admin/users/:userId/resend-verify", wrapper.AdminResendVerify)
+	router.PUT(baseURL+"/admin/users/:userId/role", wrapper.AdminSetRole)
 	router.GET(baseURL+"/api/v1/accounts", wrapper.GetAllAccounts)
 	router.POST(baseURL+"/api/v1/accounts", wrapper.CreateAccount)
 	router.DELETE(baseURL+"/api/v1/accounts/:accountId", wrapper.DeleteAccount)
 	router.GET(baseURL+"/api/v1/accounts/:accountId", wrapper.GetAccountInfo)
-	router.PUT(baseURL+"/api/v1/accounts/:accountId/cashOut", wrapper.CashOut)
-	router.PUT(baseURL+"/api/v1/accounts/:accountId/deposit", wrapper.Deposit)
+	router.GET(baseURL+"/api/v1/accounts/:accountId/movements", wrapper.GetAccountMovements)
 	router.PUT(baseURL+"/api/v1/accounts/:accountId/transfer", wrapper.Transfer)
+	router.GET(baseURL+"/api/v1/api-keys", wrapper.GetApiKeys)
+	router.POST(baseURL+"/api/v1/api-keys", wrapper.CreateApiKey)
+	router.DELETE(baseURL+"/api/v1/api-keys/:keyId", wrapper.DeleteApiKey)
+	router.GET(baseURL+"/api/v1/cards", wrapper.GetCards)
+	router.POST(baseURL+"/api/v1/cards", wrapper.IssueCard)
+	router.POST(baseURL+"/api/v1/cards/:cardId/block", wrapper.BlockCard)
+	router.PUT(baseURL+"/api/v1/cards/:cardId/pin", wrapper.SetCardPin)
+	router.GET(baseURL+"/api/v1/cashout-codes", wrapper.GetCashoutCodes)
+	router.POST(baseURL+"/api/v1/cashout-codes", wrapper.CreateCashoutCode)
+	router.DELETE(baseURL+"/api/v1/cashout-codes/:codeId", wrapper.CancelCashoutCode)
+	router.GET(baseURL+"/api/v1/machines", wrapper.FindMachines)
+	router.POST(baseURL+"/api/v1/transfers/:operationId/confirm", wrapper.ConfirmTransfer)
+	router.POST(baseURL+"/auth/2fa/confirm", wrapper.ConfirmTwoFactor)
+	router.POST(baseURL+"/auth/2fa/disable", wrapper.DisableTwoFactor)
+	router.POST(baseURL+"/auth/2fa/enroll", wrapper.EnrollTwoFactor)
+	router.GET(baseURL+"/auth/email-change/confirm", wrapper.ConfirmEmailChange)
+	router.POST(baseURL+"/auth/magic-link/request", wrapper.RequestMagicLink)
+	router.POST(baseURL+"/auth/magic-link/sign-in", wrapper.SignInMagicLink)
 	router.GET(baseURL+"/auth/me", wrapper.GetMe)
+	router.PATCH(baseURL+"/auth/me", wrapper.UpdateMe)
+	router.POST(baseURL+"/auth/me/erase", wrapper.EraseMe)
+	router.POST(baseURL+"/auth/me/export", wrapper.RequestDataExport)
+	router.GET(baseURL+"/auth/me/export/:exportId", wrapper.GetDataExport)
+	router.PUT(baseURL+"/auth/me/password", wrapper.ChangePassword)
+	router.GET(baseURL+"/auth/passkeys", wrapper.GetPasskeys)
+	router.POST(baseURL+"/auth/passkeys/register/begin", wrapper.BeginPasskeyRegistration)
+	router.POST(baseURL+"/auth/passkeys/register/finish", wrapper.FinishPasskeyRegistration)
+	router.POST(baseURL+"/auth/passkeys/sign-in/begin", wrapper.BeginPasskeySignIn)
+	router.POST(baseURL+"/auth/passkeys/sign-in/finish", wrapper.FinishPasskeySignIn)
+	router.DELETE(baseURL+"/auth/passkeys/:passkeyId", wrapper.DeletePasskey)
+	router.POST(baseURL+"/auth/password-reset/confirm", wrapper.ConfirmPasswordReset)
+	router.POST(baseURL+"/auth/password-reset/request", wrapper.RequestPasswordReset)
 	router.POST(baseURL+"/auth/resend-verify", wrapper.ResendVerify)
+	router.GET(baseURL+"/auth/sessions", wrapper.GetSessions)
+	router.DELETE(baseURL+"/auth/sessions/:sessionId", wrapper.RevokeSession)
 	router.POST(baseURL+"/auth/sign-in", wrapper.SignIn)
+	router.POST(baseURL+"/auth/sign-in/2fa", wrapper.SignInTwoFactor)
 	router.POST(baseURL+"/auth/sign-up", wrapper.SignUp)
 	router.GET(baseURL+"/auth/verify-email", wrapper.VerifyEmail)
+	router.POST(baseURL+"/machines/cashout-codes/redeem", wrapper.RedeemCashoutCode)
+	router.GET(baseURL+"/machines/deposit-recipients/:accountId", wrapper.GetDepositRecipient)
+	router.POST(baseURL+"/machines/deposits", wrapper.MachineThirdPartyDeposit)
+	router.POST(baseURL+"/machines/heartbeat", wrapper.MachineHeartbeat)
+	router.POST(baseURL+"/machines/sessions", wrapper.OpenMachineSession)
+	router.DELETE(baseURL+"/machines/sessions/:sessionId", wrapper.CloseMachineSession)
+	router.GET(baseURL+"/machines/sessions/:sessionId/balance", wrapper.MachineSessionBalance)
+	router.POST(baseURL+"/machines/sessions/:sessionId/cashOut", wrapper.MachineSessionCashOut)
+	router.POST(baseURL+"/machines/sessions/:sessionId/deposit", wrapper.MachineSessionDeposit)
+	router.GET(baseURL+"/machines/sessions/:sessionId/mini-statement", wrapper.MachineSessionMiniStatement)
+	router.POST(baseURL+"/machines/token/refresh", wrapper.RefreshMachineToken)
 
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xb3W4bxxV+lcW0FwlAhXTsAi3v5CQtVMBo4DjNhaGL9XIkbkzurndn3QoGAZFs6wRW",
-	"IdgI0N40ReA+wIoRLZoSV69w5o2Kc2b/yF1SFCWRUqwbebk/M2fOOd93vvnxC2bYTce2uCU8Vn3BPKPO",
-	"mzpdrhuG7VsCLx3XdrgrTE4PzBr+3bLdpi5Ylfm+WWMlJnYczqrME65pbbNWiTVti++MvWla4u6n6aum",
-	"Jfg2d1mrVWIuf+abLq+x6mNGzamvN5OX7SffckNgu+u+qH9FZuZN403dbIz1qe4UmOfonvcX26WxTDyc",
-	"sCduIvmiyKrPdK9u++Ihf+Zzr8BpejN25nndEX1Z1Onn3LE9c8mdPuCep2/zfG/N9MFsh8YvFrX+kAvf",
-	"tTZqC6ZdPpWmd/LIfsqtfD8ivj27afVaUeuPXN3ytri7YW3ZF4pJiQn7/GOOOqCPi8z72uPuhZAzJ/4t",
-	"vVmUC4gj4drWjlH40PPdqR8+5665ZfIsZJ/YdoPrVjGHxG1FtmR6LiWjS9qc5qpvTFH/MsMVC7tthj+m",
-	"MtHCzppwxzyemEFv2BU3fNcUO8S8auz3ue5yF+kYfz2hX7+P3fDHbx5hBOhtjBM9Td1SF8JhLWzYjFBS",
-	"457hmo4wbYtV2X3deqo95J5Y/3IDvzJFg0e3VdA89d6dTyqfVNAVtsMt3TFZld2lWzTEOtlZ1h2z/PxO",
-	"WVcFje5tc5HvFP4LIRzLrnwJA9mRexr0ZBv6GhxAACMYQkg3hjCAvibb8iX0ZQcCRr27OraCxMX+wMV6",
-	"o7Eed4eh8Bzb8pTbPq1U8B/DtgRXRKA7TsM06Pvyt55tpYW4gD4ygzAFb9LFr12+xarsV+W0mJejSl6O",
-	"y3gr8b3uuvoOBXUyzK3SpEfeyjacQl9+ByMIsY17lTvnsn6WaXElKer4P+j3AHqyA6HchQEcofcxDsqK",
-	"e0uxQiXEXty57EAff2swQvNGEMB7OIS+Muo35wzsoka9gZHsyo7cxY5hJPflvgah/A4GcABDCDRMWrkL",
-	"PfobjMGXVR+PA/cx87EebLYQ9fq2h3eSzN1EArK9Iqj8BCEcwSH6BP2RR8h7Qoh8LTs5fHzmcl3wODEv",
-	"CI9ZXkzERJEbf1LWabKbzXF0XjSyONWWlvDF+V7GWzDEYGswkG3Zka9hqEy7uwzTvsACEaX8KYRwKDtR",
-	"br1Lk/9e5XdLcxMhUu5nQ9VLEzGEY+hDX7ubUnQIvV8qQFulXH0rv4iuNmothdwGF7wAw2/JdcdxsTsX",
-	"hj+nRlMMO7qrN7ngrkcjMLEDrMCx5KiyxCiW1SbC9Xkp4/OztO7mFdLFrJhOY4uu8qF8fQPY4t5SPZWr",
-	"kWVijw+8nM4pPKn8wEi+QseECQyhXyg2I2Shkr45SCzUtXOr2clJb3R/cx5R+8M0506iG3pUYHoYd/k9",
-	"5od8ddkoT0d0HhF83WB+BAEB6OfImF0YyN3YWA16aXXp39LAWUW7bOhe/U++WtDzi/jiRzggP2HtznAG",
-	"JugexoCkUWaKqn1EVzi2o2yhP0Hvf5wX6FH/yyOTUtS2YdtPTZ62/te1pm7UTYuvmZfAVrRMet+u7Vxa",
-	"Rk0s+04r1SEcyL+jdIYBnCjZMISAUIOhk23ZhRM4kV0NX82GViEIBnKftVYkfcbWAIgyi02E/vVYIFj5",
-	"hIlCfgghdk30FsqXqp6cInWoychNk2PLnOAV+U62ib4O6cFqp3MXqQsRnY2XhgfJzbMqQ03t+EyvDKQk",
-	"IYR3RVUBY5rInXPWhGiv6bYmnJlLE7tyC9cEigbic4jUQXFEhaUWYcZinA3rysrED1n5MaGjU4tJQ+ct",
-	"Xua8+Wxmvq0SN2zSDsFKWVlEO76zaFkxLdqD8R4UEDMcyl3ZhZ8hnLXwFu8uL3umf/k8ObZPvrhyThI3",
-	"0JAplR6Ur+C9hjkC72IGmh2AwZgIT9/GnoMr5VTajz9Dd6vhjFl1/bX2rb69ofr2qlc+fFEvq1MLi6yG",
-	"Fkd1v2hh9AG/3M13Pzo6Mwecx5cnlU9u8Ib7LycX8UkmD13ucau2RseAqLRN2e+OCzjWl0ifxdl5SsS3",
-	"R+UpMmwg/wYDtFf+AwZYXk4R57IjX+XS9CEZ8GfV/4qk+xt1zCWMmft6HPFYDh+/xUnJh5j5nrltrZnW",
-	"zJwf52HdMLjnaWnJzyXzV+a2tWGxq9GLmYO/86pF6GUFX4AKG39q8p90SGBI/g5pBhEqhRXBVFOgDegx",
-	"aYYQNUYAQ0J1F0Z08qt15YdX1CHVsyoFTX/GU5ioR60ar3xTWvuIli4S3qT6nYjajJMHcJxhSwg+vu4I",
-	"nAku35kBrn9N3zFDsMk29p7dNysE29fOFYEtd/R1wQnaXNqtqsH/4N/wYylFYDc3Y22t6qDYtEOAk4tc",
-	"M/dAl1nSphrcRu4OaF0xJTu13NClMohzYPl9xJg92YW+Wp67iRBUkm4tOaFdPNd4k+o1IswMBMeTcRx7",
-	"Sq99kRydzi/EPPO5u5OuxKj/LjBrFeZqz1fMM3VJI3M7e1lZ+pKsc5/HueS7jei4fLVcbtiG3qjbnqj+",
-	"tlKpsNZm6/8BAAD//9VUwpnANQAA",
+	"H4sIAAAAAAAC/+x9a3Mbx7XgX5nC3g92FUhQipOyWbUfZNnOVRLZKkpO9q6vNjUCmuSEwAwyM5DMq1UV",
+	"H5FlFxXxypWtm0qtrSjeqvsVoggR4gP6C91/YX/JrXO6e6ZnpucBEgABcr5IBDDTfbr79Hk/HlbqTqvt",
+	"2MT2vcriw4pXXyUtE/+8Vq87HduHP9uu0yaubxH8Ydl1/o3Y8Je/3iaVxco9x2kS0648qlasBj7huC3T",
+	"ryxWOh2rUanK5zzftewVeKzl2GQ98qRl+z+7Gj5q2T5ZIW7l0aNqxSV/7FguaVQWv6rgcPztqgTkbvCW",
+	"c+8PpO7DBAL4m8590iK6RZgtubhcEKqVuktMnzSuRZ9vmD6Z860W0a2w4EYASKZvObidDeLVXavNP1Zc",
+	"cp+4ntk06B4d0AO6xzZol31Hu7THtgx6QrsG22RP2HP4tMd26D7tsidsu2rQQzpgW3TANtg2e2bQV7RL",
+	"T+BLeky7/N2ewTbpMR3Q1/zdd3RAj+gJ7bMt9rRSrRC704L9rpve6u+djl+pVhqk7XgW/CUhq9zVrMhf",
+	"tdzGLdP115NLot/TA3pCB2wTpt+nPXoEoBl0H0Clr2mfHhvsCX4/oHu0x4GuVBOYpkOLcC+r8ngj4Kjn",
+	"qMWZtvVrsq5BFY5LN4qd6ClwhXzdtlzijQG9mqbnf+kNB41ttohyucMfvLrT5jti+aSFf/yTS5Yri5X/",
+	"VgupSE2QkBrfztvwErwthjNd11zXnx9OHExT7LSu4zNnPbNTHMB57FJ0g/I2paHZlQDD80GC4db4w7E1",
+	"xqBaQ1oshk4Hii9x8WFAWsQReYsuMfFUXNP2zDpc4Oh3y8T1Fh+4lk+0BOdax1+9jaAnF0xaptWMHCr/",
+	"RjNM2/S8B47byF+wHCJ4Q7fo66bbOC9SoqO8L9kGHdBDA6nuW7bJtuge7bNdg+4Z7E/AKzhzoD3j5s3a",
+	"v/zLGYnOBxoQXgDhp0e0R/eB09Ce8YHBvqF99ie2wXaAKwGD6gGbAxbWZRtsi+1oz8qyf+PU10gjOcut",
+	"G5/PIdfYN+gB7dJXyEoOaR/Xv8fZzTsJCbJCYDRsg56wHfYYmC2w2314uGrQE7ZN3wBknF/2JIs0wnk0",
+	"3Kla8XzT7+RefkCS2/xJLUUM0UXuanDCwRTqbuTRTJjvY7Np2vUzk8xTS3DqmvggaZBed0mD2L5lNj0N",
+	"Nv0FhZoTtkN7CrZUUSaCM1J/jglA8H/VoH3lEKv84Ht0nz0X773Fu8I26Bt+LxBzQRaJkZjgxrVN3ycu",
+	"APe/3lv46srcR3f/95WvFuau3n2/9tXC3Ed3H1599E96ymPHXuePX/lFyvOW/vkPdI/Hdh/mUnAIhkrb",
+	"/hue1zkzmpwFVhVTsgC9ZdnXV017RQNsBlE/6z6KgbNBux2QgZDr+dZ9UqlW7jX5ndWxtOumt/qJtbxM",
+	"XKK9qrgvRF2Voqw0iO20LDvQKjRPRIZO/k6+bpN6yvixfYhMprxZDWCMTKffJ2/1N+Q+aaasE/5oWbbV",
+	"gg1cqBZYcfD0leqQ8PMJ06B0Ov51p3HmOxGqniPRNMenPRRlYsHOFOVlgXYW8LBwEfk8LJhuNMJ/eBxD",
+	"4I1mNTnALpEGabUlisbxvEG0pOjKgp4FeKTuEl/7yi8KUC+cLxglB/AkDWsTuwHDwqANQlqkEZwg/FUH",
+	"+aLZJI0CVM7p+Evkjx3iDWeiGeakck9niXidpmZ+2/GHUOQ+d/wCGhwOmQKLR3yfZBDB05D6pvNAb6hr",
+	"Og/urLrEW3WaKXyk024MR4qKkNbYxPixos6VtTfX9fbIuvg5elZj5yTJPcwa+xSbo9uKlmXf4Cu8koNq",
+	"4a5kbekSaTeJbXmreivp6a5AcSDT78Mnpm9++nXbcf2Q/kRhG5qJ6ciX2UBDsmk1tURKx8jEgFqguYl0",
+	"idSttqU3Ow/FmqSRKab6/F/aZZtRnRaV554BBlTU6dGwigZW9pR9o5iKabeKtmP6jm3A06DhGP9aoX+V",
+	"uvGLeYO+nP/XylDiOQKauSOjpfHVEDPjRgZc1gnbBW0QVUO2Td+xZ2BiAJ2PPYat2KbHoAoaqOcfoYYP",
+	"W4S7+Q53rotaPts0AlllTFcgGD/jLpD7Vl3Dh521SrXywHRtjs3LJrAxHaf9zCLNxqeu67gabw78ptWP",
+	"WsTzzBWSbxDjQ4Qv6NbwWZMQ/5+J2fRXNTAA5OupHAz4tP7HlllftewhSNRN/oIAJMGuYUQY3jZTlSMb",
+	"nB+3CUlhuM7yctOyU9517PTffMc3mwU0Lv5cMFY4owpadB1VucHhbipbpzst1P0behtmXXybZ9hSTBux",
+	"O/qDNPRFDDfogzqkXXrAdpAY9dgW22S7BhpsjthTsNQYnG4JpxXbRjrYyxd4Ta6qm3bmcjNUvHr0x4IK",
+	"EQwuhfvYJvyN2yj36RHbDa2OXbDHJuxVvSF3BynZAQyHhtZ+tgoRtxSD241twATVELC+mE4LHjwGB7qJ",
+	"H9imwY1qUd9d6tGEu1UtoJn8yum4ttn81Pbd9dGpfkWcwCGgNxqpB8o2OfMB23rVgD2hR7TPWe4R7bMn",
+	"0iYpPLbwEXa0Rw+iRu9xeAeWm+bKis5qTn+k77jlHQ3yuwY6kOFIn6DNuxsg6jshc8DDhypmDR8EMAr9",
+	"KuZBl5zRI55nOfbvnTYSw3vC5s2Fit+DBMfDAqp6B7d8vd50PJLn8HY6ft1pRV1b7bbr3OfGL1IH6qzX",
+	"gF1ieo6dJsWAHZvvPRwG3n3arWiHUcx5Cb/PJtuhR/C6IQaLHPWzqiAabBNveg9lyS7bTV5z7W0ONkt7",
+	"J14iUdjkKKXS+uBeDGKIp8YFALTv6CB4Ewl9LlplBiD8kLiG4AFim7THL2IkqoJtcTLInmJgAr74DWyD",
+	"9sIWCVFQZWZtuAK/EyFSxQIY5AXOM5L96sGahjY2V7RyXt29r/1+zdLLhWv+uvb7jqf3iH9dwIcswjPu",
+	"V+BxPjUfsIpgp6xRoxWukfXiNAW2Kc9kgwPq5heipGafGw2XeF7KXg9NtOsdFy63fs8bpO60WhbewLEE",
+	"KDVN3/I7DRJ5uOF07jWVUe1O656U1u2VYZ5PDaMIbsfQsv0X8k0dtyhm0hYjZZmzRSyGPGtlo9RNiKxD",
+	"OUrF8p19kQUkqZbuENVa5te/IfYKqFdXf/4L5HTy85UcrFJtyNfm/ufdhz/TW52zcaFlfs319Y8WFOV9",
+	"7qOF4dEkGOrKh5GxrnyoG0zikLIDV65+mLsDI8KwdE2/WunY1h87RPzsux0yLAZ+iXbRFDw8GwpmY5tw",
+	"vCcxrhVSvQLwc8V2LaIsp7AAObB8IQO+NDMCaHlLxGwQN9dKAmezZjsPbK1cVpcuyeKoEXoxNVSnYXlt",
+	"YntnhgtiQEC/H4bOJ+0niqQuNn1Ic2TiB2GCuG0JGbQYZAlriAKY5yz7D0yX/Ja4XtS5Max7MpuWhxsQ",
+	"xh9KyhyeWlVFrSRwEYRRbSxihdmo7Pr3iOnnYXPWEiP2wQT6atRULgH3RBTYQDGSon4PUi1I7T0wF7PH",
+	"QgjfDyJqjg1hIAUFBfQDjU2gW9RiWvziDLMFGvxR2MMvPsjhDnFvzakwIePYv9AprRpVVEcEJEJztSuJ",
+	"N5F4gCx9DMLjDtlG1J6k6mb7EPb2Z9T8XgMGHHO7HT2B8+VR5PQV28Ywqa2YKkffzhv0b7RL3yCu7CY1",
+	"PWFK2Me4KgGBwTYDCJ9VqgUJSfFYe/sM/kwZY87HUEMWMg46IwAoaqGNCvJZB89lglIWvPSyYBrORR7U",
+	"5GDghUfjc58eGOzP9JBt0nf0iG1zyo3Xtx/JC2G7gb0ZjVHCsyi5wYHwJw7YprASZWK7HrlXrPpvLHst",
+	"1VVYOJZbG66tv6NiztvWin1DQ0oD0XUYZpEhvoYOtZhQXdTTluVh+5yY7r31U1klzPum1TTvNUNs9vJs",
+	"xLQfyTPa4RlFEFr+BKQHzix45HQywUjvRSgqMRTR87ONJpbnm3pz6d+56IOr2hVR6sgHkwIOLuIY7wXs",
+	"yWOt02DV9KT0Hb+GGjkLJTL2NOEtOIuVfcwGHEW9yDO1oryBHg+QG/dolz3jNEW7vHRlI1AbYhM+T6a5",
+	"oVN/j+3SgxRXxVjMTOOwFwU4q3h+JXKpKofuKmuphZMZa5YdfDGJgNtbpuet6dLxxpeMOcJsuQwUyDb4",
+	"iWVf8zziyu2NrZ+4pOXY63cKMSecT7HmZGH278g9SKayFftPfCHRySODZ6zmunirwGI0ollwPYvA/oV4",
+	"PA9wOWwG1EvE7GjNTFkO/Ttf3LkFghUqUCIPaQ8EI2QqXYzT2kOV50S4iAf0FRKoMMnkJIxyoIf0iD0D",
+	"f5SI8cKslD22zR6zP6H+hvxX/tRl22xLaGh99idUzwW7PlvOW2auW7BfK5bnh3rteaKtVhMYVu2P40x4",
+	"gfNwHrYqLSeFE3X/VlZqik0e3Cp8NvEBo69ngujYy5bbGipvZljEgB+XiEf800xW2HrsiyMqDs4E9Yxb",
+	"POD0jkhmzTHZFOM7LeKvOg3VsBBA6fhtsRNrkew6jbp7o6jlZCQ0WJ02WEGeIeWW6yxbzVSrxxAZvqkS",
+	"Zdv0Xcder2t/9Dpuyos6FXyJ1J37xF2HKCGNd9qN/xxInEnEzxIpo+Pc1QLid1xxvqeIm07KL+mTBBQ9",
+	"RXk+rbq85DQjUTX1juc7LW537bTbjusH8rLjomzdsvR+kyUnHYFcMUsWYiMkiTOAL3Vwp1pmT+38989U",
+	"bMVqaxHsNM6kjkfcaytRgLJE3vB5BEMVfiMAhAvVb6jvN1PquIg0w2tBJF+eFoq+DG7I6MlIH7Y9qrIv",
+	"Mtzrego4P2IkF6Skn8hEY/Y4CAZkTwwu9YkqKdy1ckC7EuJ+NOE8opKhx0Ab3Pd9GONEB8OOdz0j/ShI",
+	"7BzOT6qktup9PnWXtM2o9UZBe+mNaaQYN7mjqvBC5WgZC5VprcNhGdvOxK6ipZKGctG2iWs5jU/tRnGU",
+	"5a/c9k13CDz38Eo2Pl4/FS+pRvyuKgDqCtSTTpyTivEJbI3fw8QJVmN0I4rLURxUF5unvYeUKi1XLczc",
+	"jquPkTwVMAC+RTN7UMRAZzKdN+gPYHndluGh4eO8mkafmxLZjlIXQZrdDB5Aj4kyx6ggTi557rRWoeyU",
+	"N7G52pORMb9pgeNjrgfmnNrjmyZEq2GjOVjpk/aXbUX/SpTSwNButGXL2icGitHAjkCfCDAslnwAppCq",
+	"jA0XGofx/zf+Yig6cjXFfDJ6Q1Vi4XeC2NncPLRR5Q1clqw1XTZ/egabVH1v2MvOCJMAfWd49hNA6zt6",
+	"UB84n5l133Gvr5rNJtGacHz5zJ2COk70+exp9elH+kujy+vPHP1T23WaTb0oHWYEJeV+18qfXbzPn86E",
+	"Is3fmkoazrjh1fSd+dLTGmSKWxWsM8bOZRofiiunWYaKauU+ca1lizR0ArU2x1mMFdg8AyirwU4EY1bT",
+	"VWHY3d9Z/qpqzByH/Sarys+pjTsR3C6wIZm2x9+aTauBfDslD5fA18X1KCWlV5fFOoqAAg2XTZZb69xr",
+	"WnVec1A8JHIVj0D9CeTXtxg3YtjmfWsFzDXzIbP3jGu3bkCmofGr2198XokDUq18PbfizIkv/+A59vyS",
+	"+UCGUihgftFOC1x4AVk6tCud9WxHSiyp4AwLBc+n7LiWv46lCPmR8sKH14QTyQJYVmXkIEe7yv+Yu9a2",
+	"5n6NJRTlIQblFz8mpktc+f49/PSZvCa/+t0duKg4W2VR/BqOsur7bSUmKAcI8dScJJcxUGB9lmDd0b39",
+	"2LTXjCXi+WLTLL9JxNecRHBLWOXK/ML8ggzCM9tWZbHyM/wKb9Iqbldt/gFpNucw/Ln2hwdr3vwfRHbc",
+	"ijZR9QXbpq+iarfwltG+POF4viJPlz3kphC6V4nZwiu/JD7mFcEt8dqO7fGTvLqwwPmT7QvWabbbTauO",
+	"L9YkmF5QhTIn6cjjW6oxIrwCb164jh59a7z3q9/9+vb7XMA1Vzy4tXiad+GbGho9a7KWZu1hIJg9qi27",
+	"hPwblyUcT7eB/4F3YoA7dCAic6RJbN6Ix/sI09mB8tIbfr9B02TbRmhNCyPCeuw7nlqX2OlrAPhnCKKs",
+	"NA244Jot4hMghF8JbAX8CHFVFTxDQua7HVJV9j9PJLw7xvMNiULyiF/Kes2JXYS78cEIoYjzGz3CYYFl",
+	"tgEHha5kQaqVeD4O15WJ7A6YMmiX7gmfdp8eyPImtfDagjYENovn9JDD9rNJwQZqF/fiI4BPuJHxHbKW",
+	"PQ7LB5PFohPai5iKAIifTwiVv4faqDxDFnn8Lo8N/Zb26SvMNsbAtg1BersRHomXW+VuXykeHeHGeXRX",
+	"oXfiqxyC17FzSd7f0UyWRvT0ROpLe/mSkim2EdmuklCVhKokVMMRquUmIX5tNchYXEmpucKrrBxigQa0",
+	"6UWCkPu6agx78wb93sDY/T6vg5N4Cgsi86W/gl4Q7Dval1UPoPhXl0ehLeIJSbQB0yMXqKtYywV+ggkE",
+	"AnHXJNtiu+A1CGLddNG93+Ekfe5sCEyatCvcDRpa+0viq5Wixkj/1Gm0uBs7AnTqdVGL6GpPoyRAxQnQ",
+	"jN79ZLBJ9uVXy5Ol6LCBpURKIuI2Y61+HZalXZubci69ePLHDnHXQ/kkSHItuMfxJFr9qE2rxevnBIM2",
+	"CM9rXry6oKZyLSzk1XDVT+AsL3skZYaFaqZ/8szClDYPfui0gVwnZkZdNg2CP4/T+1I8K6njVFPHaoY1",
+	"CiF5LQ5mI6xvypOiYtQQpJ9kY6ywJh6ecixJ7VjTDCsRM4/5ydCPqhekJ2lILi/JcjOoWuFy1/LHTmN9",
+	"dIcXqf7y6NGjuDL5KEHTroxh8tDHnkuA8M6nHWOpPJbUaeZkt9rDIFbtkUiQr+vSHf+KhpKwQZBSL6Mn",
+	"lSS5rxB2pS+ZoTM/YeBySGjyjU9qcN0ZjU9jo2l8VcVo2sKoJy9GyfryRCFSt6RcpdmrKCzPdf024+av",
+	"DxY+Oh9g9jCGr8chya4BccnJfS3SWaKwAj8I+ob1sQpCL5LpX5ju/5L414P5J0z2R6Ym63tz5CQI4Bv5",
+	"0b4ZDS40+PW38AjYju4QSgJfEvhREviLrsV39I6MAXuMByLlYITrgG1jJ5Itvl2x9CvaVbwDugp084Yo",
+	"zPIYvZD7XMzmpeZllg2vy4wXBxE0GqgOg75h25gp36VHsTao9G2Kjg8+3HMjwqOXvaONlMYge5fEvyT+",
+	"JfEvpfvZku5rruyClhMs+RrNwjJqSE3SiXMtLZUx3sN8PPmYKIT5vp73BJ3ZLiD/iXadK/lQyYdKPlTy",
+	"ocvKh9TSvxkMiNdt5UW7+xo/ZtYWzxv0H+HVSTImdJHGknv1bOkTBdhz8kmcQ0DsWdG7pOklTS9p+iWi",
+	"6X/gXQqH8Bv8GUs/HuI6BmHWZMRolSDci/Gq+32lgj59W1UqSIoi/Mn6Q0ozQp45p1YgYjvzBsBqYPEB",
+	"APmJsO09C+rrDLBfAKCFNMrBcYjugJnBiqKX4wQZSEpc4bLrtCracTL7A+gH852RDZURUHllQY2o/Pns",
+	"R1QS23etIXS0SCPQPD1NDl5IS/sPaUSm/cgFLPWzkpeXTqKR8UgvKJY1nH89TObWaTLYNfQQWWiPfQOO",
+	"nhO2m8eIbiugnDszukRR9DEUKET3w7PKpfrq8IUo/8tM5Cqpf0n9S+o/ikB/3ndMxslCiOVTzX1D1SOI",
+	"FODFC0OdRfW+sM0gNzHSWSQeIIC11Hg/s35O1dVXMDPbFuOgshuVBWFscQO/RY3qbYQ5DQV80BmA7YoJ",
+	"43FkPHlB8TDpWRonjhcmWDheT3PCKRAqq8nkFl2l0AxH6JJblNyitPtdOp0mKHqf4cQRVXdlFEE8ZX/b",
+	"EKwALm54S4Cf4FL3gWuxbUyuVx8YwomDDTAEk7gTdM6YfReO2o9Af+WT+1qS6ZJMl2T64pLpEViZ9hDC",
+	"x9p6DFXZq0spGf/fgSZyEgNNUg+xogsWU4qHMPMaKqnWqXyzVMyyE6tbnzTvLJtNj1Q1NWBLU9T0mqJK",
+	"FlVmTc8Ite14xM2gs1Di9C2PX3onqOOBLPEgmh++DVr0DNgT+LZqYKfDY55DrVSc6st86zRTiOnWV79E",
+	"gAoRT/kxPITSYB+nksH5FqKPsPm5lJEPWYgm/pDsiqJHo5JkliRzRgoEIvrXHsJ/YECQhU2HkVWhUDR3",
+	"fmpvQ4b7Ey7oNTljERsAB3OKEozV/SpEk8Ryc8lSMHAxaS3/DEqKVNoZCsLyQotCTy9zHdUYmXSJR+zG",
+	"HPYgWc+wt75Ai1/QI5vbSMWJSgMszxd+CvGNYdsA8L6xLbEYRV8PBFO2w58qTG6XEOTfcohnhNaeHoGV",
+	"LVW3nDc+H5TEsCSGYyGGkzK+vhDaaRcCo9/QXhrF6F5eCi1aV+nrRMSrpaHz/IhTYy1NhWqPAL4a395P",
+	"fZpfalwn+zYMB0mNmcAeWpOkyaMPlVB6XE+6qFoGSv5dHmtQTO18gyMADJlEd4IpERLzStJfysHnQWXz",
+	"aGvbqt2/cgqzAPdaqT6rPWwSIDJ0hLqqa4B1rdlUbALTqqvn6+Q/oeezx74NJc7zvt7l5Rnt5QF2DKGm",
+	"0V57ofFm0SVmI3a5ArtOeoTqS6wrvZ8sPC2v0Nv0lkG8WHPYK2jMwS43GtkdfLbVS8CjSg94adoJ34gp",
+	"5Xef8jbXJ6ki/CS1ih/4FaUHbFc9KqUC+gA16J7xs5CGD+je7N9g/QXVMEC14Re/uU3iE80d/gm37oj2",
+	"T3GHP8FBL2G/rxi12OZ7WNY/PgMVO+dOWzX67vLIAWlsvqDEHKnYPgjIA+1ppWRx4+1lZ4YohFYgLyyG",
+	"611kxTxkf0nb3DjVoXuRrh68e+wloT7hTo/KaHAe5CezBYjBk6s4N+5dKvJ0SjUlRwqqtZz7w8a30n0s",
+	"e/sm9GrtC9vcax5+FfZyXsysDmKIbDjRg5s9jhxvkMnHHlfDrIaeLAAMH3ThryF1vRksbXIktgzyiiDU",
+	"MIYaeVz5Tc6CGQryjhi2RlG0lE1Lk3LJqcbKqXzXtD2zDrOekVvhSMvETfcNirRptdihyBHnadtY4WMf",
+	"S/G+FmnXKXr8HTnXhAX00Xv35EpQ3Ui7YwP6CtNL9nnjEdDfD7GBdzfS5TyskRL0xXtrABrRN+Ja5hwA",
+	"NrKCemDHbFt9GmbuVibpeuQBxtm2f768CJRwQa8uXB0ZGLeI3bDslQDd9OZ4BQClKTPvCZMRb7SndC/E",
+	"SPhD/gVIVyXnmyW96PRmmUkaoHWcmm2K1GP4Ye+i8rdl4nqLD1zLJ4W4W9uaWyPrQ2hd127dmAvKMfaL",
+	"hw+CPoTQjtofKwYtLOXj8/mR02LYIqJ90S2Zcnpyccym8uyKOkfVA5TRs2wz4FAnaFR4C/E/J3iYr8NS",
+	"pfMG/Zt8cS+rk69MohWizQmm0tKDNNcrx9LxyGF88GEa8i6MZe5G3mXSuHonLyZwNDzhPfCgiNFuNVD6",
+	"aFcm8iFzwX73kaZMbLeUInKkiEmKBi/Ryvctt+oZ9BiP9TUdRLlaj769oPRQw/lrD9fIep4f+kdEn4PA",
+	"ia9uV5rjWVKwfKUVAZhFn3OUbwzCTSpdzVNOjqInd7GdyLrbXzfdxjCuFqjZtzFcvhC2foVZRtwGyW0U",
+	"F/YBgALtj2DIwq2PcrahFPIng9YctzJEfE2VMrjcr2S8Gdtmz0LE3jbooeKNmTewztYxQBfB/mIy/p7B",
+	"/iy7eQ6ERUwXgnHD8zoEkXRcvcrcBs4x6aKTOGmDX7/0ewTi8544pu/KgpOlGD8sEAdYU4Vr6W+4SVK9",
+	"xXHswtjhFA1AXvILSSTjnL/2EP4DT9a9plNfy+kY+QorAx+GbsWoTLBd5YiAJcQkycQPEBW1xV0GvPsx",
+	"PcgdDDFLBhBXIy6dvSRFP5GUPEFaP4aFCdKar4Lw7ZjJuFeFlh7oDqukqtNPVZUzjNNV2r1kFKlt2elu",
+	"9ZeRhNtbNz6fQ0q+r8ho8wZ9CU9A2Bb3S8avxCH3+IqWT5zgCCufiP4SfmCooR7OoUk0u811nFuWPVEq",
+	"Mx5B8ZZlX1817ZVpyr1VTzjIvi1D+WcjXCq4Uzx2oSuzlGv0MKR3OTyrpL8ToL/eqtPx5+pOgwxjEwKy",
+	"2ccQlYglROkPAfcWe0PgSrZ4KEgi1vYV7dEDhYDrLUkI43UEcbQGJbnqoj21JRz5diUcuaBdKblTpSVp",
+	"YldCwa3iCbUD7BU54G7coCa7YFZDojzILDwYDdYDKz0Qa+pjiKC0MsFIatYJyChGyiXEmYXgEpQb7XFd",
+	"Fw+S9tDXhU+iG5tzWdxP6G3DhaeYwStsSnMqgxd3/Kq3aGxN+uUMw/i5R28AU8hF2r2fCi93nlxTk9GS",
+	"aLPjXPIIhex+2FWsVOSmwjxWLACuFobSJs1oGXayTLYPxO1Q6k4XmUukyU+1h/BfEX+6qshquEY1wgfS",
+	"HQ8RlpCkt6ZdJ80ovS2gp+ISZtQahlxswLZKXXF2rF94ZnGSGYRWieJ0WqJzSWiM7NNUqCr9K+TMb/CL",
+	"b3mtpA3UsgfIEJ5h16VesmkTiKHfp4h7yYdR6INx92kfs597eO0MwXAOab+KX4B0g8q1qt2Hhc9lSxEe",
+	"brnL27In6Nhnlt24KfegUCH8pukXI2ANp3MPC+cF2Y8fqZmKcx+FuYp2p3UvK/ux6dinnfPKh5FJr3yo",
+	"nzVRmS7YftxMvCOHKKIZ6InZwt19XKlq4XXNhtXx9NmaP1+I92Y/bU6oKMEYnSfzDvKD/kJiwCTyWUec",
+	"fKpc10J2hc+J6d5bFwvPzzyVwxcyLjyP39ySH15yi0dASSM8JkgfqT1UiO+jWt2xly23lVOgOkz82g9r",
+	"UqvZYoFecCwa0YYlq7tV484Xd27FHkGDhYzJxe+EUgNtZzXOmOsczqHyJdURprF1rE/aX7bFwqapJGo0",
+	"EZB75kE046VRSwIzY86ZQ5lQOimR/0dBHDCLhu0mhX/FZimMlUAKaLemJgDPsokFob16zmkPSdd3Sg4v",
+	"fVvlv+FNwpTft9wC/Q4Vhje0d9kTKjv+au3qsnkWdgnML0xCOeFamlJmRmGQSVWNm+ulzw0os9CqRFd2",
+	"0bagrwnVlpzzgfOZWeeN0saS/y/HD+3hk+NmS6Tu3CfuOleuUwq0sG32OJKezstr0y4PZMP0vz77E+3T",
+	"w5B07UXOrIy1KlA8+PzZ3MQ6LOiutORc7AlnHnFLl1r3P2g1cnESUuCXGNFsWJ55r0kyiCaYzIMc66D+",
+	"V/LC8rD+9AubtC19wue+0NQv68BPS/cGyomUlK+kfCNBKiSNOo46IVH5+yDr/22kUI2BglZPeO0wHD0l",
+	"ci0eWsoLEIexH1yK3uFKYKVaWSVmQ/QjXSK+uz53bdnnlaVioP2n0LkPDDTyqn25EfW36Qno4seoYYga",
+	"SGHjMEXI5LMfsm1dn9jQJProojMdYrtOs5nBc36QLDpDPNcyIUSfdETXlIv8FGGJ8qAx8YJgEj4nr3Wo",
+	"1RqVMCDZUg5p2pFUYuHOzqWsk29Fd5qI71STPin8aYjfhb2FBBoXzNUx6lxVnFfIEHrzSZjNKdsabqep",
+	"uNgpQUS5F3Ij+s4ayXbqTU+7QtnNbVr6VE2TXPaP0OR6QnuxIi2ybftJ1OYYmmXPqx9fJOQhjAOddpqQ",
+	"dt1b5opVn2ta9lpNaFg5mYcCUXRNTtkmN7dush16hOLQ24BL7YkKkt0g0jbMPtjFWFv5VjdSr4fvcCK0",
+	"lb6ClbFtEYzVq0awmPbpHurpxwbtseeLhu3YdYLwscfwImxaGK61Z9QdZ80iBm7G72Ezfo9vJEjWEt+i",
+	"m/Dcbyx7bUx6aTC+mO+0lTlD4gs8+BB+A5FUNOiaDlX3//C4xhiAWAkUUoRVLKhG0W3mGsCeu2tBvSIz",
+	"EAxagGZ51oo9Z9kZNOtH+kqEHe7inYh4+lBxCyiPqAGsPhFxb88b9B9qeVWkHimkI06huikU6nWEZGqk",
+	"pNvWin3DnhjF4dOdluBkbW5lsq4FaGJ2ByXFQnV8BX8adQXf0MK4ajabxE4ThRJoJTMs9wLjB2KKqsCU",
+	"QmRhIbKqiI+BQBlC/K247KL2d58ex6+vqIk8k9SSDNW/IxoKWrSs1E0y2hRA1FOLFemOxuFxBXeG23he",
+	"NMMCqPR+fbVAr+5CyCeqQO1x4827UMEOSp1EZXvF8JtaCz2B0Lzf9c1xZcLdcp1la5xdtafhLk0DW+pF",
+	"cOrymT0vjPXiTBbNFqkR1/RIjmHjEHohiDxiwB8pF3BL8GYk3WLe4OGWqEdhKjF7qsE4gw64tUOkGvfp",
+	"Xpi/UTXQ2gxvn3B9jL+CYgkeVD9msQjfFE244R8QscF9YaihuX2+hgO2o86Y9HDAtoyPzJme98BxG1MY",
+	"K6s2DZTdSC9XR8CLWMRkstGmXaXdPTiR0VTFnkZ62lx4uvo1pBENZzEGMvcaNa0DXvYJaRl3w2tIKvyw",
+	"H+Yyzxv039kGewy0FMkjfYWefiX7tgtz0ROkm7xTwCDNkPuJ6Zuf8jUkKNHojADhLLd90+/oQx2/V7cF",
+	"BVoZyxxYN3kO2QCRjoc0P516slDaPM/jRtYe8v9F0rte+X9JD9VgisS9LHIddbaAyKXKz/eRkJ5rbnu+",
+	"iqNSnZS9gcobyu7Ax1HbEwuRkhBU2mPfsecpdFJ2ycAs5A1OdNQwV/gd0oCfqkg7oG9LEWm6c/cTnCQ9",
+	"kUdUbuXNKo8ueGRLi9TaQiUpWs1SlS4hvUOjdak3pqtqaOnFQWRhqhBTkrExGA8jNahxK2jnUmIyz0Xz",
+	"Itz6aS40GR5nUhW5zPHNPD8Qnfh9zeZcWCoDJGa49n2q1Sbibzbek4O9P5Qz5pYEYaRmZHVhhQooCDBy",
+	"SycEAxesy6jfrLJSwTkjfM0lK5bnE7d2j6xYdsEY7libbJEQFN6JbjIGgzOGriymwju/CI1YLY+zZ9jm",
+	"fWvF9B13vu6SBrF9y2x68Lfpk/feT9anB7gF1i7hYviPY2S/OBNs46S5r5j8OnFJy7HXMziwutG606L9",
+	"qeLHpc10KJupUtVj4ulIA942gr7mJEupXmnEG/+VuUZlrtF4mdayZVveao4pOzCNnIp3qXV40UyZnaWj",
+	"KbhmeavnwJ+UmSZcsjcQIdMFQbyIsZNQruPUFu8NcSEHD4T9RkSqsef0SHwQtwEu8pQwum8EiTqmA+E3",
+	"j1aKmVr2cvHpnAiUHko2l0GxvCik3DO2HSNrIxDJV4ifI4+LuOSpk4jDxJZZjQ9NoEguJ4yH1Is8XJGb",
+	"UYCcZcTYZ3M9BQvGxu+ueR5xizO76YhZv+zq14soBqocExhPgmMW41UzfZ0fir/yCp//JMKfosbIvGvJ",
+	"O4pL+ayInzcAZxqrOp6rLSa7EDc/DhmlVhZPv3j2lg8mikn6Io2lfaW0r4xY7wDX8pxLPOIXKMD4U6Qu",
+	"YqSMRPIKYY364JazbZ5sqFYvTis2IV3eSwDWmN3qOEck+PnsCZXqGg3aT9uiyvQUJZ4JJ/4MVsCYVdlU",
+	"oQpnLjghiktACJt4qBupK5EW9jtxKlDWcSjrOJR1HOLkAKiA3Zi7T1xreT2zODOXzFQ80VEDPmesrFt4",
+	"eXY05AAA+C2f/5zuzfeiTQ89nCTnmY78xJ/om0w4Lqx47BHPsxx7mCAxripBBqFIW4vl/vFQXq5aCRmi",
+	"O1TU2G0J00ijxtSVFooaE2DkRo0FAxeKGvv3jN0rI8fO+RbUHoq/8kyVGhd8cI6aKtJL5L6zRiRCFbFR",
+	"BnDMZKPFl8Fe7CYLlpfVYqa9T616ehe85X2CEuSW8YrzQ7NeJ56XlUIxVl8hLOA2f7KoTkf3cL82eUPI",
+	"LlQbgo8G+zMqPYeCeZ/IUsWB2GrQvqrV8voE56sIDuGqFFV2I3dO1Nc9p2JbEZuUYqevGmwrtQ7XKWtK",
+	"lzQX4dGSXOM9tHoFGl2QRpniMQmK/bw/WY/FY1mJrXRQzIRZQcaxXF02MxhKxBP1Le3S10osz2Jw77nR",
+	"PVliPerHG663E+dME2tuopZ0nEUWURJQXecQiYCq3KoUWc7wJ5T+3pKcDktOO+1sL01aADbskgz9DApo",
+	"9bQU8cv2mAgh1Lb7neWvBtnUp/XCFKlPuGjQ/0f/Sn+sRjsfTJru3mikhsklgX6KxsOwgN+MhtRPuouc",
+	"diM3DRQBDiM6HH4QJfh0DrFZdeBwz80ctgxJN2V/H7pl2DchIsU8mwmawN0y2B1kWvuCDF9nMzyZC1Zq",
+	"c1rbu83YtWqJNvC1uumtOh1/rg69UWuQKkFamdrMDt2Xd+pExPM+UXiVbEO7DYAAeFtcixHNOMBisQHG",
+	"pnmD/icQfRDEYsIVEDcYI/RDKxUxYW7McIPSM/h1X7RL0hVfg9Vc5yvE1ovjYfzKDDBlqx1G9Z+C+0up",
+	"W8qH2IVroqxdrGeJeJ1mWnEEma5DX9Mut/xXDRRm37FnPGElaMiyIwTp0koVDcXSYHWGNlWLNuxO6mcq",
+	"tkwwCPl5dBXCpRB6kdXAspOgPDbdF8Wx+0hCMTtxEGkM/2ySYtZLtk2P6THEPZ1wCYseqMjLL6bEbkzM",
+	"6msOcPqbvicJbWIRM+UAusn5mPAARXw/4qc4w2uQtuNZ/pxL6lbbQkgfmryfe2YVw0SgxDEWAI3YEHCf",
+	"ofo1PUbsgfwevM9H7Cn7RlRyZc/5XZdtFdOqxcP7B/REhhtGmC17LHwIAArvI3GEUi+vGa1w1lgI18h4",
+	"K9Rf5Du5JDeykOc72Oqp9XwnlpWul22LLt496eMJzmsmWMoFYhMTcp1L6h53m8dAR/XgBOU7AXSAGmxn",
+	"OhiBji5cUlbgZek7cKLSl57Qd04Ukh629RE7DNmdfeGu7WoVICF10G6MJEfofpIYd1HS3sCESQGIgl48",
+	"zBkSx5+oGfFKE98gPZX2odak8mrA3iLl/Hk16AQYEA/InghO0y2IUQDqsdjRnqhwO8AxBIq+SxDWpF9L",
+	"HOedVctt3DJdf12Q7HE5uOLznDHKXmJMFf4K5M5+QDcw2x2PVdGnKtPT0iCoOo9nHqDAVLc1qEV10xNE",
+	"PrwJ71BEQ2RHq6o4krAAccnESyauZ+KTVU5ldYEeaKXsWwmUUmwmLn+WQsYUCBmrxHT9e8TMSj97SQfI",
+	"NL4Lo415UaEB20UU7Gn2bd6I38cq9yIfIfMU5XD3uQMJTHSI7EHwvvCHC4lDcZL3kS+hHBAtJ92LlGrA",
+	"wvNprPmfg0WPq41obJopqqTwMnF2anelGUg7mwWOd4EJhprJklYYiW0pbcTU3ATtXgq3DCoeaJ4ybt34",
+	"fE46arDTYQ+xQIyJcVLKqLvaUYMX6F5IJ3g4At+Zb6EQBOg8QNo2QaqPygACIm507Sbn6GVUtwfeG6c9",
+	"X7SJLfY0zMUYj7/HbVwPq4pNulhhbI25Mf6DEFvK7Iwz+mDkLeoKHWIDjcDvEEuxwx7tJh014W2bpLD+",
+	"txDUlJC3sD+HPAX8V1gZaiHYqSPU4huoUwnORbgP7CwpdWgWPposPAl/kGbrUuiksCuxbfYsXNf2JeGC",
+	"Q+Xs6XlilXtKX/OAVeUGR5mLaFgjlVVpfkqm+11vOh5JMJpLl/RXspTRmW+mIvMvWv9F1idUfNo6QnrJ",
+	"SFDtntk07XpGq3tNKZlX6DfA9rsRD3DAo9LUaEFdPhZzXgwiA9KzXFGakTG5WyWRKW3Es00pz1/kvIz0",
+	"GuJbv+j4pw1pBdPdqWj2dTHvhGn22IJbz+7zDDyd0CI03Oe+0EV5AuM0BblGIuBpLxVwNFSVoa8l+yrZ",
+	"14iyUPdlS3J0ozzBXDG2KXqRI3rUVIIyzpDdy8YvRUzUiEKiinLLMIBnxrnlyCKEyrigMi6oZJol0yx1",
+	"vuF5WMuyrTnPN33SEustmNIRVICg+yJ0I4qQmGYVZ3rHomZQwPbYdnHGd9OyrdsBpLNj4ItmJBPbdy0y",
+	"RO1NueJPbd/Nb9wshy+Ut/wi9wyrYTX7nuwftscPslTTSo5TcpyS42RwHKz+UHPJsku81QLFxIGyvONF",
+	"k2V051bGDYMmh0LR3YHoLBHHqb4zYFv0IBp2pUl9R/hkpkRQseKcinD9EHQPyVx8GX54PviNb7n3pdTR",
+	"cZuVxcqq77cXa7WmUzebq47nL364sLBQeXT30X8NAHjwczJ00wEA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
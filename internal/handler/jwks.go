@@ -0,0 +1,25 @@
+package handler
+
+import "github.com/labstack/echo/v4"
+
+func (h *Handler) GetJwks(ctx echo.Context) error {
+	jwks := h.tokenManager.JWKS()
+
+	keys := make([]Jwk, len(jwks.Keys))
+	for i, key := range jwks.Keys {
+		keys[i] = Jwk{
+			Kty: key.Kty,
+			Crv: key.Crv,
+			X:   key.X,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+		}
+	}
+
+	// verifiers may cache keys, but not longer than key rotation check interval
+	ctx.Response().Header().Set("Cache-Control", "public, max-age=60")
+	return ctx.JSON(200, Jwks{
+		Keys: keys,
+	})
+}
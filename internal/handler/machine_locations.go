@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"math"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	machineSearchDefaultRadius = 5000
+	machineSearchDefaultLimit  = 20
+)
+
+func toNearbyMachine(machine domain.NearbyMachine) NearbyMachine {
+	operations := make([]MachineOperation, len(machine.Operations))
+	availableOperations := []MachineOperation{}
+	for i, operation := range machine.Operations {
+		operations[i] = MachineOperation(operation)
+		if machine.Available(operation) {
+			availableOperations = append(availableOperations, MachineOperation(operation))
+		}
+	}
+	return NearbyMachine{
+		Id:                  machine.Id,
+		Name:                machine.Name,
+		Address:             machine.Address,
+		Latitude:            machine.Latitude,
+		Longitude:           machine.Longitude,
+		Operations:          operations,
+		Currency:            machine.Currency,
+		Distance:            int(math.Round(machine.Distance)),
+		Online:              machine.Online,
+		HasCash:             machine.HasCash,
+		LowCash:             machine.LowCash,
+		AvailableOperations: availableOperations,
+	}
+}
+
+func (h *Handler) FindMachines(ctx echo.Context, params FindMachinesParams) error {
+	if _, err := h.authorization(ctx); err != nil {
+		return err
+	}
+
+	search := domain.MachineSearch{
+		Latitude:  params.Lat,
+		Longitude: params.Lon,
+		Radius:    machineSearchDefaultRadius,
+		Limit:     machineSearchDefaultLimit,
+	}
+	if params.Radius != nil {
+		search.Radius = float64(*params.Radius)
+	}
+	if params.Supports != nil {
+		search.Operation = string(*params.Supports)
+	}
+	if params.Limit != nil {
+		search.Limit = *params.Limit
+	}
+
+	machines, err := h.services.FindMachines(ctx.Request().Context(), search)
+	if err != nil {
+		logrus.Errorf("error find machines (handler): %s", err)
+		if errors.Is(service.ErrInvalidSearch, err) {
+			return echo.NewHTTPError(400, Message{
+				Message: "Invalid search",
+			})
+		}
+		return httpInternalError()
+	}
+
+	machinesReturn := make([]NearbyMachine, len(machines))
+	for i, machine := range machines {
+		machinesReturn[i] = toNearbyMachine(machine)
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"machines": machinesReturn,
+	})
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/sirupsen/logrus"
+)
+
+func (h *Handler) OpenMachineSession(ctx echo.Context) error {
+	var data OpenMachineSessionJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	card, err := h.authenticateCard(ctx, data)
+	if err != nil {
+		return err
+	}
+
+	session, err := h.services.OpenMachineSession(ctx.Request().Context(), machineId(ctx), card)
+	if err != nil {
+		logrus.Errorf("error open machine session (handler): %s", err)
+		return httpErrMachineOperation(err)
+	}
+
+	return ctx.JSON(201, MachineSession{
+		Id:        session.Id,
+		OpenedAt:  session.OpenedAt,
+		ExpiresAt: session.ExpiresAt,
+	})
+}
+
+func (h *Handler) CloseMachineSession(ctx echo.Context, sessionId openapi_types.UUID) error {
+	err := h.services.CloseMachineSession(ctx.Request().Context(), machineId(ctx), sessionId)
+	if err != nil {
+		logrus.Errorf("error close machine session (handler): %s", err)
+		return httpErrMachineOperation(err)
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
+
+func (h *Handler) MachineSessionBalance(ctx echo.Context, sessionId openapi_types.UUID) error {
+	account, err := h.services.MachineBalance(ctx.Request().Context(), machineId(ctx), sessionId)
+	if err != nil {
+		logrus.Errorf("error machine session balance (handler): %s", err)
+		return httpErrMachineOperation(err)
+	}
+
+	return ctx.JSON(200, CardBalance{
+		AccountId: account.Id,
+		Money:     int32(account.Money),
+	})
+}
+
+func (h *Handler) MachineSessionMiniStatement(ctx echo.Context, sessionId openapi_types.UUID) error {
+	entries, err := h.services.MachineMiniStatement(ctx.Request().Context(), machineId(ctx), sessionId)
+	if err != nil {
+		logrus.Errorf("error machine session mini statement (handler): %s", err)
+		return httpErrMachineOperation(err)
+	}
+
+	entriesReturn := make([]StatementEntry, len(entries))
+	for i, entry := range entries {
+		entriesReturn[i] = StatementEntry{
+			Operation: StatementEntryOperation(entry.Operation),
+			Amount:    int32(entry.Amount),
+			CreatedAt: entry.CreatedAt,
+		}
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"entries": entriesReturn,
+	})
+}
+
+func (h *Handler) MachineSessionCashOut(ctx echo.Context, sessionId openapi_types.UUID) error {
+	var data MachineSessionCashOutJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	notes, err := h.services.MachineCashOut(ctx.Request().Context(), machineId(ctx), sessionId,
+		int(data.Amount), "")
+	if err != nil {
+		logrus.Errorf("error machine session cashout (handler): %s", err)
+		return httpErrMachineOperation(err)
+	}
+
+	return ctx.JSON(200, CashoutResult{
+		Notes: toNotes(notes),
+	})
+}
+
+func (h *Handler) MachineSessionDeposit(ctx echo.Context, sessionId openapi_types.UUID) error {
+	var data MachineSessionDepositJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	err := h.services.MachineDeposit(ctx.Request().Context(), machineId(ctx), sessionId,
+		int(data.Amount), fromNotes(data.Notes))
+	if err != nil {
+		logrus.Errorf("error machine session deposit (handler): %s", err)
+		return httpErrMachineOperation(err)
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
@@ -3,77 +3,110 @@ package handler
 import (
 	"errors"
 
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
 	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
 	"github.com/labstack/echo/v4"
-	openapi_types "github.com/oapi-codegen/runtime/types"
 	"github.com/sirupsen/logrus"
 )
 
-func (h *Handler) CashOut(ctx echo.Context, accountId openapi_types.UUID, params CashOutParams) error {
-	userId, err := h.authorization(ctx)
-	if err != nil {
-		return err
+// httpErrMachineOperation maps error of operation with the account at the machine.
+func httpErrMachineOperation(err error) error {
+	if errors.Is(service.ErrMachineSessionNotFound, err) {
+		return echo.NewHTTPError(404, Message{
+			Message: "Session not found or expired",
+		})
 	}
-
-	var data CashOutJSONRequestBody
-	if err := ctx.Bind(&data); err != nil {
-		return httpBadRequest()
+	if errors.Is(service.ErrMachineNotFound, err) {
+		return echo.NewHTTPError(403, Message{
+			Message: "Not enough rights",
+		})
+	}
+	if errors.Is(service.ErrMachineBusy, err) {
+		return echo.NewHTTPError(409, Message{
+			Message: "Machine is serving another card",
+		})
+	}
+	if errors.Is(service.ErrMachineNotActive, err) {
+		return echo.NewHTTPError(403, Message{
+			Message: "Machine is not active",
+		})
+	}
+	if errors.Is(service.ErrOperationNotSupported, err) {
+		return echo.NewHTTPError(403, Message{
+			Message: "Operation is not supported by machine",
+		})
+	}
+	if errors.Is(service.ErrUserNotFound, err) {
+		return httpErrUserNotFound()
+	}
+	if errors.Is(service.ErrAccountNotFound, err) {
+		return httpErrAccountNotFound()
+	}
+	if errors.Is(service.ErrInsufficientFunds, err) {
+		return echo.NewHTTPError(409, Message{
+			Message: "Insufficient funds in the account",
+		})
 	}
+	if errors.Is(service.ErrAmountNotDispensable, err) {
+		return echo.NewHTTPError(409, Message{
+			Message: "Amount can't be dispensed by the machine",
+		})
+	}
+	if errors.Is(service.ErrAccountFrozen, err) {
+		return httpErrAccountFrozen()
+	}
+	if errors.Is(service.ErrInvalidNotes, err) {
+		return echo.NewHTTPError(400, Message{
+			Message: "Notes don't match the amount",
+		})
+	}
+	return httpInternalError()
+}
 
-	err = h.services.CashOut(ctx.Request().Context(), params.XMachineId, userId, accountId,
-		int(data.Amount))
+func (h *Handler) RefreshMachineToken(ctx echo.Context) error {
+	token, err := h.services.Machines.IssueToken(ctx.Request().Context(), machineId(ctx))
 	if err != nil {
-		logrus.Errorf("error cashout (handler): %s", err)
+		logrus.Errorf("error refresh machine token (handler): %s", err)
 		if errors.Is(service.ErrMachineNotFound, err) {
-			return echo.NewHTTPError(403, Message{
-				Message: "Not enough rights",
-			})
-		}
-		if errors.Is(service.ErrUserNotFound, err) {
-			return httpErrUserNotFound()
-		}
-		if errors.Is(service.ErrAccountNotFound, err) {
-			return httpErrAccountNotFound()
-		}
-		if errors.Is(service.ErrInsufficientFunds, err) {
-			return echo.NewHTTPError(409, Message{
-				Message: "Insufficient funds in the account",
+			return echo.NewHTTPError(401, Message{
+				Message: "Machine token is invalid",
 			})
 		}
 		return httpInternalError()
 	}
 
-	return ctx.JSON(200, Message{
-		Message: "ok",
+	return ctx.JSON(200, ReturnToken{
+		Token: token,
 	})
 }
 
-func (h *Handler) Deposit(ctx echo.Context, accountId openapi_types.UUID, params DepositParams) error {
-	userId, err := h.authorization(ctx)
-	if err != nil {
-		return err
-	}
-
-	var data DepositJSONRequestBody
+func (h *Handler) MachineHeartbeat(ctx echo.Context) error {
+	var data MachineHeartbeatJSONRequestBody
 	if err := ctx.Bind(&data); err != nil {
 		return httpBadRequest()
 	}
+	cashLevels := make(domain.CashLevels, len(data.CashLevels))
+	for i, level := range data.CashLevels {
+		cashLevels[i] = domain.Note{
+			Denomination: level.Denomination,
+			Count:        level.Count,
+		}
+	}
 
-	err = h.services.Deposit(ctx.Request().Context(), params.XMachineId, userId, accountId,
-		int(data.Amount))
+	err := h.services.Machines.Heartbeat(ctx.Request().Context(), domain.MachineHeartbeat{
+		MachineId:       machineId(ctx),
+		Dispenser:       string(data.Dispenser),
+		CardReader:      string(data.CardReader),
+		SoftwareVersion: data.SoftwareVersion,
+		CashLevels:      cashLevels,
+	})
 	if err != nil {
-		logrus.Errorf("error cashout (handler): %s", err)
-		if errors.Is(service.ErrMachineNotFound, err) {
-			return echo.NewHTTPError(403, Message{
-				Message: "Not enough rights",
+		logrus.Errorf("error machine heartbeat (handler): %s", err)
+		if errors.Is(service.ErrInvalidHeartbeat, err) {
+			return echo.NewHTTPError(400, Message{
+				Message: "Invalid heartbeat",
 			})
 		}
-		if errors.Is(service.ErrUserNotFound, err) {
-			return httpErrUserNotFound()
-		}
-		if errors.Is(service.ErrAccountNotFound, err) {
-			return httpErrAccountNotFound()
-		}
 		return httpInternalError()
 	}
 
@@ -81,3 +114,25 @@ func (h *Handler) Deposit(ctx echo.Context, accountId openapi_types.UUID, params
 		Message: "ok",
 	})
 }
+
+func toNotes(notes []domain.Note) []Note {
+	notesReturn := make([]Note, len(notes))
+	for i, note := range notes {
+		notesReturn[i] = Note{
+			Denomination: note.Denomination,
+			Count:        note.Count,
+		}
+	}
+	return notesReturn
+}
+
+func fromNotes(notes []Note) []domain.Note {
+	result := make([]domain.Note, len(notes))
+	for i, note := range notes {
+		result[i] = domain.Note{
+			Denomination: note.Denomination,
+			Count:        note.Count,
+		}
+	}
+	return result
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// magicLinkNonceCookie binds magic link to the browser which requested it.
+const magicLinkNonceCookie = "magic_link_nonce"
+
+func magicLinkNonce(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     magicLinkNonceCookie,
+		Value:    value,
+		Path:     "/auth/magic-link",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	}
+}
+
+func (h *Handler) RequestMagicLink(ctx echo.Context) error {
+	var data MagicLinkRequest
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	nonce, err := h.services.Auth.RequestMagicLink(ctx.Request().Context(), string(data.Email),
+		ctx.RealIP())
+	if err != nil {
+		logrus.Errorf("error request magic link (handler): %s", err)
+		if errors.Is(service.ErrTooManyRequests, err) {
+			return httpTooManyRequests()
+		}
+		return httpInternalError()
+	}
+
+	// the cookie expires with the browser session, the link itself expires earlier
+	ctx.SetCookie(magicLinkNonce(nonce, 0))
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
+
+func (h *Handler) SignInMagicLink(ctx echo.Context) error {
+	var data MagicLinkSignIn
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+	cookie, err := ctx.Cookie(magicLinkNonceCookie)
+	if err != nil || cookie.Value == "" {
+		return echo.NewHTTPError(401, Message{
+			Message: "Token is invalid",
+		})
+	}
+
+	result, err := h.services.Auth.SignInMagicLink(ctx.Request().Context(), data.Token, cookie.Value,
+		device(ctx))
+	if err != nil {
+		logrus.Errorf("error sign in by magic link (handler): %s", err)
+		if errors.Is(service.ErrTokenInvalid, err) || errors.Is(service.ErrUserNotFound, err) {
+			return echo.NewHTTPError(401, Message{
+				Message: "Token is invalid",
+			})
+		}
+		return httpInternalError()
+	}
+
+	ctx.SetCookie(magicLinkNonce("", -1))
+
+	if result.TwoFactorToken != "" {
+		return ctx.JSON(202, TwoFactorChallenge{
+			TwoFactorToken: result.TwoFactorToken,
+		})
+	}
+
+	return ctx.JSON(200, ReturnToken{
+		Token: result.AccessToken,
+	})
+}
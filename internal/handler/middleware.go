@@ -4,65 +4,158 @@ import (
 	"errors"
 	"strings"
 
-	"github.com/IvanMeln1k/go-bank-app-bank/pkg/tokens"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
 )
 
-func (h *Handler) authorization(c echo.Context) (uuid.UUID, error) {
+const (
+	principalKey = "principal"
+	machineIdKey = "machineId"
+
+	// scopeUser allows any authenticated user regardless of role
+	scopeUser = "user"
+)
+
+// userIdentity authenticates user by bearer token and checks that the user's
+// role is one of scopes of the operation. Scope "user" allows any role.
+func (h *Handler) userIdentity(c echo.Context, scopes []string) error {
 	authHeader := c.Request().Header.Get("Authorization")
+	if authHeader == "" {
+		return errNoCredentials
+	}
 	params := strings.Split(authHeader, " ")
 	if len(params) != 2 || params[0] != "Bearer" {
-		return uuid.UUID{}, echo.NewHTTPError(401, "No authorized")
+		return echo.NewHTTPError(401, "No authorized")
 	}
-	userId, err := h.tokenManager.ParseAccessToken(params[1])
+	principal, err := h.services.Auth.Authenticate(c.Request().Context(), params[1])
 	if err != nil {
-		logrus.Errorf("error parsing access token (handler): %s", err)
-		if errors.Is(tokens.ErrTokenExpired, err) {
-			return uuid.UUID{}, echo.NewHTTPError(401, Message{
+		logrus.Errorf("error authenticating access token (handler): %s", err)
+		if errors.Is(service.ErrTokenExpired, err) {
+			return echo.NewHTTPError(401, Message{
 				Message: "Token is expired",
 			})
 		}
-		if errors.Is(tokens.ErrTokenInvalid, err) {
-			return uuid.UUID{}, echo.NewHTTPError(401, Message{
+		if errors.Is(service.ErrTokenInvalid, err) {
+			return echo.NewHTTPError(401, Message{
 				Message: "Token is invalid",
 			})
 		}
-		return uuid.UUID{}, echo.NewHTTPError(500, Message{
-			Message: "Internal server error",
+		if errors.Is(service.ErrSessionRevoked, err) {
+			return echo.NewHTTPError(401, Message{
+				Message: "Session is revoked",
+			})
+		}
+		return httpInternalError()
+	}
+	if !roleAllowed(principal.Role, scopes) {
+		return httpForbidden()
+	}
+	c.Set(principalKey, principal)
+	return nil
+}
+
+func roleAllowed(role string, scopes []string) bool {
+	for _, scope := range scopes {
+		if scope == scopeUser || scope == role {
+			return true
+		}
+	}
+	return false
+}
+
+// apiKeyIdentity authenticates third-party integration by X-Api-Key header.
+// The key must have all scopes of the operation.
+func (h *Handler) apiKeyIdentity(c echo.Context, scopes []string) error {
+	key := c.Request().Header.Get("X-Api-Key")
+	if key == "" {
+		return errNoCredentials
+	}
+	principal, err := h.services.ApiKeys.AuthenticateApiKey(c.Request().Context(), key)
+	if err != nil {
+		logrus.Errorf("error authenticating api key (handler): %s", err)
+		if errors.Is(service.ErrTokenExpired, err) {
+			return echo.NewHTTPError(401, Message{
+				Message: "Api key is expired",
+			})
+		}
+		if errors.Is(service.ErrTokenInvalid, err) {
+			return echo.NewHTTPError(401, Message{
+				Message: "Api key is invalid",
+			})
+		}
+		return httpInternalError()
+	}
+	for _, scope := range scopes {
+		if !principal.ApiKey.HasScope(scope) {
+			return httpForbidden()
+		}
+	}
+	c.Set(principalKey, principal)
+	return nil
+}
+
+// authorization returns id of the user authenticated by bearer token or api key.
+func (h *Handler) authorization(c echo.Context) (uuid.UUID, error) {
+	principal, ok := c.Get(principalKey).(domain.Principal)
+	if !ok {
+		return uuid.UUID{}, echo.NewHTTPError(401, "No authorized")
+	}
+	return principal.UserId, nil
+}
+
+// currentPrincipal returns the caller authenticated by bearer token or api key.
+func currentPrincipal(c echo.Context) domain.Principal {
+	principal, _ := c.Get(principalKey).(domain.Principal)
+	return principal
+}
+
+// device returns info about client of the request.
+func device(c echo.Context) domain.Device {
+	return domain.Device{
+		Ip:        c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+	}
+}
+
+// accountAllowed reports whether the caller may access the account. Api keys
+// may be restricted to a single account.
+func accountAllowed(c echo.Context, accountId uuid.UUID) bool {
+	principal := currentPrincipal(c)
+	return principal.ApiKey == nil || principal.ApiKey.AllowsAccount(accountId)
+}
+
+// machineIdentity authenticates ATM by X-Machine-Token header and stores id of
+// the machine into echo context.
+func (h *Handler) machineIdentity(c echo.Context, _ []string) error {
+	token := c.Request().Header.Get("X-Machine-Token")
+	if token == "" {
+		return echo.NewHTTPError(401, Message{
+			Message: "No machine token",
 		})
 	}
-	return userId, nil
+	machineId, err := h.services.Machines.AuthenticateMachine(c.Request().Context(), token)
+	if err != nil {
+		logrus.Errorf("error authenticating machine (handler): %s", err)
+		if errors.Is(service.ErrTokenExpired, err) {
+			return echo.NewHTTPError(401, Message{
+				Message: "Machine token is expired",
+			})
+		}
+		if errors.Is(service.ErrTokenInvalid, err) {
+			return echo.NewHTTPError(401, Message{
+				Message: "Machine token is invalid",
+			})
+		}
+		return httpInternalError()
+	}
+	c.Set(machineIdKey, machineId)
+	return nil
 }
 
-// func (h *Handler) getUserIdentityMiddleware() echo.MiddlewareFunc {
-// 	spec, _ := GetSwagger()
-// 	m := middleware.OapiRequestValidatorWithOptions(spec, &middleware.Options{
-// 		Options: openapi3filter.Options{
-// 			AuthenticationFunc: func(ctx context.Context, ai *openapi3filter.AuthenticationInput) error {
-// 				if ai.Scopes[0] == "user" || ai.Scopes[0] == "machine" {
-// 					authHeader := ai.RequestValidationInput.Request.Header.Get("Authorization")
-// 					params := strings.Split(authHeader, " ")
-// 					if len(params) != 2 || params[0] != "Bearer" {
-// 						return errors.New("no authorized")
-// 					}
-// 					userId, err := h.tokenManager.ParseAccessToken(params[1])
-// 					if err != nil {
-// 						if errors.Is(tokens.ErrTokenExpired, err) {
-// 							return errors.New("token is expired")
-// 						}
-// 						if errors.Is(tokens.ErrTokenInvalid, err) {
-// 							return errors.New("token is invalid")
-// 						}
-// 						return errors.New("internal server error")
-// 					}
-// 					eCxt := middleware.GetEchoContext(ctx)
-// 					eCxt.Set("userId", userId)
-// 				}
-// 				return nil
-// 			},
-// 		},
-// 	})
-// 	return m
-// }
+func machineId(c echo.Context) uuid.UUID {
+	id, _ := c.Get(machineIdKey).(uuid.UUID)
+	return id
+}
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/sirupsen/logrus"
+)
+
+func passkeyReturn(passkey domain.Passkey) Passkey {
+	return Passkey{
+		Id:         passkey.Id,
+		Name:       passkey.Name,
+		CreatedAt:  passkey.CreatedAt,
+		LastUsedAt: passkey.LastUsedAt,
+	}
+}
+
+func httpErrTooManyPasskeys() error {
+	return echo.NewHTTPError(409, Message{
+		Message: "Too many passkeys",
+	})
+}
+
+func httpErrCeremonyInvalid() error {
+	return echo.NewHTTPError(401, Message{
+		Message: "Ceremony is invalid or expired",
+	})
+}
+
+// httpErrReauth maps errors of password and second factor confirmation.
+func httpErrReauth(ctx echo.Context, err error) error {
+	var lockedErr *service.LockedError
+	if errors.As(err, &lockedErr) {
+		return httpErrLocked(ctx, lockedErr.RetryAfter)
+	}
+	if errors.Is(service.ErrWrongPassword, err) {
+		return echo.NewHTTPError(403, Message{
+			Message: "Wrong password",
+		})
+	}
+	if errors.Is(service.ErrInvalidCode, err) {
+		return httpErrInvalidCode()
+	}
+	return nil
+}
+
+func (h *Handler) BeginPasskeyRegistration(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+	var data BeginPasskeyRegistrationJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+	var code string
+	if data.Code != nil {
+		code = *data.Code
+	}
+
+	ceremony, err := h.services.Auth.BeginPasskeyRegistration(ctx.Request().Context(), userId,
+		data.Password, code)
+	if err != nil {
+		logrus.Errorf("error begin passkey registration (handler): %s", err)
+		if errors.Is(service.ErrUserNotFound, err) {
+			return httpErrUserNotFound()
+		}
+		if httpErr := httpErrReauth(ctx, err); httpErr != nil {
+			return httpErr
+		}
+		if errors.Is(service.ErrTooManyPasskeys, err) {
+			return httpErrTooManyPasskeys()
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, PasskeyCeremony{
+		CeremonyToken: ceremony.Token,
+		Options:       ceremony.Options,
+	})
+}
+
+func (h *Handler) FinishPasskeyRegistration(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+	var data PasskeyRegistration
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	passkey, err := h.services.Auth.FinishPasskeyRegistration(ctx.Request().Context(), userId,
+		data.CeremonyToken, data.Name, data.Credential)
+	if err != nil {
+		logrus.Errorf("error finish passkey registration (handler): %s", err)
+		if errors.Is(service.ErrTokenInvalid, err) {
+			return httpErrCeremonyInvalid()
+		}
+		if errors.Is(service.ErrUserNotFound, err) {
+			return httpErrUserNotFound()
+		}
+		if errors.Is(service.ErrInvalidPasskeyName, err) {
+			return echo.NewHTTPError(400, Message{
+				Message: "Invalid passkey name",
+			})
+		}
+		if errors.Is(service.ErrPasskeyInvalid, err) {
+			return echo.NewHTTPError(400, Message{
+				Message: "Passkey credential is invalid",
+			})
+		}
+		if errors.Is(service.ErrTooManyPasskeys, err) {
+			return httpErrTooManyPasskeys()
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(201, passkeyReturn(passkey))
+}
+
+func (h *Handler) GetPasskeys(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	passkeys, err := h.services.Auth.GetPasskeys(ctx.Request().Context(), userId)
+	if err != nil {
+		logrus.Errorf("error get passkeys (handler): %s", err)
+		return httpInternalError()
+	}
+
+	passkeysReturn := make([]Passkey, len(passkeys))
+	for i, passkey := range passkeys {
+		passkeysReturn[i] = passkeyReturn(passkey)
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"passkeys": passkeysReturn,
+	})
+}
+
+func (h *Handler) DeletePasskey(ctx echo.Context, passkeyId openapi_types.UUID) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data DeletePasskeyJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+	var code string
+	if data.Code != nil {
+		code = *data.Code
+	}
+
+	err = h.services.Auth.DeletePasskey(ctx.Request().Context(), userId, passkeyId, data.Password, code)
+	if err != nil {
+		logrus.Errorf("error delete passkey (handler): %s", err)
+		if errors.Is(service.ErrUserNotFound, err) {
+			return httpErrUserNotFound()
+		}
+		if httpErr := httpErrReauth(ctx, err); httpErr != nil {
+			return httpErr
+		}
+		if errors.Is(service.ErrPasskeyNotFound, err) {
+			return echo.NewHTTPError(404, Message{
+				Message: "Passkey not found",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
+
+func (h *Handler) BeginPasskeySignIn(ctx echo.Context) error {
+	ceremony, err := h.services.Auth.BeginPasskeySignIn(ctx.Request().Context())
+	if err != nil {
+		logrus.Errorf("error begin passkey sign in (handler): %s", err)
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, PasskeyCeremony{
+		CeremonyToken: ceremony.Token,
+		Options:       ceremony.Options,
+	})
+}
+
+func (h *Handler) FinishPasskeySignIn(ctx echo.Context) error {
+	var data PasskeyAssertion
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	token, err := h.services.Auth.FinishPasskeySignIn(ctx.Request().Context(), data.CeremonyToken,
+		data.Credential, device(ctx))
+	if err != nil {
+		logrus.Errorf("error finish passkey sign in (handler): %s", err)
+		if errors.Is(service.ErrTokenInvalid, err) {
+			return httpErrCeremonyInvalid()
+		}
+		if errors.Is(service.ErrPasskeyInvalid, err) {
+			return echo.NewHTTPError(401, Message{
+				Message: "Passkey is invalid",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, ReturnToken{
+		Token: token,
+	})
+}
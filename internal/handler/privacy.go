@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/sirupsen/logrus"
+)
+
+func (h *Handler) RequestDataExport(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	exportId, err := h.services.Privacy.RequestExport(ctx.Request().Context(), userId)
+	if err != nil {
+		logrus.Errorf("error request data export (handler): %s", err)
+		if errors.Is(service.ErrTooManyRequests, err) {
+			return httpTooManyRequests()
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(202, DataExportStatus{
+		Id:     exportId,
+		Status: Pending,
+	})
+}
+
+func (h *Handler) GetDataExport(ctx echo.Context, exportId openapi_types.UUID) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	export, err := h.services.Privacy.GetExport(ctx.Request().Context(), userId, exportId)
+	if err != nil {
+		logrus.Errorf("error get data export (handler): %s", err)
+		if errors.Is(service.ErrExportNotFound, err) {
+			return echo.NewHTTPError(404, Message{
+				Message: "Export not found",
+			})
+		}
+		return httpInternalError()
+	}
+
+	if export.Status != domain.DataExportReady {
+		return ctx.JSON(202, DataExportStatus{
+			Id:     export.Id,
+			Status: DataExportStatusStatus(export.Status),
+		})
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition,
+		"attachment; filename=\"export-"+export.Id.String()+".json\"")
+	return ctx.JSONBlob(200, export.Archive)
+}
+
+func (h *Handler) EraseMe(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data EraseMeJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	err = h.services.Privacy.EraseAccount(ctx.Request().Context(), userId, data.Password)
+	if err != nil {
+		logrus.Errorf("error erase me (handler): %s", err)
+		if errors.Is(service.ErrUserNotFound, err) {
+			return httpErrUserNotFound()
+		}
+		if errors.Is(service.ErrWrongPassword, err) {
+			return echo.NewHTTPError(403, Message{
+				Message: "Wrong password",
+			})
+		}
+		if errors.Is(service.ErrAccountHasMoney, err) {
+			return echo.NewHTTPError(409, Message{
+				Message: "Withdraw money from all accounts first",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
@@ -1,6 +1,14 @@
 package handler
 
-import "github.com/labstack/echo/v4"
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/labstack/echo/v4"
+)
 
 func httpBadRequest() error {
 	return echo.NewHTTPError(400, Message{
@@ -14,6 +22,12 @@ func httpInternalError() error {
 	})
 }
 
+func httpForbidden() error {
+	return echo.NewHTTPError(403, Message{
+		Message: "Forbidden",
+	})
+}
+
 func httpErrUserNotFound() error {
 	return echo.NewHTTPError(404, Message{
 		Message: "User not found",
@@ -25,3 +39,48 @@ func httpErrAccountNotFound() error {
 		Message: "Account not found",
 	})
 }
+
+func httpErrRecipientUnavailable() error {
+	return echo.NewHTTPError(404, Message{
+		Message: "Account can't receive deposit",
+	})
+}
+
+func httpErrAccountFrozen() error {
+	return echo.NewHTTPError(409, Message{
+		Message: "Account is frozen",
+	})
+}
+
+func httpTooManyRequests() error {
+	return echo.NewHTTPError(429, Message{
+		Message: "Too many requests",
+	})
+}
+
+// httpErrInvalidUserData maps user data validation error of service to 400 response.
+// It returns nil if err isn't a validation error.
+func httpErrInvalidUserData(err error) error {
+	var message string
+	switch {
+	case errors.Is(service.ErrInvalidName, err):
+		message = "Invalid surname, name or patronyc"
+	case errors.Is(service.ErrInvalidEmail, err):
+		message = "Invalid email"
+	case errors.Is(service.ErrInvalidPassword, err):
+		message = "Password must be from 8 to 128 characters"
+	default:
+		return nil
+	}
+	return echo.NewHTTPError(400, Message{
+		Message: message,
+	})
+}
+
+func httpErrLocked(ctx echo.Context, retryAfter time.Duration) error {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	ctx.Response().Header().Set("Retry-After", strconv.Itoa(seconds))
+	return echo.NewHTTPError(429, Message{
+		Message: "Too many failed attempts, try again in " + strconv.Itoa(seconds) + " seconds",
+	})
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var echoPathParam = regexp.MustCompile(`:(\w+)`)
+
+// errNoCredentials is returned by authenticator when the request has no credentials
+// of its security scheme, so error of another alternative is more relevant.
+var errNoCredentials = echo.NewHTTPError(401, Message{
+	Message: "No authorized",
+})
+
+// securityAuthenticator authenticates request by security scheme, checks scopes
+// that the operation requires and stores identity into echo context.
+type securityAuthenticator func(c echo.Context, scopes []string) error
+
+type echoContextKey struct{}
+
+var errInvalidFormat = errors.New("invalid format")
+
+// specRouter registers routes into echo router adding middleware which validates
+// request against operation of openapi spec. Security requirements of the operation
+// are checked first by authenticators: requirements are alternatives, schemes of one
+// requirement must all pass. Then parameters and body are validated.
+type specRouter struct {
+	router         EchoRouter
+	spec           *openapi3.T
+	authenticators map[string]securityAuthenticator
+}
+
+func newSpecRouter(router EchoRouter, spec *openapi3.T,
+	authenticators map[string]securityAuthenticator) *specRouter {
+	defineStringFormats()
+	return &specRouter{
+		router:         router,
+		spec:           spec,
+		authenticators: authenticators,
+	}
+}
+
+// defineStringFormats makes validator check formats used by the spec the same way
+// as services do.
+func defineStringFormats() {
+	openapi3.DefineStringFormatCallback("email", func(value string) error {
+		if !domain.ValidateEmail(value) {
+			return errInvalidFormat
+		}
+		return nil
+	})
+	openapi3.DefineStringFormatCallback("uuid", func(value string) error {
+		if _, err := uuid.Parse(value); err != nil {
+			return errInvalidFormat
+		}
+		return nil
+	})
+}
+
+func (r *specRouter) validationMiddlewares(method string, path string) []echo.MiddlewareFunc {
+	specPath := echoPathParam.ReplaceAllString(path, "{$1}")
+	pathItem := r.spec.Paths.Find(specPath)
+	if pathItem == nil {
+		return nil
+	}
+	operation := pathItem.GetOperation(method)
+	if operation == nil {
+		return nil
+	}
+	route := &routers.Route{
+		Spec:      r.spec,
+		Path:      specPath,
+		PathItem:  pathItem,
+		Method:    method,
+		Operation: operation,
+	}
+	security := operation.Security
+	if security == nil {
+		security = &r.spec.Security
+	}
+
+	return []echo.MiddlewareFunc{
+		func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				pathParams := make(map[string]string, len(c.ParamNames()))
+				for _, name := range c.ParamNames() {
+					pathParams[name] = c.Param(name)
+				}
+				input := &openapi3filter.RequestValidationInput{
+					Request:    c.Request(),
+					PathParams: pathParams,
+					Route:      route,
+					Options: &openapi3filter.Options{
+						MultiError:         true,
+						AuthenticationFunc: r.authenticate,
+					},
+				}
+				ctx := context.WithValue(c.Request().Context(), echoContextKey{}, c)
+
+				err := openapi3filter.ValidateSecurityRequirements(ctx, input, *security)
+				if err != nil {
+					return securityError(err)
+				}
+
+				// security is already checked
+				input.Options.AuthenticationFunc = openapi3filter.NoopAuthenticationFunc
+				if err := openapi3filter.ValidateRequest(ctx, input); err != nil {
+					return httpErrValidation(err)
+				}
+
+				return next(c)
+			}
+		},
+	}
+}
+
+func (r *specRouter) authenticate(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+	c, ok := ctx.Value(echoContextKey{}).(echo.Context)
+	if !ok {
+		return httpInternalError()
+	}
+	authenticator, ok := r.authenticators[input.SecuritySchemeName]
+	if !ok {
+		return httpInternalError()
+	}
+	return authenticator(c, input.Scopes)
+}
+
+// securityError returns error of the first failed requirement, skipping ones
+// the request has no credentials for.
+func securityError(err error) error {
+	requirementsErr, ok := err.(*openapi3filter.SecurityRequirementsError)
+	if !ok {
+		return httpInternalError()
+	}
+	var firstErr error
+	for _, err := range requirementsErr.Errors {
+		if firstErr == nil || firstErr == errNoCredentials {
+			firstErr = err
+		}
+	}
+	if _, ok := firstErr.(*echo.HTTPError); !ok {
+		return httpInternalError()
+	}
+	return firstErr
+}
+
+// httpErrValidation maps validation error to 400 response listing offending fields.
+func httpErrValidation(err error) error {
+	fields := fieldErrors("body", err)
+	return echo.NewHTTPError(400, ValidationError{
+		Message: "Invalid request",
+		Errors:  &fields,
+	})
+}
+
+// fieldErrors flattens validation error into errors of single fields. Field of
+// the body error is dot-separated path to the value.
+func fieldErrors(field string, err error) []FieldError {
+	switch err := err.(type) {
+	case openapi3.MultiError:
+		fields := make([]FieldError, 0, len(err))
+		for _, err := range err {
+			fields = append(fields, fieldErrors(field, err)...)
+		}
+		return fields
+	case *openapi3filter.RequestError:
+		if err.Parameter != nil {
+			field = err.Parameter.Name
+		}
+		switch err.Err.(type) {
+		case openapi3.MultiError, *openapi3.SchemaError:
+			return fieldErrors(field, err.Err)
+		}
+		message := err.Reason
+		if err.Err != nil {
+			message = err.Err.Error()
+		}
+		return []FieldError{{Field: field, Message: message}}
+	case *openapi3.SchemaError:
+		if pointer := err.JSONPointer(); len(pointer) > 0 {
+			field = strings.Join(pointer, ".")
+		}
+		return []FieldError{{Field: field, Message: err.Reason}}
+	}
+	return []FieldError{{Field: field, Message: err.Error()}}
+}
+
+func (r *specRouter) with(method string, path string, m []echo.MiddlewareFunc) []echo.MiddlewareFunc {
+	return append(r.validationMiddlewares(method, path), m...)
+}
+
+func (r *specRouter) CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.router.CONNECT(path, h, r.with(echo.CONNECT, path, m)...)
+}
+
+func (r *specRouter) DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.router.DELETE(path, h, r.with(echo.DELETE, path, m)...)
+}
+
+func (r *specRouter) GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.router.GET(path, h, r.with(echo.GET, path, m)...)
+}
+
+func (r *specRouter) HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.router.HEAD(path, h, r.with(echo.HEAD, path, m)...)
+}
+
+func (r *specRouter) OPTIONS(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.router.OPTIONS(path, h, r.with(echo.OPTIONS, path, m)...)
+}
+
+func (r *specRouter) PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.router.PATCH(path, h, r.with(echo.PATCH, path, m)...)
+}
+
+func (r *specRouter) POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.router.POST(path, h, r.with(echo.POST, path, m)...)
+}
+
+func (r *specRouter) PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.router.PUT(path, h, r.with(echo.PUT, path, m)...)
+}
+
+func (r *specRouter) TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.router.TRACE(path, h, r.with(echo.TRACE, path, m)...)
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/sirupsen/logrus"
+)
+
+func (h *Handler) GetSessions(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := h.services.Auth.GetSessions(ctx.Request().Context(), userId)
+	if err != nil {
+		logrus.Errorf("error get sessions (handler): %s", err)
+		return httpInternalError()
+	}
+
+	currentSessionId := currentPrincipal(ctx).SessionId
+	sessionsReturn := make([]Session, len(sessions))
+	for i, session := range sessions {
+		sessionsReturn[i] = Session{
+			Id:         session.Id,
+			UserAgent:  session.UserAgent,
+			Ip:         session.Ip,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    session.Id == currentSessionId,
+		}
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"sessions": sessionsReturn,
+	})
+}
+
+func (h *Handler) RevokeSession(ctx echo.Context, sessionId openapi_types.UUID) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.services.Auth.RevokeSession(ctx.Request().Context(), userId, sessionId)
+	if err != nil {
+		logrus.Errorf("error revoke session (handler): %s", err)
+		if errors.Is(service.ErrSessionNotFound, err) {
+			return echo.NewHTTPError(404, Message{
+				Message: "Session not found",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	machineJournalDefaultLimit = 100
+	settlementsDefaultLimit    = 20
+)
+
+func toJournalEntry(entry domain.JournalEntry) JournalEntry {
+	journalEntry := JournalEntry{
+		Id:         entry.Id,
+		AccountId:  entry.AccountId,
+		Operation:  JournalEntryOperation(entry.Operation),
+		Amount:     int32(entry.Amount),
+		Notes:      toNotes(entry.Notes),
+		Outcome:    JournalEntryOutcome(entry.Outcome),
+		Reason:     entry.Reason,
+		Reference:  entry.Reference,
+		ThirdParty: entry.ThirdParty,
+		Flagged:    entry.Flagged,
+		CreatedAt:  entry.CreatedAt,
+	}
+	if entry.SessionId.Valid {
+		journalEntry.SessionId = &entry.SessionId.UUID
+	}
+	if entry.CashoutCodeId.Valid {
+		journalEntry.CashoutCodeId = &entry.CashoutCodeId.UUID
+	}
+	return journalEntry
+}
+
+func toSettlement(settlement domain.MachineSettlement) Settlement {
+	differences := settlement.Differences()
+	differencesReturn := make([]CashDifference, len(differences))
+	for i, difference := range differences {
+		differencesReturn[i] = CashDifference{
+			Denomination: difference.Denomination,
+			Expected:     difference.Expected,
+			Counted:      difference.Counted,
+			Difference:   difference.Difference(),
+		}
+	}
+	return Settlement{
+		Id:             settlement.Id,
+		MachineId:      settlement.MachineId,
+		PeriodStart:    settlement.PeriodStart,
+		PeriodEnd:      settlement.PeriodEnd,
+		Dispensed:      settlement.Dispensed,
+		DispensedCount: settlement.DispensedCount,
+		Deposited:      settlement.Deposited,
+		DepositedCount: settlement.DepositedCount,
+		DeclinedCount:  settlement.DeclinedCount,
+		ExpectedAmount: settlement.ExpectedAmount(),
+		CountedAmount:  settlement.CountedAmount(),
+		Differences:    differencesReturn,
+		Discrepancy:    settlement.Discrepancy,
+		SettledBy:      settlement.SettledBy,
+		CreatedAt:      settlement.CreatedAt,
+	}
+}
+
+func toSettlements(settlements []domain.MachineSettlement) []Settlement {
+	settlementsReturn := make([]Settlement, len(settlements))
+	for i, settlement := range settlements {
+		settlementsReturn[i] = toSettlement(settlement)
+	}
+	return settlementsReturn
+}
+
+func (h *Handler) AdminGetMachineJournal(ctx echo.Context, machineId openapi_types.UUID,
+	params AdminGetMachineJournalParams) error {
+	if _, err := h.authorization(ctx); err != nil {
+		return err
+	}
+
+	var from, to time.Time
+	if params.From != nil {
+		from = *params.From
+	}
+	if params.To != nil {
+		to = *params.To
+	}
+	limit := machineJournalDefaultLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	var offset int
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+
+	entries, err := h.services.Admin.GetMachineJournal(ctx.Request().Context(), machineId, from, to,
+		limit, offset)
+	if err != nil {
+		logrus.Errorf("error admin get machine journal (handler): %s", err)
+		return httpErrMachine(err)
+	}
+
+	entriesReturn := make([]JournalEntry, len(entries))
+	for i, entry := range entries {
+		entriesReturn[i] = toJournalEntry(entry)
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"entries": entriesReturn,
+	})
+}
+
+func (h *Handler) AdminGetMachineSettlements(ctx echo.Context, machineId openapi_types.UUID,
+	params AdminGetMachineSettlementsParams) error {
+	if _, err := h.authorization(ctx); err != nil {
+		return err
+	}
+
+	limit := settlementsDefaultLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	var offset int
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+
+	settlements, err := h.services.Admin.GetMachineSettlements(ctx.Request().Context(), machineId, limit, offset)
+	if err != nil {
+		logrus.Errorf("error admin get machine settlements (handler): %s", err)
+		return httpErrMachine(err)
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"settlements": toSettlements(settlements),
+	})
+}
+
+func (h *Handler) AdminSettleMachine(ctx echo.Context, machineId openapi_types.UUID) error {
+	actorId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data AdminSettleMachineJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+	counted := make([]domain.Note, len(data.Counted))
+	for i, note := range data.Counted {
+		counted[i] = domain.Note{
+			Denomination: note.Denomination,
+			Count:        note.Count,
+		}
+	}
+
+	settlement, err := h.services.Admin.SettleMachine(ctx.Request().Context(), actorId, machineId, counted)
+	if err != nil {
+		logrus.Errorf("error admin settle machine (handler): %s", err)
+		return httpErrMachine(err)
+	}
+
+	return ctx.JSON(201, toSettlement(settlement))
+}
+
+func (h *Handler) AdminGetSettlements(ctx echo.Context, params AdminGetSettlementsParams) error {
+	if _, err := h.authorization(ctx); err != nil {
+		return err
+	}
+
+	var discrepancyOnly bool
+	if params.Discrepancy != nil {
+		discrepancyOnly = *params.Discrepancy
+	}
+	limit := settlementsDefaultLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	var offset int
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+
+	settlements, err := h.services.Admin.GetSettlements(ctx.Request().Context(), discrepancyOnly, limit, offset)
+	if err != nil {
+		logrus.Errorf("error admin get settlements (handler): %s", err)
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, map[string]interface{}{
+		"settlements": toSettlements(settlements),
+	})
+}
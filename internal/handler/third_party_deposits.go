@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/sirupsen/logrus"
+)
+
+func (h *Handler) GetDepositRecipient(ctx echo.Context, accountId openapi_types.UUID) error {
+	recipient, err := h.services.GetDepositRecipient(ctx.Request().Context(), machineId(ctx), accountId)
+	if err != nil {
+		logrus.Errorf("error get deposit recipient (handler): %s", err)
+		if errors.Is(service.ErrTooManyRequests, err) {
+			return httpTooManyRequests()
+		}
+		if errors.Is(service.ErrRecipientUnavailable, err) {
+			return httpErrRecipientUnavailable()
+		}
+		return httpErrMachineOperation(err)
+	}
+
+	return ctx.JSON(200, DepositRecipient{
+		AccountId: recipient.AccountId,
+		Name:      recipient.Name,
+	})
+}
+
+func (h *Handler) MachineThirdPartyDeposit(ctx echo.Context) error {
+	var data MachineThirdPartyDepositJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	err := h.services.MachineThirdPartyDeposit(ctx.Request().Context(), machineId(ctx), data.AccountId,
+		int(data.Amount), fromNotes(data.Notes))
+	if err != nil {
+		logrus.Errorf("error machine third party deposit (handler): %s", err)
+		if errors.Is(service.ErrDepositLimitExceeded, err) {
+			return echo.NewHTTPError(409, Message{
+				Message: "Amount exceeds limit of deposit to account of someone else",
+			})
+		}
+		if errors.Is(service.ErrInvalidAmount, err) {
+			return httpBadRequest()
+		}
+		if errors.Is(service.ErrTooManyRequests, err) {
+			return httpTooManyRequests()
+		}
+		if errors.Is(service.ErrRecipientUnavailable, err) {
+			return httpErrRecipientUnavailable()
+		}
+		return httpErrMachineOperation(err)
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+func httpErrInvalidCode() error {
+	return echo.NewHTTPError(403, Message{
+		Message: "Invalid code",
+	})
+}
+
+func (h *Handler) SignInTwoFactor(ctx echo.Context) error {
+	var data SignInTwoFactorJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	token, err := h.services.Auth.SignInTwoFactor(ctx.Request().Context(), data.TwoFactorToken, data.Code,
+		device(ctx))
+	if err != nil {
+		logrus.Errorf("error sign in two factor (handler): %s", err)
+		var lockedErr *service.LockedError
+		if errors.As(err, &lockedErr) {
+			return httpErrLocked(ctx, lockedErr.RetryAfter)
+		}
+		if errors.Is(service.ErrTokenInvalid, err) {
+			return echo.NewHTTPError(401, Message{
+				Message: "Sign in token is invalid or expired",
+			})
+		}
+		if errors.Is(service.ErrInvalidCode, err) {
+			return echo.NewHTTPError(401, Message{
+				Message: "Invalid code",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, ReturnToken{
+		Token: token,
+	})
+}
+
+func (h *Handler) EnrollTwoFactor(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	enrollment, err := h.services.Auth.EnrollTwoFactor(ctx.Request().Context(), userId)
+	if err != nil {
+		logrus.Errorf("error enroll two factor (handler): %s", err)
+		if errors.Is(service.ErrUserNotFound, err) {
+			return httpErrUserNotFound()
+		}
+		if errors.Is(service.ErrTwoFactorAlreadyEnabled, err) {
+			return echo.NewHTTPError(409, Message{
+				Message: "Two factor authentication already enabled",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, TwoFactorEnrollment{
+		Secret: enrollment.Secret,
+		Uri:    enrollment.URI,
+	})
+}
+
+func (h *Handler) ConfirmTwoFactor(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data ConfirmTwoFactorJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	codes, err := h.services.Auth.ConfirmTwoFactor(ctx.Request().Context(), userId, data.Code)
+	if err != nil {
+		logrus.Errorf("error confirm two factor (handler): %s", err)
+		if errors.Is(service.ErrInvalidCode, err) {
+			return httpErrInvalidCode()
+		}
+		if errors.Is(service.ErrTwoFactorNotEnabled, err) {
+			return echo.NewHTTPError(409, Message{
+				Message: "Two factor authentication enrollment not started",
+			})
+		}
+		if errors.Is(service.ErrTwoFactorAlreadyEnabled, err) {
+			return echo.NewHTTPError(409, Message{
+				Message: "Two factor authentication already enabled",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, RecoveryCodes{
+		RecoveryCodes: codes,
+	})
+}
+
+func (h *Handler) DisableTwoFactor(ctx echo.Context) error {
+	userId, err := h.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data DisableTwoFactorJSONRequestBody
+	if err := ctx.Bind(&data); err != nil {
+		return httpBadRequest()
+	}
+
+	err = h.services.Auth.DisableTwoFactor(ctx.Request().Context(), userId, data.Code)
+	if err != nil {
+		logrus.Errorf("error disable two factor (handler): %s", err)
+		var lockedErr *service.LockedError
+		if errors.As(err, &lockedErr) {
+			return httpErrLocked(ctx, lockedErr.RetryAfter)
+		}
+		if errors.Is(service.ErrInvalidCode, err) {
+			return httpErrInvalidCode()
+		}
+		if errors.Is(service.ErrTwoFactorNotEnabled, err) {
+			return echo.NewHTTPError(409, Message{
+				Message: "Two factor authentication not enabled",
+			})
+		}
+		return httpInternalError()
+	}
+
+	return ctx.JSON(200, Message{
+		Message: "ok",
+	})
+}
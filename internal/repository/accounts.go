@@ -72,6 +72,21 @@ func (r *AccountRepository) GetAll(ctx context.Context, userId uuid.UUID) ([]dom
 	return accounts, nil
 }
 
+// GetAllForUpdate locks accounts of the user until the end of transaction, so
+// their balances can't change before it's done.
+func (r *AccountRepository) GetAllForUpdate(ctx context.Context, userId uuid.UUID) ([]domain.Account, error) {
+	accounts := []domain.Account{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE user_id=$1 ORDER BY id FOR UPDATE`, accountsTable)
+	if err := sqlx.SelectContext(ctx, tx, &accounts, query, userId); err != nil {
+		logrus.Errorf("error select accounts for update from db by user_id: %s", err)
+		return accounts, ErrInternal
+	}
+
+	return accounts, nil
+}
+
 func (r *AccountRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	tx := r.ctxGetter.TrOrDb(ctx, r.db)
 
@@ -103,6 +118,9 @@ func (r *AccountRepository) Update(ctx context.Context, id uuid.UUID,
 	if data.Money != nil {
 		addProperty("money", *data.Money)
 	}
+	if data.Frozen != nil {
+		addProperty("frozen", *data.Frozen)
+	}
 
 	values = append(values, id)
 	setQuery := strings.Join(names, ", ")
@@ -116,3 +134,42 @@ func (r *AccountRepository) Update(ctx context.Context, id uuid.UUID,
 
 	return account, nil
 }
+
+// Debit takes amount from the account in one statement, so concurrent debits
+// can't spend the same money. ErrInsufficientFunds is returned when the
+// account holds less than amount.
+func (r *AccountRepository) Debit(ctx context.Context, id uuid.UUID, amount int) (domain.Account, error) {
+	var account domain.Account
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s a SET money=money-$1 WHERE id=$2 AND money>=$1 RETURNING a.*`,
+		accountsTable)
+	row := tx.QueryRowxContext(ctx, query, amount, id)
+	if err := row.StructScan(&account); err != nil {
+		logrus.Errorf("error debit account into db by id: %s", err)
+		if errors.Is(sql.ErrNoRows, err) {
+			return account, ErrInsufficientFunds
+		}
+		return account, ErrInternal
+	}
+
+	return account, nil
+}
+
+// Credit adds amount to the account in one statement.
+func (r *AccountRepository) Credit(ctx context.Context, id uuid.UUID, amount int) (domain.Account, error) {
+	var account domain.Account
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s a SET money=money+$1 WHERE id=$2 RETURNING a.*`, accountsTable)
+	row := tx.QueryRowxContext(ctx, query, amount, id)
+	if err := row.StructScan(&account); err != nil {
+		logrus.Errorf("error credit account into db by id: %s", err)
+		if errors.Is(sql.ErrNoRows, err) {
+			return account, ErrAccountNotFound
+		}
+		return account, ErrInternal
+	}
+
+	return account, nil
+}
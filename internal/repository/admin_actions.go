@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type AdminActionsRepository struct {
+	db        *sqlx.DB
+	ctxGetter transactions.CtxGetterInterface
+}
+
+func NewAdminActionsRepository(db *sqlx.DB, ctxGetter transactions.CtxGetterInterface) *AdminActionsRepository {
+	return &AdminActionsRepository{
+		db:        db,
+		ctxGetter: ctxGetter,
+	}
+}
+
+func (r *AdminActionsRepository) Create(ctx context.Context, action domain.AdminAction) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, actor_id, action, target_type, target_id, details)
+		VALUES ((SELECT gen_random_uuid()), $1, $2, $3, $4, $5)`, adminActionsTable)
+	_, err := tx.ExecContext(ctx, query, action.ActorId, action.Action, action.TargetType,
+		action.TargetId, action.Details)
+	if err != nil {
+		logrus.Errorf("error insert admin action into db: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type ApiKeysRepository struct {
+	db        *sqlx.DB
+	ctxGetter transactions.CtxGetterInterface
+}
+
+func NewApiKeysRepository(db *sqlx.DB, ctxGetter transactions.CtxGetterInterface) *ApiKeysRepository {
+	return &ApiKeysRepository{
+		db:        db,
+		ctxGetter: ctxGetter,
+	}
+}
+
+func (r *ApiKeysRepository) Create(ctx context.Context, key domain.ApiKey) (domain.ApiKey, error) {
+	var created domain.ApiKey
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, user_id, name, key_hash, scopes, account_id, expires_at)
+		VALUES ((SELECT gen_random_uuid()), $1, $2, $3, $4, $5, $6) RETURNING *`, apiKeysTable)
+	row := tx.QueryRowxContext(ctx, query, key.UserId, key.Name, key.Hash, key.Scopes, key.AccountId,
+		key.ExpiresAt)
+	if err := row.StructScan(&created); err != nil {
+		logrus.Errorf("error insert api key into db: %s", err)
+		return created, ErrInternal
+	}
+
+	return created, nil
+}
+
+func (r *ApiKeysRepository) GetByHash(ctx context.Context, hash string) (domain.ApiKey, error) {
+	var key domain.ApiKey
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE key_hash=$1`, apiKeysTable)
+	if err := sqlx.GetContext(ctx, tx, &key, query, hash); err != nil {
+		if errors.Is(sql.ErrNoRows, err) {
+			return key, ErrApiKeyNotFound
+		}
+		logrus.Errorf("error select api key from db by hash: %s", err)
+		return key, ErrInternal
+	}
+
+	return key, nil
+}
+
+func (r *ApiKeysRepository) GetAll(ctx context.Context, userId uuid.UUID) ([]domain.ApiKey, error) {
+	keys := []domain.ApiKey{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE user_id=$1 ORDER BY created_at`, apiKeysTable)
+	if err := sqlx.SelectContext(ctx, tx, &keys, query, userId); err != nil {
+		logrus.Errorf("error select api keys from db by user_id: %s", err)
+		return keys, ErrInternal
+	}
+
+	return keys, nil
+}
+
+func (r *ApiKeysRepository) Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id=$1 AND user_id=$2`, apiKeysTable)
+	result, err := tx.ExecContext(ctx, query, id, userId)
+	if err != nil {
+		logrus.Errorf("error delete api key from db: %s", err)
+		return ErrInternal
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logrus.Errorf("error getting affected rows when deleting api key: %s", err)
+		return ErrInternal
+	}
+	if affected == 0 {
+		return ErrApiKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *ApiKeysRepository) DeleteAll(ctx context.Context, userId uuid.UUID) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE user_id=$1`, apiKeysTable)
+	if _, err := tx.ExecContext(ctx, query, userId); err != nil {
+		logrus.Errorf("error delete api keys from db by user_id: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// Touch updates last used time of the key. It's written at most once a minute
+// to not update the row on every request.
+func (r *ApiKeysRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET last_used_at=now() WHERE id=$1
+		AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')`, apiKeysTable)
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		logrus.Errorf("error update api key last used time into db: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
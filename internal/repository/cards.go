@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+const pqUniqueViolation = "23505"
+
+type CardsRepository struct {
+	db        *sqlx.DB
+	ctxGetter transactions.CtxGetterInterface
+}
+
+func NewCardsRepository(db *sqlx.DB, ctxGetter transactions.CtxGetterInterface) *CardsRepository {
+	return &CardsRepository{
+		db:        db,
+		ctxGetter: ctxGetter,
+	}
+}
+
+// Create inserts the card. ErrCardPanExists is returned when a card with the
+// same PAN exists.
+func (r *CardsRepository) Create(ctx context.Context, card domain.Card) (domain.Card, error) {
+	var created domain.Card
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, user_id, account_id, pan_hash, last4, expires_on, pin_hash)
+		VALUES ((SELECT gen_random_uuid()), $1, $2, $3, $4, $5, $6) RETURNING *`, cardsTable)
+	row := tx.QueryRowxContext(ctx, query, card.UserId, card.AccountId, card.PanHash, card.Last4,
+		card.ExpiresOn, card.PinHash)
+	if err := row.StructScan(&created); err != nil {
+		logrus.Errorf("error insert card into db: %s", err)
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation {
+			return created, ErrCardPanExists
+		}
+		return created, ErrInternal
+	}
+
+	return created, nil
+}
+
+func (r *CardsRepository) Get(ctx context.Context, id uuid.UUID) (domain.Card, error) {
+	var card domain.Card
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE id=$1`, cardsTable)
+	if err := sqlx.GetContext(ctx, tx, &card, query, id); err != nil {
+		logrus.Errorf("error select card from db by id: %s", err)
+		if errors.Is(sql.ErrNoRows, err) {
+			return card, ErrCardNotFound
+		}
+		return card, ErrInternal
+	}
+
+	return card, nil
+}
+
+func (r *CardsRepository) GetByPanHash(ctx context.Context, panHash string) (domain.Card, error) {
+	var card domain.Card
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE pan_hash=$1`, cardsTable)
+	if err := sqlx.GetContext(ctx, tx, &card, query, panHash); err != nil {
+		logrus.Errorf("error select card from db by pan hash: %s", err)
+		if errors.Is(sql.ErrNoRows, err) {
+			return card, ErrCardNotFound
+		}
+		return card, ErrInternal
+	}
+
+	return card, nil
+}
+
+func (r *CardsRepository) GetAll(ctx context.Context, userId uuid.UUID) ([]domain.Card, error) {
+	cards := []domain.Card{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE user_id=$1 ORDER BY created_at`, cardsTable)
+	if err := sqlx.SelectContext(ctx, tx, &cards, query, userId); err != nil {
+		logrus.Errorf("error select cards from db by user_id: %s", err)
+		return cards, ErrInternal
+	}
+
+	return cards, nil
+}
+
+func (r *CardsRepository) SetStatus(ctx context.Context, id uuid.UUID, status string) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET status=$2 WHERE id=$1`, cardsTable)
+	result, err := tx.ExecContext(ctx, query, id, status)
+	if err != nil {
+		logrus.Errorf("error update card status into db: %s", err)
+		return ErrInternal
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logrus.Errorf("error getting affected rows when updating card status: %s", err)
+		return ErrInternal
+	}
+	if affected == 0 {
+		return ErrCardNotFound
+	}
+
+	return nil
+}
+
+// BlockAll blocks every card of the user.
+func (r *CardsRepository) BlockAll(ctx context.Context, userId uuid.UUID) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET status=$2 WHERE user_id=$1`, cardsTable)
+	if _, err := tx.ExecContext(ctx, query, userId, domain.CardStatusBlocked); err != nil {
+		logrus.Errorf("error block cards into db by user_id: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// SetPin replaces PIN of the card and forgives wrong attempts made before.
+func (r *CardsRepository) SetPin(ctx context.Context, id uuid.UUID, pinHash string) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET pin_hash=$2, pin_attempts=0 WHERE id=$1`, cardsTable)
+	if _, err := tx.ExecContext(ctx, query, id, pinHash); err != nil {
+		logrus.Errorf("error update card pin into db: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// UsePinAttempt takes one attempt before the PIN is checked, so concurrent
+// requests can't try more PINs than limit. ErrPinAttemptsExhausted is returned
+// when no attempts are left.
+func (r *CardsRepository) UsePinAttempt(ctx context.Context, id uuid.UUID, limit int) (int, error) {
+	var attempts int
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET pin_attempts=pin_attempts+1 WHERE id=$1 AND pin_attempts<$2
+		RETURNING pin_attempts`, cardsTable)
+	if err := sqlx.GetContext(ctx, tx, &attempts, query, id, limit); err != nil {
+		if errors.Is(sql.ErrNoRows, err) {
+			return attempts, ErrPinAttemptsExhausted
+		}
+		logrus.Errorf("error update card pin attempts into db: %s", err)
+		return attempts, ErrInternal
+	}
+
+	return attempts, nil
+}
+
+func (r *CardsRepository) ResetPinAttempts(ctx context.Context, id uuid.UUID) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET pin_attempts=0 WHERE id=$1`, cardsTable)
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		logrus.Errorf("error reset card pin attempts into db: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+type CashoutCodesRepository struct {
+	db        *sqlx.DB
+	ctxGetter transactions.CtxGetterInterface
+}
+
+func NewCashoutCodesRepository(db *sqlx.DB, ctxGetter transactions.CtxGetterInterface) *CashoutCodesRepository {
+	return &CashoutCodesRepository{
+		db:        db,
+		ctxGetter: ctxGetter,
+	}
+}
+
+func (r *CashoutCodesRepository) Create(ctx context.Context, code domain.CashoutCode) (domain.CashoutCode, error) {
+	var created domain.CashoutCode
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, user_id, account_id, amount, code_hash, secret_hash, expires_at)
+		VALUES ((SELECT gen_random_uuid()), $1, $2, $3, $4, $5, $6) RETURNING *`, cashoutCodesTable)
+	row := tx.QueryRowxContext(ctx, query, code.UserId, code.AccountId, code.Amount, code.CodeHash,
+		code.SecretHash, code.ExpiresAt)
+	if err := row.StructScan(&created); err != nil {
+		logrus.Errorf("error insert cashout code into db: %s", err)
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation {
+			return created, ErrCashoutCodeExists
+		}
+		return created, ErrInternal
+	}
+
+	return created, nil
+}
+
+// GetPending returns codes of the user waiting for redemption, newest first.
+func (r *CashoutCodesRepository) GetPending(ctx context.Context, userId uuid.UUID) ([]domain.CashoutCode, error) {
+	codes := []domain.CashoutCode{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE user_id=$1 AND status='pending' ORDER BY created_at DESC`,
+		cashoutCodesTable)
+	if err := sqlx.SelectContext(ctx, tx, &codes, query, userId); err != nil {
+		logrus.Errorf("error select pending cashout codes from db: %s", err)
+		return codes, ErrInternal
+	}
+
+	return codes, nil
+}
+
+// GetByCodeHash returns pending code which isn't expired yet.
+func (r *CashoutCodesRepository) GetByCodeHash(ctx context.Context, codeHash string) (domain.CashoutCode, error) {
+	var code domain.CashoutCode
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE code_hash=$1 AND status='pending' AND expires_at>now()`,
+		cashoutCodesTable)
+	if err := sqlx.GetContext(ctx, tx, &code, query, codeHash); err != nil {
+		if errors.Is(sql.ErrNoRows, err) {
+			return code, ErrCashoutCodeNotFound
+		}
+		logrus.Errorf("error select cashout code from db by code_hash: %s", err)
+		return code, ErrInternal
+	}
+
+	return code, nil
+}
+
+// UseSecretAttempt counts wrong secret entered for the code and returns number
+// of attempts used.
+func (r *CashoutCodesRepository) UseSecretAttempt(ctx context.Context, id uuid.UUID) (int, error) {
+	var attempts int
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET secret_attempts=secret_attempts+1 WHERE id=$1 RETURNING secret_attempts`,
+		cashoutCodesTable)
+	if err := sqlx.GetContext(ctx, tx, &attempts, query, id); err != nil {
+		logrus.Errorf("error update cashout code secret attempts into db: %s", err)
+		return attempts, ErrInternal
+	}
+
+	return attempts, nil
+}
+
+// Finish moves pending code to the final status. ErrCashoutCodeNotFound is
+// returned if the code is not pending anymore, so the code is finished once.
+func (r *CashoutCodesRepository) Finish(ctx context.Context, id uuid.UUID, status string,
+	machineId uuid.NullUUID) (domain.CashoutCode, error) {
+	var code domain.CashoutCode
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET status=$2, machine_id=$3, finished_at=now()
+		WHERE id=$1 AND status='pending' RETURNING *`, cashoutCodesTable)
+	if err := sqlx.GetContext(ctx, tx, &code, query, id, status, machineId); err != nil {
+		if errors.Is(sql.ErrNoRows, err) {
+			return code, ErrCashoutCodeNotFound
+		}
+		logrus.Errorf("error finish cashout code into db: %s", err)
+		return code, ErrInternal
+	}
+
+	return code, nil
+}
+
+// ExpirePending expires codes which weren't redeemed before expiresBefore and returns them.
+func (r *CashoutCodesRepository) ExpirePending(ctx context.Context,
+	expiresBefore time.Time) ([]domain.CashoutCode, error) {
+	codes := []domain.CashoutCode{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET status=$2, finished_at=now()
+		WHERE status='pending' AND expires_at<=$1 RETURNING *`, cashoutCodesTable)
+	err := sqlx.SelectContext(ctx, tx, &codes, query, expiresBefore, domain.CashoutCodeExpired)
+	if err != nil {
+		logrus.Errorf("error expire cashout codes into db: %s", err)
+		return codes, ErrInternal
+	}
+
+	return codes, nil
+}
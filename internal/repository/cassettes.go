@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type CassettesRepository struct {
+	db        *sqlx.DB
+	ctxGetter transactions.CtxGetterInterface
+}
+
+func NewCassettesRepository(db *sqlx.DB, ctxGetter transactions.CtxGetterInterface) *CassettesRepository {
+	return &CassettesRepository{
+		db:        db,
+		ctxGetter: ctxGetter,
+	}
+}
+
+func (r *CassettesRepository) GetAll(ctx context.Context, machineId uuid.UUID) ([]domain.Cassette, error) {
+	cassettes := []domain.Cassette{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE machine_id=$1 ORDER BY denomination DESC`, cassettesTable)
+	if err := sqlx.SelectContext(ctx, tx, &cassettes, query, machineId); err != nil {
+		logrus.Errorf("error select cassettes from db: %s", err)
+		return cassettes, ErrInternal
+	}
+
+	return cassettes, nil
+}
+
+// GetForUpdate locks cassettes of the machine until the end of transaction, so
+// concurrent operations on the machine see the inventory they change.
+func (r *CassettesRepository) GetForUpdate(ctx context.Context, machineId uuid.UUID) ([]domain.Cassette, error) {
+	cassettes := []domain.Cassette{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE machine_id=$1 ORDER BY denomination DESC FOR UPDATE`,
+		cassettesTable)
+	if err := sqlx.SelectContext(ctx, tx, &cassettes, query, machineId); err != nil {
+		logrus.Errorf("error select cassettes for update from db: %s", err)
+		return cassettes, ErrInternal
+	}
+
+	return cassettes, nil
+}
+
+// Add puts notes into cassettes, cassette of a new denomination is created.
+func (r *CassettesRepository) Add(ctx context.Context, machineId uuid.UUID, notes []domain.Note) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`INSERT INTO %s (machine_id, denomination, count) VALUES ($1, $2, $3)
+		ON CONFLICT (machine_id, denomination) DO UPDATE SET count=%s.count+EXCLUDED.count, updated_at=now()`,
+		cassettesTable, cassettesTable)
+	for _, note := range notes {
+		if _, err := tx.ExecContext(ctx, query, machineId, note.Denomination, note.Count); err != nil {
+			logrus.Errorf("error adding notes into cassette in db: %s", err)
+			return ErrInternal
+		}
+	}
+
+	return nil
+}
+
+// Withdraw takes notes out of cassettes. ErrNotEnoughNotes is returned when any
+// cassette has fewer notes.
+func (r *CassettesRepository) Withdraw(ctx context.Context, machineId uuid.UUID, notes []domain.Note) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET count=count-$3, updated_at=now()
+		WHERE machine_id=$1 AND denomination=$2 AND count>=$3`, cassettesTable)
+	for _, note := range notes {
+		result, err := tx.ExecContext(ctx, query, machineId, note.Denomination, note.Count)
+		if err != nil {
+			logrus.Errorf("error withdrawing notes from cassette in db: %s", err)
+			return ErrInternal
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			logrus.Errorf("error getting affected rows when withdrawing notes: %s", err)
+			return ErrInternal
+		}
+		if affected == 0 {
+			return ErrNotEnoughNotes
+		}
+	}
+
+	return nil
+}
+
+// Set stores counted number of notes and low cash threshold of the cassette.
+func (r *CassettesRepository) Set(ctx context.Context, cassette domain.Cassette) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`INSERT INTO %s (machine_id, denomination, count, low_threshold) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (machine_id, denomination) DO UPDATE SET count=EXCLUDED.count,
+		low_threshold=EXCLUDED.low_threshold, updated_at=now()`, cassettesTable)
+	_, err := tx.ExecContext(ctx, query, cassette.MachineId, cassette.Denomination, cassette.Count,
+		cassette.LowThreshold)
+	if err != nil {
+		logrus.Errorf("error setting cassette into db: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type MachineHealthRepository struct {
+	db        *sqlx.DB
+	ctxGetter transactions.CtxGetterInterface
+}
+
+func NewMachineHealthRepository(db *sqlx.DB, ctxGetter transactions.CtxGetterInterface) *MachineHealthRepository {
+	return &MachineHealthRepository{
+		db:        db,
+		ctxGetter: ctxGetter,
+	}
+}
+
+// Save stores heartbeat of the machine and marks it online.
+func (r *MachineHealthRepository) Save(ctx context.Context, heartbeat domain.MachineHeartbeat) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`INSERT INTO %s (machine_id, dispenser, card_reader, software_version, cash_levels)
+		VALUES ($1, $2, $3, $4, $5) ON CONFLICT (machine_id) DO UPDATE SET dispenser=EXCLUDED.dispenser,
+		card_reader=EXCLUDED.card_reader, software_version=EXCLUDED.software_version,
+		cash_levels=EXCLUDED.cash_levels, online=true, last_seen_at=now(), offline_since=NULL`,
+		machineHealthTable)
+	_, err := tx.ExecContext(ctx, query, heartbeat.MachineId, heartbeat.Dispenser, heartbeat.CardReader,
+		heartbeat.SoftwareVersion, heartbeat.CashLevels)
+	if err != nil {
+		logrus.Errorf("error saving machine heartbeat into db: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// MarkOffline marks active machines silent since the time as offline and returns them.
+// Every machine is returned once per outage.
+func (r *MachineHealthRepository) MarkOffline(ctx context.Context,
+	silentSince time.Time) ([]domain.MachineHealth, error) {
+	machines := []domain.MachineHealth{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s h SET online=false, offline_since=now() FROM %s m
+		WHERE m.id=h.machine_id AND m.status=$1 AND h.online AND h.last_seen_at<$2
+		RETURNING h.*, m.name, m.status`, machineHealthTable, machinesTable)
+	err := sqlx.SelectContext(ctx, tx, &machines, query, domain.MachineStatusActive, silentSince)
+	if err != nil {
+		logrus.Errorf("error marking machines offline in db: %s", err)
+		return machines, ErrInternal
+	}
+
+	return machines, nil
+}
+
+// GetFleet returns health of machines which aren't decommissioned.
+func (r *MachineHealthRepository) GetFleet(ctx context.Context) ([]domain.MachineHealth, error) {
+	machines := []domain.MachineHealth{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT m.id AS machine_id, m.name, m.status,
+		COALESCE(h.dispenser, $1) AS dispenser, COALESCE(h.card_reader, $1) AS card_reader,
+		COALESCE(h.software_version, '') AS software_version, COALESCE(h.cash_levels, '[]') AS cash_levels,
+		COALESCE(h.online, false) AS online, h.last_seen_at, h.offline_since,
+		EXISTS (SELECT 1 FROM %s c WHERE c.machine_id=m.id AND c.count<c.low_threshold) AS low_cash
+		FROM %s m LEFT JOIN %s h ON h.machine_id=m.id WHERE m.status<>$2 ORDER BY m.name, m.id`,
+		cassettesTable, machinesTable, machineHealthTable)
+	err := sqlx.SelectContext(ctx, tx, &machines, query, domain.DeviceStatusUnknown,
+		domain.MachineStatusDecommissioned)
+	if err != nil {
+		logrus.Errorf("error select fleet health from db: %s", err)
+		return machines, ErrInternal
+	}
+
+	return machines, nil
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+type MachineJournalRepository struct {
+	db        *sqlx.DB
+	ctxGetter transactions.CtxGetterInterface
+}
+
+func NewMachineJournalRepository(db *sqlx.DB,
+	ctxGetter transactions.CtxGetterInterface) *MachineJournalRepository {
+	return &MachineJournalRepository{
+		db:        db,
+		ctxGetter: ctxGetter,
+	}
+}
+
+func (r *MachineJournalRepository) Create(ctx context.Context, entry domain.JournalEntry) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	outcome := entry.Outcome
+	if outcome == "" {
+		outcome = domain.JournalApproved
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, session_id, cashout_code_id, machine_id, account_id, operation,
+		amount, notes, outcome, reason, reference, third_party, flagged)
+		VALUES ((SELECT gen_random_uuid()), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		machineJournalTable)
+	_, err := tx.ExecContext(ctx, query, entry.SessionId, entry.CashoutCodeId, entry.MachineId, entry.AccountId,
+		entry.Operation, entry.Amount, entry.Notes, outcome, entry.Reason, entry.Reference, entry.ThirdParty,
+		entry.Flagged)
+	if err != nil {
+		logrus.Errorf("error insert journal entry into db: %s", err)
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation {
+			return ErrJournalReferenceExists
+		}
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// GetByAccount returns the latest operations of the account with given types,
+// newest first.
+func (r *MachineJournalRepository) GetByAccount(ctx context.Context, accountId uuid.UUID,
+	operations []string, limit int, offset int) ([]domain.JournalEntry, error) {
+	entries := []domain.JournalEntry{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE account_id=$1 AND operation=ANY($2) AND outcome='approved'
+		ORDER BY created_at DESC LIMIT $3 OFFSET $4`, machineJournalTable)
+	err := sqlx.SelectContext(ctx, tx, &entries, query, accountId, pq.StringArray(operations), limit, offset)
+	if err != nil {
+		logrus.Errorf("error select journal entries from db by account_id: %s", err)
+		return entries, ErrInternal
+	}
+
+	return entries, nil
+}
+
+// GetAllByAccount returns all operations of the account with given types,
+// oldest first.
+func (r *MachineJournalRepository) GetAllByAccount(ctx context.Context, accountId uuid.UUID,
+	operations []string) ([]domain.JournalEntry, error) {
+	entries := []domain.JournalEntry{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE account_id=$1 AND operation=ANY($2) AND outcome='approved'
+		ORDER BY created_at`, machineJournalTable)
+	err := sqlx.SelectContext(ctx, tx, &entries, query, accountId, pq.StringArray(operations))
+	if err != nil {
+		logrus.Errorf("error select all journal entries from db by account_id: %s", err)
+		return entries, ErrInternal
+	}
+
+	return entries, nil
+}
+
+// GetByReference returns approved operation of the machine by its reference.
+func (r *MachineJournalRepository) GetByReference(ctx context.Context, machineId uuid.UUID, operation string,
+	reference string) (domain.JournalEntry, error) {
+	var entry domain.JournalEntry
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE machine_id=$1 AND operation=$2 AND reference=$3
+		AND outcome='approved'`, machineJournalTable)
+	if err := sqlx.GetContext(ctx, tx, &entry, query, machineId, operation, reference); err != nil {
+		if errors.Is(sql.ErrNoRows, err) {
+			return entry, ErrJournalEntryNotFound
+		}
+		logrus.Errorf("error select journal entry from db by reference: %s", err)
+		return entry, ErrInternal
+	}
+
+	return entry, nil
+}
+
+// GetByMachine returns operations of the machine made in [from, to), newest first.
+func (r *MachineJournalRepository) GetByMachine(ctx context.Context, machineId uuid.UUID, from time.Time,
+	to time.Time, limit int, offset int) ([]domain.JournalEntry, error) {
+	entries := []domain.JournalEntry{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE machine_id=$1 AND created_at>=$2 AND created_at<$3
+		ORDER BY created_at DESC LIMIT $4 OFFSET $5`, machineJournalTable)
+	err := sqlx.SelectContext(ctx, tx, &entries, query, machineId, from, to, limit, offset)
+	if err != nil {
+		logrus.Errorf("error select journal entries from db by machine_id: %s", err)
+		return entries, ErrInternal
+	}
+
+	return entries, nil
+}
+
+// Totals sums up cash operations of the machine made in [from, to). Reversed
+// cash outs are not counted as dispensed.
+func (r *MachineJournalRepository) Totals(ctx context.Context, machineId uuid.UUID, from time.Time,
+	to time.Time) (domain.JournalTotals, error) {
+	var totals domain.JournalTotals
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT
+			COALESCE(SUM(amount) FILTER (WHERE operation='cash_out' AND outcome='approved'), 0)
+				- COALESCE(SUM(amount) FILTER (WHERE operation='reversal' AND outcome='approved'), 0) AS dispensed,
+			COUNT(*) FILTER (WHERE operation='cash_out' AND outcome='approved')
+				- COUNT(*) FILTER (WHERE operation='reversal' AND outcome='approved') AS dispensed_count,
+			COALESCE(SUM(amount) FILTER (WHERE operation='deposit' AND outcome='approved'), 0) AS deposited,
+			COUNT(*) FILTER (WHERE operation='deposit' AND outcome='approved') AS deposited_count,
+			COUNT(*) FILTER (WHERE outcome='declined') AS declined_count
+		FROM %s WHERE machine_id=$1 AND created_at>=$2 AND created_at<$3`, machineJournalTable)
+	if err := sqlx.GetContext(ctx, tx, &totals, query, machineId, from, to); err != nil {
+		logrus.Errorf("error select journal totals from db: %s", err)
+		return totals, ErrInternal
+	}
+
+	return totals, nil
+}
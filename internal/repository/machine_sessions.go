@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+type MachineSessionsRepository struct {
+	db        *sqlx.DB
+	ctxGetter transactions.CtxGetterInterface
+}
+
+func NewMachineSessionsRepository(db *sqlx.DB,
+	ctxGetter transactions.CtxGetterInterface) *MachineSessionsRepository {
+	return &MachineSessionsRepository{
+		db:        db,
+		ctxGetter: ctxGetter,
+	}
+}
+
+func (r *MachineSessionsRepository) Create(ctx context.Context,
+	session domain.MachineSession) (domain.MachineSession, error) {
+	var created domain.MachineSession
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, machine_id, channel, card_id, user_id, account_id)
+		VALUES ((SELECT gen_random_uuid()), $1, $2, $3, $4, $5) RETURNING *`, machineSessionsTable)
+	row := tx.QueryRowxContext(ctx, query, session.MachineId, session.Channel, session.CardId,
+		session.UserId, session.AccountId)
+	if err := row.StructScan(&created); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation {
+			return created, ErrMachineSessionOpen
+		}
+		logrus.Errorf("error insert machine session into db: %s", err)
+		return created, ErrInternal
+	}
+
+	return created, nil
+}
+
+// Touch prolongs the session if it's open, belongs to the machine and was
+// active after idleSince. ErrMachineSessionNotFound is returned otherwise.
+func (r *MachineSessionsRepository) Touch(ctx context.Context, id uuid.UUID, machineId uuid.UUID,
+	idleSince time.Time) (domain.MachineSession, error) {
+	var session domain.MachineSession
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET last_activity_at=now()
+		WHERE id=$1 AND machine_id=$2 AND closed_at IS NULL AND last_activity_at>$3 RETURNING *`,
+		machineSessionsTable)
+	if err := sqlx.GetContext(ctx, tx, &session, query, id, machineId, idleSince); err != nil {
+		if errors.Is(sql.ErrNoRows, err) {
+			return session, ErrMachineSessionNotFound
+		}
+		logrus.Errorf("error update machine session activity into db: %s", err)
+		return session, ErrInternal
+	}
+
+	return session, nil
+}
+
+func (r *MachineSessionsRepository) Close(ctx context.Context, id uuid.UUID, reason string) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET closed_at=now(), close_reason=$2 WHERE id=$1 AND closed_at IS NULL`,
+		machineSessionsTable)
+	if _, err := tx.ExecContext(ctx, query, id, reason); err != nil {
+		logrus.Errorf("error close machine session into db: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// CloseOpen closes session left open on the machine through the channel and
+// returns it. Nil is returned when there's no such session.
+func (r *MachineSessionsRepository) CloseOpen(ctx context.Context, machineId uuid.UUID, channel string,
+	reason string) (*domain.MachineSession, error) {
+	var session domain.MachineSession
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET closed_at=now(), close_reason=$3
+		WHERE machine_id=$1 AND channel=$2 AND closed_at IS NULL RETURNING *`, machineSessionsTable)
+	if err := sqlx.GetContext(ctx, tx, &session, query, machineId, channel, reason); err != nil {
+		if errors.Is(sql.ErrNoRows, err) {
+			return nil, nil
+		}
+		logrus.Errorf("error close open machine session into db: %s", err)
+		return nil, ErrInternal
+	}
+
+	return &session, nil
+}
+
+// CloseIdle closes sessions which weren't active after idleSince and returns them.
+func (r *MachineSessionsRepository) CloseIdle(ctx context.Context,
+	idleSince time.Time) ([]domain.MachineSession, error) {
+	sessions := []domain.MachineSession{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET closed_at=now(), close_reason=$2
+		WHERE closed_at IS NULL AND last_activity_at<=$1 RETURNING *`, machineSessionsTable)
+	err := sqlx.SelectContext(ctx, tx, &sessions, query, idleSince, domain.MachineSessionTimeout)
+	if err != nil {
+		logrus.Errorf("error close idle machine sessions into db: %s", err)
+		return sessions, ErrInternal
+	}
+
+	return sessions, nil
+}
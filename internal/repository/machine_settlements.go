@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type MachineSettlementsRepository struct {
+	db        *sqlx.DB
+	ctxGetter transactions.CtxGetterInterface
+}
+
+func NewMachineSettlementsRepository(db *sqlx.DB,
+	ctxGetter transactions.CtxGetterInterface) *MachineSettlementsRepository {
+	return &MachineSettlementsRepository{
+		db:        db,
+		ctxGetter: ctxGetter,
+	}
+}
+
+func (r *MachineSettlementsRepository) Create(ctx context.Context,
+	settlement domain.MachineSettlement) (domain.MachineSettlement, error) {
+	var created domain.MachineSettlement
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, machine_id, period_start, period_end, dispensed,
+		dispensed_count, deposited, deposited_count, declined_count, expected, counted, discrepancy, settled_by)
+		VALUES ((SELECT gen_random_uuid()), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING *`,
+		machineSettlementsTable)
+	row := tx.QueryRowxContext(ctx, query, settlement.MachineId, settlement.PeriodStart, settlement.PeriodEnd,
+		settlement.Dispensed, settlement.DispensedCount, settlement.Deposited, settlement.DepositedCount,
+		settlement.DeclinedCount, settlement.Expected, settlement.Counted, settlement.Discrepancy,
+		settlement.SettledBy)
+	if err := row.StructScan(&created); err != nil {
+		logrus.Errorf("error insert machine settlement into db: %s", err)
+		return created, ErrInternal
+	}
+
+	return created, nil
+}
+
+// GetLast returns the latest settlement of the machine. Nil is returned when
+// the machine was never settled.
+func (r *MachineSettlementsRepository) GetLast(ctx context.Context,
+	machineId uuid.UUID) (*domain.MachineSettlement, error) {
+	var settlement domain.MachineSettlement
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE machine_id=$1 ORDER BY period_end DESC LIMIT 1`,
+		machineSettlementsTable)
+	if err := sqlx.GetContext(ctx, tx, &settlement, query, machineId); err != nil {
+		if errors.Is(sql.ErrNoRows, err) {
+			return nil, nil
+		}
+		logrus.Errorf("error select last machine settlement from db: %s", err)
+		return nil, ErrInternal
+	}
+
+	return &settlement, nil
+}
+
+// GetByMachine returns settlements of the machine, newest first.
+func (r *MachineSettlementsRepository) GetByMachine(ctx context.Context, machineId uuid.UUID, limit int,
+	offset int) ([]domain.MachineSettlement, error) {
+	settlements := []domain.MachineSettlement{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE machine_id=$1 ORDER BY period_end DESC LIMIT $2 OFFSET $3`,
+		machineSettlementsTable)
+	if err := sqlx.SelectContext(ctx, tx, &settlements, query, machineId, limit, offset); err != nil {
+		logrus.Errorf("error select machine settlements from db: %s", err)
+		return settlements, ErrInternal
+	}
+
+	return settlements, nil
+}
+
+// GetAll returns settlements of all machines, newest first. Only settlements
+// with discrepancy are returned if discrepancyOnly is set.
+func (r *MachineSettlementsRepository) GetAll(ctx context.Context, discrepancyOnly bool, limit int,
+	offset int) ([]domain.MachineSettlement, error) {
+	settlements := []domain.MachineSettlement{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE NOT $1 OR discrepancy ORDER BY period_end DESC
+		LIMIT $2 OFFSET $3`, machineSettlementsTable)
+	if err := sqlx.SelectContext(ctx, tx, &settlements, query, discrepancyOnly, limit, offset); err != nil {
+		logrus.Errorf("error select settlements from db: %s", err)
+		return settlements, ErrInternal
+	}
+
+	return settlements, nil
+}
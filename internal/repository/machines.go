@@ -5,11 +5,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
 	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 )
 
@@ -39,3 +41,166 @@ func (r *MachinesRepository) Get(ctx context.Context, id uuid.UUID) (domain.Mach
 
 	return machine, nil
 }
+
+func (r *MachinesRepository) SetTokenId(ctx context.Context, id uuid.UUID, tokenId uuid.UUID) error {
+	tx := r.CtxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s m SET token_id=$1 WHERE id=$2`, machinesTable)
+	result, err := tx.ExecContext(ctx, query, tokenId, id)
+	if err != nil {
+		logrus.Errorf("error update machine token id into db: %s", err)
+		return ErrInternal
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logrus.Errorf("error getting affected rows when updating machine token id: %s", err)
+		return ErrInternal
+	}
+	if affected == 0 {
+		return ErrMachineNotFound
+	}
+
+	return nil
+}
+
+func (r *MachinesRepository) Create(ctx context.Context, machine domain.Machine) (domain.Machine, error) {
+	var created domain.Machine
+	tx := r.CtxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, token_id, name, address, latitude, longitude, operations,
+		currency, status) VALUES ((SELECT gen_random_uuid()), $1, $2, $3, $4, $5, $6, $7, $8) RETURNING *`,
+		machinesTable)
+	row := tx.QueryRowxContext(ctx, query, machine.TokenId, machine.Name, machine.Address, machine.Latitude,
+		machine.Longitude, machine.Operations, machine.Currency, machine.Status)
+	if err := row.StructScan(&created); err != nil {
+		logrus.Errorf("error insert machine into db: %s", err)
+		return created, ErrInternal
+	}
+
+	return created, nil
+}
+
+// GetAll returns machines ordered by name. Empty status means any status.
+func (r *MachinesRepository) GetAll(ctx context.Context, status string, limit int,
+	offset int) ([]domain.Machine, error) {
+	machines := []domain.Machine{}
+	tx := r.CtxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s m WHERE $1='' OR status=$1 ORDER BY name, id LIMIT $2 OFFSET $3`,
+		machinesTable)
+	if err := sqlx.SelectContext(ctx, tx, &machines, query, status, limit, offset); err != nil {
+		logrus.Errorf("error select machines from db: %s", err)
+		return machines, ErrInternal
+	}
+
+	return machines, nil
+}
+
+// Update changes the machine unless it's decommissioned, ErrMachineNotFound is
+// returned for decommissioned machine.
+func (r *MachinesRepository) Update(ctx context.Context, id uuid.UUID,
+	data domain.MachineUpdate) (domain.Machine, error) {
+	var machine domain.Machine
+	tx := r.CtxGetter.TrOrDb(ctx, r.db)
+
+	values := []interface{}{}
+	names := []string{}
+	argId := 1
+
+	addField := func(field string, value interface{}) {
+		values = append(values, value)
+		names = append(names, fmt.Sprintf("%s=$%d", field, argId))
+		argId++
+	}
+
+	if data.Name != nil {
+		addField("name", *data.Name)
+	}
+	if data.Address != nil {
+		addField("address", *data.Address)
+	}
+	if data.Latitude != nil {
+		addField("latitude", *data.Latitude)
+	}
+	if data.Longitude != nil {
+		addField("longitude", *data.Longitude)
+	}
+	if data.Operations != nil {
+		addField("operations", pq.StringArray(*data.Operations))
+	}
+	if data.Currency != nil {
+		addField("currency", *data.Currency)
+	}
+	if data.Status != nil {
+		addField("status", *data.Status)
+	}
+	if len(names) == 0 {
+		return r.Get(ctx, id)
+	}
+	values = append(values, id)
+
+	querySet := strings.Join(names, ", ")
+	values = append(values, domain.MachineStatusDecommissioned)
+	query := fmt.Sprintf("UPDATE %s m SET %s WHERE id=$%d AND status<>$%d RETURNING m.*", machinesTable,
+		querySet, argId, argId+1)
+
+	row := tx.QueryRowxContext(ctx, query, values...)
+	if err := row.StructScan(&machine); err != nil {
+		logrus.Errorf("error updating machine into db by id: %s", err)
+		if errors.Is(sql.ErrNoRows, err) {
+			return machine, ErrMachineNotFound
+		}
+		return machine, ErrInternal
+	}
+
+	return machine, nil
+}
+
+// Decommission retires the machine for good, its token stops working.
+func (r *MachinesRepository) Decommission(ctx context.Context, id uuid.UUID) error {
+	tx := r.CtxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s m SET status=$1, token_id=NULL, decommissioned_at=now()
+		WHERE id=$2`, machinesTable)
+	result, err := tx.ExecContext(ctx, query, domain.MachineStatusDecommissioned, id)
+	if err != nil {
+		logrus.Errorf("error decommission machine into db: %s", err)
+		return ErrInternal
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logrus.Errorf("error getting affected rows when decommissioning machine: %s", err)
+		return ErrInternal
+	}
+	if affected == 0 {
+		return ErrMachineNotFound
+	}
+
+	return nil
+}
+
+// GetNearby returns active machines within the box supporting the operation.
+// Empty operation means any operation.
+func (r *MachinesRepository) GetNearby(ctx context.Context, box domain.BoundingBox,
+	operation string) ([]domain.NearbyMachine, error) {
+	machines := []domain.NearbyMachine{}
+	tx := r.CtxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT m.*, COALESCE(h.online, false) AS online,
+		COALESCE(h.dispenser=$7 OR h.card_reader=$7, false) AS faulty,
+		EXISTS (SELECT 1 FROM %s c WHERE c.machine_id=m.id AND c.count>0) AS has_cash,
+		EXISTS (SELECT 1 FROM %s c WHERE c.machine_id=m.id AND c.count<c.low_threshold) AS low_cash
+		FROM %s m LEFT JOIN %s h ON h.machine_id=m.id
+		WHERE m.status=$1 AND m.latitude BETWEEN $2 AND $3
+		AND (($4<=$5 AND m.longitude BETWEEN $4 AND $5) OR ($4>$5 AND (m.longitude>=$4 OR m.longitude<=$5)))
+		AND ($6='' OR $6=ANY(m.operations))`,
+		cassettesTable, cassettesTable, machinesTable, machineHealthTable)
+	err := sqlx.SelectContext(ctx, tx, &machines, query, domain.MachineStatusActive, box.MinLatitude,
+		box.MaxLatitude, box.MinLongitude, box.MaxLongitude, operation, domain.DeviceStatusFault)
+	if err != nil {
+		logrus.Errorf("error select nearby machines from db: %s", err)
+		return machines, ErrInternal
+	}
+
+	return machines, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type PasskeysRepository struct {
+	db        *sqlx.DB
+	ctxGetter transactions.CtxGetterInterface
+}
+
+func NewPasskeysRepository(db *sqlx.DB, ctxGetter transactions.CtxGetterInterface) *PasskeysRepository {
+	return &PasskeysRepository{
+		db:        db,
+		ctxGetter: ctxGetter,
+	}
+}
+
+func (r *PasskeysRepository) Create(ctx context.Context, passkey domain.Passkey) (domain.Passkey, error) {
+	var created domain.Passkey
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, user_id, name, credential_id, public_key, attestation_type,
+		aaguid, sign_count, transports, backup_eligible)
+		VALUES ((SELECT gen_random_uuid()), $1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *`, passkeysTable)
+	row := tx.QueryRowxContext(ctx, query, passkey.UserId, passkey.Name, passkey.CredentialId,
+		passkey.PublicKey, passkey.AttestationType, passkey.AAGUID, passkey.SignCount, passkey.Transports,
+		passkey.BackupEligible)
+	if err := row.StructScan(&created); err != nil {
+		logrus.Errorf("error insert passkey into db: %s", err)
+		return created, ErrInternal
+	}
+
+	return created, nil
+}
+
+func (r *PasskeysRepository) GetAll(ctx context.Context, userId uuid.UUID) ([]domain.Passkey, error) {
+	passkeys := []domain.Passkey{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE user_id=$1 ORDER BY created_at`, passkeysTable)
+	if err := sqlx.SelectContext(ctx, tx, &passkeys, query, userId); err != nil {
+		logrus.Errorf("error select passkeys from db by user_id: %s", err)
+		return passkeys, ErrInternal
+	}
+
+	return passkeys, nil
+}
+
+func (r *PasskeysRepository) Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id=$1 AND user_id=$2`, passkeysTable)
+	result, err := tx.ExecContext(ctx, query, id, userId)
+	if err != nil {
+		logrus.Errorf("error delete passkey from db: %s", err)
+		return ErrInternal
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logrus.Errorf("error getting affected rows when deleting passkey: %s", err)
+		return ErrInternal
+	}
+	if affected == 0 {
+		return ErrPasskeyNotFound
+	}
+
+	return nil
+}
+
+func (r *PasskeysRepository) DeleteAll(ctx context.Context, userId uuid.UUID) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE user_id=$1`, passkeysTable)
+	if _, err := tx.ExecContext(ctx, query, userId); err != nil {
+		logrus.Errorf("error delete passkeys from db by user_id: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// UpdateSignCount saves signature counter of the last assertion. The counter
+// never goes back, so concurrent assertions can't lower it.
+func (r *PasskeysRepository) UpdateSignCount(ctx context.Context, id uuid.UUID, signCount int64) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET sign_count=GREATEST(sign_count, $2), last_used_at=now()
+		WHERE id=$1`, passkeysTable)
+	if _, err := tx.ExecContext(ctx, query, id, signCount); err != nil {
+		logrus.Errorf("error update passkey sign count into db: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+// RecipientsRepository keeps accounts the user has already transferred money to.
+type RecipientsRepository struct {
+	db        *sqlx.DB
+	ctxGetter transactions.CtxGetterInterface
+}
+
+func NewRecipientsRepository(db *sqlx.DB, ctxGetter transactions.CtxGetterInterface) *RecipientsRepository {
+	return &RecipientsRepository{
+		db:        db,
+		ctxGetter: ctxGetter,
+	}
+}
+
+func (r *RecipientsRepository) Known(ctx context.Context, userId uuid.UUID, accountId uuid.UUID) (bool, error) {
+	var known bool
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE user_id=$1 AND account_id=$2)`,
+		recipientsTable)
+	if err := sqlx.GetContext(ctx, tx, &known, query, userId, accountId); err != nil {
+		logrus.Errorf("error checking known recipient in db: %s", err)
+		return false, ErrInternal
+	}
+
+	return known, nil
+}
+
+func (r *RecipientsRepository) Add(ctx context.Context, userId uuid.UUID, accountId uuid.UUID) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, account_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, recipientsTable)
+	if _, err := tx.ExecContext(ctx, query, userId, accountId); err != nil {
+		logrus.Errorf("error insert recipient into db: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
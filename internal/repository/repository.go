@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
 	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
@@ -14,6 +15,25 @@ var (
 	usersTable    = "users"
 	accountsTable = "accounts"
 	machinesTable = "machines"
+
+	twoFactorTable     = "two_factor"
+	recoveryCodesTable = "recovery_codes"
+
+	adminActionsTable = "admin_actions"
+	apiKeysTable      = "api_keys"
+	sessionsTable     = "sessions"
+	recipientsTable   = "transfer_recipients"
+	passkeysTable     = "passkeys"
+	cassettesTable    = "machine_cassettes"
+
+	machineHealthTable = "machine_health"
+	cardsTable         = "cards"
+
+	machineSessionsTable = "machine_sessions"
+	machineJournalTable  = "machine_journal"
+
+	machineSettlementsTable = "machine_settlements"
+	cashoutCodesTable       = "cashout_codes"
 )
 
 var (
@@ -22,6 +42,23 @@ var (
 	ErrAccountNotFound    = errors.New("account not found")
 	ErrSessionDoesntExist = errors.New("session doesn't exist")
 	ErrMachineNotFound    = errors.New("machine not found")
+
+	ErrTwoFactorNotFound    = errors.New("two factor not found")
+	ErrRecoveryCodeNotFound = errors.New("recovery code not found")
+	ErrApiKeyNotFound       = errors.New("api key not found")
+	ErrPasskeyNotFound      = errors.New("passkey not found")
+	ErrNotEnoughNotes       = errors.New("not enough notes in cassette")
+	ErrCardNotFound         = errors.New("card not found")
+	ErrCardPanExists        = errors.New("card with the pan already exists")
+	ErrPinAttemptsExhausted = errors.New("pin attempts exhausted")
+
+	ErrMachineSessionNotFound = errors.New("machine session not found")
+	ErrMachineSessionOpen     = errors.New("machine session is already open")
+	ErrCashoutCodeNotFound    = errors.New("cashout code not found")
+	ErrCashoutCodeExists      = errors.New("cashout code already exists")
+	ErrJournalEntryNotFound   = errors.New("journal entry not found")
+	ErrJournalReferenceExists = errors.New("journal entry with the reference already exists")
+	ErrInsufficientFunds      = errors.New("insufficient funds in the account")
 )
 
 type Users interface {
@@ -30,24 +67,159 @@ type Users interface {
 	GetByEmail(ctx context.Context, email string) (domain.User, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	Update(ctx context.Context, id uuid.UUID, data domain.UserUpdate) (domain.User, error)
+	Search(ctx context.Context, query string, limit int, offset int) ([]domain.User, error)
+	Erase(ctx context.Context, id uuid.UUID) error
 }
 
 type Accounts interface {
 	Create(ctx context.Context, userId uuid.UUID, account domain.Account) (uuid.UUID, error)
 	Get(ctx context.Context, id uuid.UUID) (domain.Account, error)
 	GetAll(ctx context.Context, userId uuid.UUID) ([]domain.Account, error)
+	GetAllForUpdate(ctx context.Context, userId uuid.UUID) ([]domain.Account, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	Update(ctx context.Context, id uuid.UUID, data domain.AccountUpdate) (domain.Account, error)
+	Debit(ctx context.Context, id uuid.UUID, amount int) (domain.Account, error)
+	Credit(ctx context.Context, id uuid.UUID, amount int) (domain.Account, error)
 }
 
 type Machines interface {
+	Create(ctx context.Context, machine domain.Machine) (domain.Machine, error)
 	Get(ctx context.Context, id uuid.UUID) (domain.Machine, error)
+	GetAll(ctx context.Context, status string, limit int, offset int) ([]domain.Machine, error)
+	Update(ctx context.Context, id uuid.UUID, data domain.MachineUpdate) (domain.Machine, error)
+	Decommission(ctx context.Context, id uuid.UUID) error
+	SetTokenId(ctx context.Context, id uuid.UUID, tokenId uuid.UUID) error
+	GetNearby(ctx context.Context, box domain.BoundingBox, operation string) ([]domain.NearbyMachine, error)
+}
+
+type TwoFactor interface {
+	Get(ctx context.Context, userId uuid.UUID) (domain.TwoFactor, error)
+	Save(ctx context.Context, userId uuid.UUID, secret string) error
+	Enable(ctx context.Context, userId uuid.UUID) error
+	Delete(ctx context.Context, userId uuid.UUID) error
+	SetRecoveryCodes(ctx context.Context, userId uuid.UUID, hashes []string) error
+	UseRecoveryCode(ctx context.Context, userId uuid.UUID, hash string) error
+}
+
+type AdminActions interface {
+	Create(ctx context.Context, action domain.AdminAction) error
+}
+
+type ApiKeys interface {
+	Create(ctx context.Context, key domain.ApiKey) (domain.ApiKey, error)
+	GetByHash(ctx context.Context, hash string) (domain.ApiKey, error)
+	GetAll(ctx context.Context, userId uuid.UUID) ([]domain.ApiKey, error)
+	Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+	DeleteAll(ctx context.Context, userId uuid.UUID) error
+	Touch(ctx context.Context, id uuid.UUID) error
+}
+
+type Sessions interface {
+	Create(ctx context.Context, session domain.Session) (uuid.UUID, error)
+	GetActive(ctx context.Context, userId uuid.UUID) ([]domain.Session, error)
+	GetAll(ctx context.Context, userId uuid.UUID) ([]domain.Session, error)
+	KnownDevice(ctx context.Context, userId uuid.UUID, userAgent string) (bool, error)
+	Revoke(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+	RevokeAll(ctx context.Context, userId uuid.UUID) error
+	Touch(ctx context.Context, id uuid.UUID) error
+}
+
+type Recipients interface {
+	Known(ctx context.Context, userId uuid.UUID, accountId uuid.UUID) (bool, error)
+	Add(ctx context.Context, userId uuid.UUID, accountId uuid.UUID) error
+}
+
+type Passkeys interface {
+	Create(ctx context.Context, passkey domain.Passkey) (domain.Passkey, error)
+	GetAll(ctx context.Context, userId uuid.UUID) ([]domain.Passkey, error)
+	Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+	DeleteAll(ctx context.Context, userId uuid.UUID) error
+	UpdateSignCount(ctx context.Context, id uuid.UUID, signCount int64) error
+}
+
+type Cassettes interface {
+	GetAll(ctx context.Context, machineId uuid.UUID) ([]domain.Cassette, error)
+	GetForUpdate(ctx context.Context, machineId uuid.UUID) ([]domain.Cassette, error)
+	Add(ctx context.Context, machineId uuid.UUID, notes []domain.Note) error
+	Withdraw(ctx context.Context, machineId uuid.UUID, notes []domain.Note) error
+	Set(ctx context.Context, cassette domain.Cassette) error
+}
+
+type MachineHealth interface {
+	Save(ctx context.Context, heartbeat domain.MachineHeartbeat) error
+	MarkOffline(ctx context.Context, silentSince time.Time) ([]domain.MachineHealth, error)
+	GetFleet(ctx context.Context) ([]domain.MachineHealth, error)
+}
+
+type Cards interface {
+	Create(ctx context.Context, card domain.Card) (domain.Card, error)
+	Get(ctx context.Context, id uuid.UUID) (domain.Card, error)
+	GetByPanHash(ctx context.Context, panHash string) (domain.Card, error)
+	GetAll(ctx context.Context, userId uuid.UUID) ([]domain.Card, error)
+	SetStatus(ctx context.Context, id uuid.UUID, status string) error
+	BlockAll(ctx context.Context, userId uuid.UUID) error
+	SetPin(ctx context.Context, id uuid.UUID, pinHash string) error
+	UsePinAttempt(ctx context.Context, id uuid.UUID, limit int) (int, error)
+	ResetPinAttempts(ctx context.Context, id uuid.UUID) error
+}
+
+type MachineSessions interface {
+	Create(ctx context.Context, session domain.MachineSession) (domain.MachineSession, error)
+	Touch(ctx context.Context, id uuid.UUID, machineId uuid.UUID,
+		idleSince time.Time) (domain.MachineSession, error)
+	Close(ctx context.Context, id uuid.UUID, reason string) error
+	CloseOpen(ctx context.Context, machineId uuid.UUID, channel string,
+		reason string) (*domain.MachineSession, error)
+	CloseIdle(ctx context.Context, idleSince time.Time) ([]domain.MachineSession, error)
+}
+
+type MachineJournal interface {
+	Create(ctx context.Context, entry domain.JournalEntry) error
+	GetByAccount(ctx context.Context, accountId uuid.UUID, operations []string, limit int,
+		offset int) ([]domain.JournalEntry, error)
+	GetAllByAccount(ctx context.Context, accountId uuid.UUID, operations []string) ([]domain.JournalEntry, error)
+	GetByReference(ctx context.Context, machineId uuid.UUID, operation string,
+		reference string) (domain.JournalEntry, error)
+	GetByMachine(ctx context.Context, machineId uuid.UUID, from time.Time, to time.Time, limit int,
+		offset int) ([]domain.JournalEntry, error)
+	Totals(ctx context.Context, machineId uuid.UUID, from time.Time, to time.Time) (domain.JournalTotals, error)
+}
+
+type MachineSettlements interface {
+	Create(ctx context.Context, settlement domain.MachineSettlement) (domain.MachineSettlement, error)
+	GetLast(ctx context.Context, machineId uuid.UUID) (*domain.MachineSettlement, error)
+	GetByMachine(ctx context.Context, machineId uuid.UUID, limit int,
+		offset int) ([]domain.MachineSettlement, error)
+	GetAll(ctx context.Context, discrepancyOnly bool, limit int, offset int) ([]domain.MachineSettlement, error)
+}
+
+type CashoutCodes interface {
+	Create(ctx context.Context, code domain.CashoutCode) (domain.CashoutCode, error)
+	GetPending(ctx context.Context, userId uuid.UUID) ([]domain.CashoutCode, error)
+	GetByCodeHash(ctx context.Context, codeHash string) (domain.CashoutCode, error)
+	UseSecretAttempt(ctx context.Context, id uuid.UUID) (int, error)
+	Finish(ctx context.Context, id uuid.UUID, status string, machineId uuid.NullUUID) (domain.CashoutCode, error)
+	ExpirePending(ctx context.Context, expiresBefore time.Time) ([]domain.CashoutCode, error)
 }
 
 type Repository struct {
 	Users
 	Accounts
 	Machines
+	TwoFactor
+	AdminActions
+	ApiKeys
+	Sessions
+	Recipients
+	Passkeys
+	Cassettes
+	MachineHealth
+	Cards
+
+	MachineSessions
+	MachineJournal
+	MachineSettlements
+	CashoutCodes
 }
 
 type Deps struct {
@@ -57,8 +229,25 @@ type Deps struct {
 
 func NewRepository(deps Deps) *Repository {
 	return &Repository{
-		Users:    NewUsersRepository(deps.DB, deps.CtxGetter),
-		Accounts: NewAccountsRepository(deps.DB, deps.CtxGetter),
-		Machines: NewMachinesRepository(deps.DB, deps.CtxGetter),
+		Users:     NewUsersRepository(deps.DB, deps.CtxGetter),
+		Accounts:  NewAccountsRepository(deps.DB, deps.CtxGetter),
+		Machines:  NewMachinesRepository(deps.DB, deps.CtxGetter),
+		TwoFactor: NewTwoFactorRepository(deps.DB, deps.CtxGetter),
+
+		AdminActions: NewAdminActionsRepository(deps.DB, deps.CtxGetter),
+		ApiKeys:      NewApiKeysRepository(deps.DB, deps.CtxGetter),
+		Sessions:     NewSessionsRepository(deps.DB, deps.CtxGetter),
+		Recipients:   NewRecipientsRepository(deps.DB, deps.CtxGetter),
+		Passkeys:     NewPasskeysRepository(deps.DB, deps.CtxGetter),
+		Cassettes:    NewCassettesRepository(deps.DB, deps.CtxGetter),
+
+		MachineHealth: NewMachineHealthRepository(deps.DB, deps.CtxGetter),
+		Cards:         NewCardsRepository(deps.DB, deps.CtxGetter),
+
+		MachineSessions: NewMachineSessionsRepository(deps.DB, deps.CtxGetter),
+		MachineJournal:  NewMachineJournalRepository(deps.DB, deps.CtxGetter),
+
+		MachineSettlements: NewMachineSettlementsRepository(deps.DB, deps.CtxGetter),
+		CashoutCodes:       NewCashoutCodesRepository(deps.DB, deps.CtxGetter),
 	}
 }
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type SessionsRepository struct {
+	db        *sqlx.DB
+	ctxGetter transactions.CtxGetterInterface
+}
+
+func NewSessionsRepository(db *sqlx.DB, ctxGetter transactions.CtxGetterInterface) *SessionsRepository {
+	return &SessionsRepository{
+		db:        db,
+		ctxGetter: ctxGetter,
+	}
+}
+
+func (r *SessionsRepository) Create(ctx context.Context, session domain.Session) (uuid.UUID, error) {
+	var id uuid.UUID
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, user_id, user_agent, ip, expires_at)
+		VALUES ((SELECT gen_random_uuid()), $1, $2, $3, $4) RETURNING id`, sessionsTable)
+	row := tx.QueryRowxContext(ctx, query, session.UserId, session.UserAgent, session.Ip, session.ExpiresAt)
+	if err := row.Scan(&id); err != nil {
+		logrus.Errorf("error insert session into db: %s", err)
+		return id, ErrInternal
+	}
+
+	return id, nil
+}
+
+// GetActive returns not revoked and not expired sessions of the user.
+func (r *SessionsRepository) GetActive(ctx context.Context, userId uuid.UUID) ([]domain.Session, error) {
+	sessions := []domain.Session{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE user_id=$1 AND revoked_at IS NULL AND expires_at > now()
+		ORDER BY last_seen_at DESC`, sessionsTable)
+	if err := sqlx.SelectContext(ctx, tx, &sessions, query, userId); err != nil {
+		logrus.Errorf("error select active sessions from db by user_id: %s", err)
+		return sessions, ErrInternal
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionsRepository) GetAll(ctx context.Context, userId uuid.UUID) ([]domain.Session, error) {
+	sessions := []domain.Session{}
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE user_id=$1 ORDER BY created_at`, sessionsTable)
+	if err := sqlx.SelectContext(ctx, tx, &sessions, query, userId); err != nil {
+		logrus.Errorf("error select sessions from db by user_id: %s", err)
+		return sessions, ErrInternal
+	}
+
+	return sessions, nil
+}
+
+// KnownDevice reports whether the user has already signed in with the user agent.
+func (r *SessionsRepository) KnownDevice(ctx context.Context, userId uuid.UUID, userAgent string) (bool, error) {
+	var known bool
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE user_id=$1 AND user_agent=$2)`, sessionsTable)
+	if err := sqlx.GetContext(ctx, tx, &known, query, userId, userAgent); err != nil {
+		logrus.Errorf("error checking known device in db: %s", err)
+		return false, ErrInternal
+	}
+
+	return known, nil
+}
+
+// Revoke is idempotent, revoking already revoked session succeeds and keeps
+// the time it was revoked at.
+func (r *SessionsRepository) Revoke(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET revoked_at=COALESCE(revoked_at, now()) WHERE id=$1 AND user_id=$2
+		AND expires_at > now() RETURNING id`, sessionsTable)
+	var revoked uuid.UUID
+	if err := sqlx.GetContext(ctx, tx, &revoked, query, id, userId); err != nil {
+		if errors.Is(sql.ErrNoRows, err) {
+			return ErrSessionDoesntExist
+		}
+		logrus.Errorf("error revoking session in db: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+func (r *SessionsRepository) RevokeAll(ctx context.Context, userId uuid.UUID) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET revoked_at=now() WHERE user_id=$1 AND revoked_at IS NULL`,
+		sessionsTable)
+	if _, err := tx.ExecContext(ctx, query, userId); err != nil {
+		logrus.Errorf("error revoking sessions in db by user_id: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+func (r *SessionsRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET last_seen_at=now() WHERE id=$1`, sessionsTable)
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		logrus.Errorf("error updating session last seen time in db: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type TwoFactorRepository struct {
+	db        *sqlx.DB
+	ctxGetter transactions.CtxGetterInterface
+}
+
+func NewTwoFactorRepository(db *sqlx.DB, ctxGetter transactions.CtxGetterInterface) *TwoFactorRepository {
+	return &TwoFactorRepository{
+		db:        db,
+		ctxGetter: ctxGetter,
+	}
+}
+
+func (r *TwoFactorRepository) Get(ctx context.Context, userId uuid.UUID) (domain.TwoFactor, error) {
+	var twoFactor domain.TwoFactor
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`SELECT user_id, secret, enabled FROM %s WHERE user_id=$1`, twoFactorTable)
+	if err := sqlx.GetContext(ctx, tx, &twoFactor, query, userId); err != nil {
+		if errors.Is(sql.ErrNoRows, err) {
+			return twoFactor, ErrTwoFactorNotFound
+		}
+		logrus.Errorf("error select two factor from db by user_id: %s", err)
+		return twoFactor, ErrInternal
+	}
+
+	return twoFactor, nil
+}
+
+// Save stores new not yet enabled secret replacing the previous one.
+func (r *TwoFactorRepository) Save(ctx context.Context, userId uuid.UUID, secret string) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, secret, enabled) VALUES ($1, $2, false)
+		ON CONFLICT (user_id) DO UPDATE SET secret=EXCLUDED.secret, enabled=false`, twoFactorTable)
+	if _, err := tx.ExecContext(ctx, query, userId, secret); err != nil {
+		logrus.Errorf("error insert two factor into db: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) Enable(ctx context.Context, userId uuid.UUID) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET enabled=true WHERE user_id=$1`, twoFactorTable)
+	if _, err := tx.ExecContext(ctx, query, userId); err != nil {
+		logrus.Errorf("error update two factor into db by user_id: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) Delete(ctx context.Context, userId uuid.UUID) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE user_id=$1`, recoveryCodesTable)
+	if _, err := tx.ExecContext(ctx, query, userId); err != nil {
+		logrus.Errorf("error delete recovery codes from db by user_id: %s", err)
+		return ErrInternal
+	}
+	query = fmt.Sprintf(`DELETE FROM %s WHERE user_id=$1`, twoFactorTable)
+	if _, err := tx.ExecContext(ctx, query, userId); err != nil {
+		logrus.Errorf("error delete two factor from db by user_id: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// SetRecoveryCodes replaces all recovery codes of the user with the given hashes.
+func (r *TwoFactorRepository) SetRecoveryCodes(ctx context.Context, userId uuid.UUID,
+	hashes []string) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE user_id=$1`, recoveryCodesTable)
+	if _, err := tx.ExecContext(ctx, query, userId); err != nil {
+		logrus.Errorf("error delete recovery codes from db by user_id: %s", err)
+		return ErrInternal
+	}
+	query = fmt.Sprintf(`INSERT INTO %s (id, user_id, hash_code) VALUES
+		((SELECT gen_random_uuid()), $1, $2)`, recoveryCodesTable)
+	for _, hash := range hashes {
+		if _, err := tx.ExecContext(ctx, query, userId, hash); err != nil {
+			logrus.Errorf("error insert recovery code into db: %s", err)
+			return ErrInternal
+		}
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks unused recovery code as used.
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userId uuid.UUID,
+	hash string) error {
+	var id uuid.UUID
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET used=true WHERE user_id=$1 AND hash_code=$2 AND NOT used
+		RETURNING id`, recoveryCodesTable)
+	row := tx.QueryRowxContext(ctx, query, userId, hash)
+	if err := row.Scan(&id); err != nil {
+		if errors.Is(sql.ErrNoRows, err) {
+			return ErrRecoveryCodeNotFound
+		}
+		logrus.Errorf("error update recovery code into db: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
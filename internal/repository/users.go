@@ -112,6 +112,9 @@ func (r *UsersRepository) Update(ctx context.Context, id uuid.UUID,
 	if data.Verified != nil {
 		addField("verified", *data.Verified)
 	}
+	if data.Role != nil {
+		addField("role", *data.Role)
+	}
 	values = append(values, id)
 
 	querySet := strings.Join(names, ", ")
@@ -125,3 +128,39 @@ func (r *UsersRepository) Update(ctx context.Context, id uuid.UUID,
 
 	return user, nil
 }
+
+// Search finds users whose email, surname or name contains query.
+func (r *UsersRepository) Search(ctx context.Context, query string, limit int,
+	offset int) ([]domain.User, error) {
+	users := []domain.User{}
+
+	sqlQuery := fmt.Sprintf(`SELECT * FROM %s u WHERE email ILIKE $1 OR surname ILIKE $1 OR name ILIKE $1
+		ORDER BY email LIMIT $2 OFFSET $3`, usersTable)
+	pattern := "%" + escapeLike(query) + "%"
+	if err := r.db.SelectContext(ctx, &users, sqlQuery, pattern, limit, offset); err != nil {
+		logrus.Errorf("error searching users in db: %s", err)
+		return users, ErrInternal
+	}
+
+	return users, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// Erase pseudonymizes personal data of the user. The row is kept because
+// financial records refer to it.
+func (r *UsersRepository) Erase(ctx context.Context, id uuid.UUID) error {
+	tx := r.ctxGetter.TrOrDb(ctx, r.db)
+
+	query := fmt.Sprintf(`UPDATE %s SET surname='', name='', patronyc='', email=$2, hash_password='',
+		verified=false, role=$3, erased_at=now() WHERE id=$1`, usersTable)
+	email := fmt.Sprintf("erased-%s@invalid", id)
+	if _, err := tx.ExecContext(ctx, query, id, email, domain.RoleCustomer); err != nil {
+		logrus.Errorf("error erasing user in db: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
@@ -4,28 +4,49 @@ import (
 	"context"
 	"errors"
 
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/broker"
 	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
 	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
 	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
 
+// movementOperations are journaled operations which move money of the account.
+var movementOperations = []string{domain.JournalCashOut, domain.JournalDeposit, domain.JournalReversal}
+
 type AccountsService struct {
 	rdb                *redis.Client
 	usersRepo          repository.Users
 	accountsRepo       repository.Accounts
+	twoFactorRepo      repository.TwoFactor
+	recipientsRepo     repository.Recipients
+	journalRepo        repository.MachineJournal
 	transactionManager transactions.ManagerInterface
+	broker             broker.BrokerInterface
+	passkeys           *passkeyVerifier
+	cfg                StepUpConfig
 }
 
 func NewAccountsService(rdb *redis.Client, usersRepo repository.Users,
-	accountsRepo repository.Accounts, transactionManager transactions.ManagerInterface) *AccountsService {
+	accountsRepo repository.Accounts, twoFactorRepo repository.TwoFactor,
+	recipientsRepo repository.Recipients, journalRepo repository.MachineJournal,
+	transactionManager transactions.ManagerInterface,
+	broker broker.BrokerInterface, passkeysRepo repository.Passkeys, webAuthn *webauthn.WebAuthn,
+	cfg StepUpConfig) *AccountsService {
 	return &AccountsService{
 		rdb:                rdb,
 		usersRepo:          usersRepo,
 		accountsRepo:       accountsRepo,
+		twoFactorRepo:      twoFactorRepo,
+		recipientsRepo:     recipientsRepo,
+		journalRepo:        journalRepo,
 		transactionManager: transactionManager,
+		broker:             broker,
+		passkeys:           newPasskeyVerifier(webAuthn, rdb, passkeysRepo, cfg.OperationTTL),
+		cfg:                cfg,
 	}
 }
 
@@ -104,6 +125,23 @@ func (s *AccountsService) Get(ctx context.Context, userId uuid.UUID, id uuid.UUI
 	return s.get(ctx, userId, id)
 }
 
+// GetMovements returns cash operations made on the account at machines, newest
+// first.
+func (s *AccountsService) GetMovements(ctx context.Context, userId uuid.UUID, id uuid.UUID, limit int,
+	offset int) ([]domain.JournalEntry, error) {
+	if _, err := s.get(ctx, userId, id); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.journalRepo.GetByAccount(ctx, id, movementOperations, limit, offset)
+	if err != nil {
+		logrus.Errorf("error getting movements from repo: %s", err)
+		return nil, ErrInternal
+	}
+
+	return entries, nil
+}
+
 func (s *AccountsService) Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
 	_, err := s.get(ctx, userId, id)
 	if err != nil {
@@ -118,48 +156,103 @@ func (s *AccountsService) Delete(ctx context.Context, userId uuid.UUID, id uuid.
 	return nil
 }
 
+// Transfer moves money between accounts of the user. Large transfers and transfers to
+// a new recipient aren't executed until confirmed by ConfirmTransfer.
 func (s *AccountsService) Transfer(ctx context.Context, userId uuid.UUID, id uuid.UUID,
-	to uuid.UUID, amount int) error {
+	to uuid.UUID, amount int) (domain.TransferResult, error) {
+	var result domain.TransferResult
+
+	account, accountTo, err := s.checkTransfer(ctx, userId, id, to, amount)
+	if err != nil {
+		return result, err
+	}
+
+	user, err := s.usersRepo.Get(ctx, userId)
+	if err != nil {
+		logrus.Errorf("error getting user from repo when transfering: %s", err)
+		if errors.Is(repository.ErrUserNotFound, err) {
+			return result, ErrUserNotFound
+		}
+		return result, ErrInternal
+	}
+
+	required, err := s.stepUpRequired(ctx, user, to, amount)
+	if err != nil {
+		return result, err
+	}
+	if required {
+		pending, err := s.createPendingTransfer(ctx, user, id, to, amount)
+		if err != nil {
+			return result, err
+		}
+		result.Pending = &pending
+		return result, nil
+	}
+
+	return result, s.transfer(ctx, userId, account, accountTo, amount)
+}
+
+func (s *AccountsService) checkTransfer(ctx context.Context, userId uuid.UUID, id uuid.UUID,
+	to uuid.UUID, amount int) (domain.Account, domain.Account, error) {
 	account, err := s.get(ctx, userId, id)
 	if err != nil {
-		return err
+		return account, domain.Account{}, err
+	}
+
+	if account.Frozen {
+		return account, domain.Account{}, ErrAccountFrozen
 	}
 
 	if account.Money < amount {
 		logrus.Errorf("insufficient funds in the account %s to transfer amount %d", id, account.Money)
-		return ErrInsufficientFunds
+		return account, domain.Account{}, ErrInsufficientFunds
 	}
 
 	accountTo, err := s.get(ctx, userId, to)
 	if err != nil {
 		logrus.Errorf("error get accountTo when transfering (service method): %s", err)
 		if errors.Is(repository.ErrAccountNotFound, err) {
-			return ErrAccountNotFound
+			return account, accountTo, ErrAccountNotFound
 		}
-		return ErrInternal
+		return account, accountTo, ErrInternal
+	}
+	if accountTo.Frozen {
+		return account, accountTo, ErrAccountFrozen
 	}
 
-	err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
-		newMoneyFrom := account.Money - amount
-		newMoneyTo := accountTo.Money + amount
+	return account, accountTo, nil
+}
 
-		_, err := s.accountsRepo.Update(ctx, id, domain.AccountUpdate{
-			Money: &newMoneyFrom,
-		})
-		if err != nil {
+func (s *AccountsService) transfer(ctx context.Context, userId uuid.UUID, account domain.Account,
+	accountTo domain.Account, amount int) error {
+	err := s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		debit := func() error {
+			_, err := s.accountsRepo.Debit(ctx, account.Id, amount)
 			return err
 		}
-		_, err = s.accountsRepo.Update(ctx, to, domain.AccountUpdate{
-			Money: &newMoneyTo,
-		})
-		if err != nil {
+		credit := func() error {
+			_, err := s.accountsRepo.Credit(ctx, accountTo.Id, amount)
 			return err
 		}
+		// accounts are always locked in the same order, so opposite transfers
+		// between two accounts don't deadlock
+		steps := []func() error{debit, credit}
+		if accountTo.Id.String() < account.Id.String() {
+			steps = []func() error{credit, debit}
+		}
+		for _, step := range steps {
+			if err := step(); err != nil {
+				return err
+			}
+		}
 
-		return nil
+		return s.recipientsRepo.Add(ctx, userId, accountTo.Id)
 	})
 	if err != nil {
 		logrus.Errorf("error transfering transaction in service transfer method: %s", err)
+		if errors.Is(repository.ErrInsufficientFunds, err) {
+			return ErrInsufficientFunds
+		}
 		return ErrInternal
 	}
 
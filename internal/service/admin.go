@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/broker"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/tokens"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	adminActionSearchUsers        = "users.search"
+	adminActionViewAccounts       = "accounts.view"
+	adminActionFreezeAccount      = "account.freeze"
+	adminActionUnfreezeAccount    = "account.unfreeze"
+	adminActionResendVerification = "user.resend_verification"
+	adminActionSetRole            = "user.set_role"
+	adminActionCreateMachine      = "machine.create"
+	adminActionUpdateMachine      = "machine.update"
+	adminActionDecommission       = "machine.decommission"
+	adminActionResetMachineToken  = "machine.reset_token"
+	adminActionReplenishCassettes = "machine.replenish"
+	adminActionCountCassettes     = "machine.count"
+	adminActionSettleMachine      = "machine.settle"
+
+	adminTargetUser    = "user"
+	adminTargetAccount = "account"
+	adminTargetMachine = "machine"
+
+	searchUsersMaxLimit = 100
+	machinesMaxLimit    = 100
+)
+
+type AdminService struct {
+	usersRepo          repository.Users
+	accountsRepo       repository.Accounts
+	adminActionsRepo   repository.AdminActions
+	sessionsRepo       repository.Sessions
+	machinesRepo       repository.Machines
+	cassettesRepo      repository.Cassettes
+	healthRepo         repository.MachineHealth
+	journalRepo        repository.MachineJournal
+	settlementsRepo    repository.MachineSettlements
+	rdb                *redis.Client
+	tokenManager       tokens.TokenManagerInterface
+	transactionManager transactions.ManagerInterface
+	broker             broker.BrokerInterface
+}
+
+func NewAdminService(usersRepo repository.Users, accountsRepo repository.Accounts,
+	adminActionsRepo repository.AdminActions, sessionsRepo repository.Sessions,
+	machinesRepo repository.Machines, cassettesRepo repository.Cassettes,
+	healthRepo repository.MachineHealth, journalRepo repository.MachineJournal,
+	settlementsRepo repository.MachineSettlements, rdb *redis.Client, tokenManager tokens.TokenManagerInterface,
+	transactionManager transactions.ManagerInterface, broker broker.BrokerInterface) *AdminService {
+	return &AdminService{
+		usersRepo:          usersRepo,
+		accountsRepo:       accountsRepo,
+		adminActionsRepo:   adminActionsRepo,
+		sessionsRepo:       sessionsRepo,
+		machinesRepo:       machinesRepo,
+		cassettesRepo:      cassettesRepo,
+		healthRepo:         healthRepo,
+		journalRepo:        journalRepo,
+		settlementsRepo:    settlementsRepo,
+		rdb:                rdb,
+		tokenManager:       tokenManager,
+		transactionManager: transactionManager,
+		broker:             broker,
+	}
+}
+
+func (s *AdminService) record(ctx context.Context, actorId uuid.UUID, action string,
+	targetType string, targetId string, details string) error {
+	err := s.adminActionsRepo.Create(ctx, domain.AdminAction{
+		ActorId:    actorId,
+		Action:     action,
+		TargetType: targetType,
+		TargetId:   targetId,
+		Details:    details,
+	})
+	if err != nil {
+		logrus.Errorf("error recording admin action %s into repo: %s", action, err)
+		return ErrInternal
+	}
+	return nil
+}
+
+func (s *AdminService) getUser(ctx context.Context, id uuid.UUID) (domain.User, error) {
+	user, err := s.usersRepo.Get(ctx, id)
+	if err != nil {
+		logrus.Errorf("error getting user from repo in admin service: %s", err)
+		if errors.Is(repository.ErrUserNotFound, err) {
+			return user, ErrUserNotFound
+		}
+		return user, ErrInternal
+	}
+	return user, nil
+}
+
+func (s *AdminService) SearchUsers(ctx context.Context, actorId uuid.UUID, query string, limit int,
+	offset int) ([]domain.User, error) {
+	if limit <= 0 || limit > searchUsersMaxLimit {
+		limit = searchUsersMaxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	// viewing personal data is audited as well
+	err := s.record(ctx, actorId, adminActionSearchUsers, adminTargetUser, "",
+		fmt.Sprintf("query=%q limit=%d offset=%d", query, limit, offset))
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.usersRepo.Search(ctx, query, limit, offset)
+	if err != nil {
+		logrus.Errorf("error searching users in repo: %s", err)
+		return nil, ErrInternal
+	}
+
+	return users, nil
+}
+
+func (s *AdminService) GetUserAccounts(ctx context.Context, actorId uuid.UUID,
+	userId uuid.UUID) ([]domain.Account, error) {
+	if _, err := s.getUser(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	err := s.record(ctx, actorId, adminActionViewAccounts, adminTargetUser, userId.String(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.accountsRepo.GetAll(ctx, userId)
+	if err != nil {
+		logrus.Errorf("error getting accounts from repo in admin service: %s", err)
+		return nil, ErrInternal
+	}
+
+	return accounts, nil
+}
+
+func (s *AdminService) setFrozen(ctx context.Context, actorId uuid.UUID, accountId uuid.UUID,
+	frozen bool) error {
+	action := adminActionUnfreezeAccount
+	if frozen {
+		action = adminActionFreezeAccount
+	}
+
+	err := s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.accountsRepo.Get(ctx, accountId); err != nil {
+			return err
+		}
+		_, err := s.accountsRepo.Update(ctx, accountId, domain.AccountUpdate{
+			Frozen: &frozen,
+		})
+		if err != nil {
+			return err
+		}
+		return s.record(ctx, actorId, action, adminTargetAccount, accountId.String(), "")
+	})
+	if err != nil {
+		logrus.Errorf("error setting account frozen in transaction: %s", err)
+		if errors.Is(repository.ErrAccountNotFound, err) {
+			return ErrAccountNotFound
+		}
+		return ErrInternal
+	}
+
+	return nil
+}
+
+func (s *AdminService) FreezeAccount(ctx context.Context, actorId uuid.UUID, accountId uuid.UUID) error {
+	return s.setFrozen(ctx, actorId, accountId, true)
+}
+
+func (s *AdminService) UnfreezeAccount(ctx context.Context, actorId uuid.UUID, accountId uuid.UUID) error {
+	return s.setFrozen(ctx, actorId, accountId, false)
+}
+
+func (s *AdminService) ResendVerification(ctx context.Context, actorId uuid.UUID, userId uuid.UUID) error {
+	user, err := s.getUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if user.Verified {
+		return ErrEmailAlreadyVerified
+	}
+
+	err = s.record(ctx, actorId, adminActionResendVerification, adminTargetUser, userId.String(), "")
+	if err != nil {
+		return err
+	}
+
+	if err := s.broker.WriteVerificationTask(ctx, user.Email); err != nil {
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// SetRole changes role of the user and revokes the user's sessions, so tokens
+// with the previous role stop working.
+func (s *AdminService) SetRole(ctx context.Context, actorId uuid.UUID, userId uuid.UUID, role string) error {
+	if !domain.ValidateRole(role) {
+		return ErrInvalidRole
+	}
+
+	user, err := s.getUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if user.Role == role {
+		return nil
+	}
+
+	err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		_, err := s.usersRepo.Update(ctx, userId, domain.UserUpdate{
+			Role: &role,
+		})
+		if err != nil {
+			return err
+		}
+		return s.record(ctx, actorId, adminActionSetRole, adminTargetUser, userId.String(),
+			fmt.Sprintf("%s -> %s", user.Role, role))
+	})
+	if err != nil {
+		logrus.Errorf("error setting user role in transaction: %s", err)
+		return ErrInternal
+	}
+
+	return revokeSessions(ctx, s.rdb, s.sessionsRepo, userId)
+}
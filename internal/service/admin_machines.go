@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+func validateMachine(machine domain.Machine) bool {
+	return domain.ValidateMachineName(machine.Name) &&
+		domain.ValidateMachineAddress(machine.Address) &&
+		domain.ValidateCoordinates(machine.Latitude, machine.Longitude) &&
+		domain.ValidateMachineOperations(machine.Operations) &&
+		domain.ValidateCurrency(machine.Currency) &&
+		domain.ValidateMachineStatus(machine.Status)
+}
+
+func (s *AdminService) getMachine(ctx context.Context, id uuid.UUID) (domain.Machine, error) {
+	machine, err := s.machinesRepo.Get(ctx, id)
+	if err != nil {
+		logrus.Errorf("error getting machine from repo in admin service: %s", err)
+		if errors.Is(repository.ErrMachineNotFound, err) {
+			return machine, ErrMachineNotFound
+		}
+		return machine, ErrInternal
+	}
+	return machine, nil
+}
+
+// CreateMachine registers ATM and returns its first token. The token is the
+// credential the machine is provisioned with.
+func (s *AdminService) CreateMachine(ctx context.Context, actorId uuid.UUID,
+	machine domain.Machine) (domain.Machine, string, error) {
+	if machine.Status == "" {
+		machine.Status = domain.MachineStatusActive
+	}
+	if !validateMachine(machine) {
+		return machine, "", ErrInvalidMachine
+	}
+
+	tokenId := uuid.New()
+	machine.TokenId = uuid.NullUUID{UUID: tokenId, Valid: true}
+
+	var created domain.Machine
+	err := s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.machinesRepo.Create(ctx, machine)
+		if err != nil {
+			return err
+		}
+		return s.record(ctx, actorId, adminActionCreateMachine, adminTargetMachine, created.Id.String(),
+			fmt.Sprintf("name=%q", created.Name))
+	})
+	if err != nil {
+		logrus.Errorf("error creating machine in transaction: %s", err)
+		return created, "", ErrInternal
+	}
+
+	token, err := s.tokenManager.CreateMachineToken(created.Id, tokenId)
+	if err != nil {
+		logrus.Errorf("error creating machine token: %s", err)
+		return created, "", ErrInternal
+	}
+
+	return created, token, nil
+}
+
+func (s *AdminService) GetMachines(ctx context.Context, status string, limit int,
+	offset int) ([]domain.Machine, error) {
+	if limit <= 0 || limit > machinesMaxLimit {
+		limit = machinesMaxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	machines, err := s.machinesRepo.GetAll(ctx, status, limit, offset)
+	if err != nil {
+		logrus.Errorf("error getting machines from repo: %s", err)
+		return nil, ErrInternal
+	}
+
+	return machines, nil
+}
+
+func (s *AdminService) UpdateMachine(ctx context.Context, actorId uuid.UUID, id uuid.UUID,
+	data domain.MachineUpdate) (domain.Machine, error) {
+	machine, err := s.getMachine(ctx, id)
+	if err != nil {
+		return machine, err
+	}
+	if machine.Status == domain.MachineStatusDecommissioned {
+		return machine, ErrMachineDecommissioned
+	}
+
+	// the update is checked as a whole, coordinates are valid only in pairs
+	updated := machine
+	if data.Name != nil {
+		updated.Name = *data.Name
+	}
+	if data.Address != nil {
+		updated.Address = *data.Address
+	}
+	if data.Latitude != nil {
+		updated.Latitude = *data.Latitude
+	}
+	if data.Longitude != nil {
+		updated.Longitude = *data.Longitude
+	}
+	if data.Operations != nil {
+		updated.Operations = *data.Operations
+	}
+	if data.Currency != nil {
+		updated.Currency = *data.Currency
+	}
+	if data.Status != nil {
+		updated.Status = *data.Status
+	}
+	if !validateMachine(updated) {
+		return machine, ErrInvalidMachine
+	}
+
+	err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		machine, err = s.machinesRepo.Update(ctx, id, data)
+		if err != nil {
+			return err
+		}
+		return s.record(ctx, actorId, adminActionUpdateMachine, adminTargetMachine, id.String(),
+			fmt.Sprintf("status=%s", machine.Status))
+	})
+	if err != nil {
+		logrus.Errorf("error updating machine in transaction: %s", err)
+		// the machine was found above, so it has been decommissioned since
+		if errors.Is(repository.ErrMachineNotFound, err) {
+			return machine, ErrMachineDecommissioned
+		}
+		return machine, ErrInternal
+	}
+
+	return machine, nil
+}
+
+// DecommissionMachine retires the machine. Its token is revoked and the machine
+// can't be brought back.
+func (s *AdminService) DecommissionMachine(ctx context.Context, actorId uuid.UUID, id uuid.UUID) error {
+	machine, err := s.getMachine(ctx, id)
+	if err != nil {
+		return err
+	}
+	if machine.Status == domain.MachineStatusDecommissioned {
+		return ErrMachineDecommissioned
+	}
+
+	err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.machinesRepo.Decommission(ctx, id); err != nil {
+			return err
+		}
+		return s.record(ctx, actorId, adminActionDecommission, adminTargetMachine, id.String(), "")
+	})
+	if err != nil {
+		logrus.Errorf("error decommissioning machine in transaction: %s", err)
+		if errors.Is(repository.ErrMachineNotFound, err) {
+			return ErrMachineNotFound
+		}
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// ResetMachineToken issues new token to the machine, e.g. when the previous one
+// leaked or was lost. The previous token is revoked.
+func (s *AdminService) ResetMachineToken(ctx context.Context, actorId uuid.UUID,
+	id uuid.UUID) (string, error) {
+	machine, err := s.getMachine(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if machine.Status == domain.MachineStatusDecommissioned {
+		return "", ErrMachineDecommissioned
+	}
+
+	err = s.record(ctx, actorId, adminActionResetMachineToken, adminTargetMachine, id.String(), "")
+	if err != nil {
+		return "", err
+	}
+
+	return issueMachineToken(ctx, s.machinesRepo, s.tokenManager, id)
+}
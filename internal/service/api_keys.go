@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/tokens"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	apiKeyPrefix  = "bk_"
+	apiKeyMaxKeys = 10
+)
+
+type ApiKeysService struct {
+	apiKeysRepo  repository.ApiKeys
+	accountsRepo repository.Accounts
+}
+
+func NewApiKeysService(apiKeysRepo repository.ApiKeys, accountsRepo repository.Accounts) *ApiKeysService {
+	return &ApiKeysService{
+		apiKeysRepo:  apiKeysRepo,
+		accountsRepo: accountsRepo,
+	}
+}
+
+// CreateApiKey creates key with name, scopes, optional account and expiry of the
+// given key. The returned secret is shown only once.
+func (s *ApiKeysService) CreateApiKey(ctx context.Context, userId uuid.UUID,
+	key domain.ApiKey) (domain.ApiKey, string, error) {
+	if !domain.ValidateApiKeyName(key.Name) {
+		return key, "", ErrInvalidApiKeyName
+	}
+	if !domain.ValidateScopes(key.Scopes) {
+		return key, "", ErrInvalidScopes
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		return key, "", ErrInvalidExpiry
+	}
+
+	if key.AccountId.Valid {
+		account, err := s.accountsRepo.Get(ctx, key.AccountId.UUID)
+		if err != nil {
+			logrus.Errorf("error getting account from repo when creating api key: %s", err)
+			if errors.Is(repository.ErrAccountNotFound, err) {
+				return key, "", ErrAccountNotFound
+			}
+			return key, "", ErrInternal
+		}
+		if account.UserId != userId {
+			return key, "", ErrAccountNotFound
+		}
+	}
+
+	keys, err := s.apiKeysRepo.GetAll(ctx, userId)
+	if err != nil {
+		logrus.Errorf("error getting api keys from repo when creating: %s", err)
+		return key, "", ErrInternal
+	}
+	if len(keys) >= apiKeyMaxKeys {
+		return key, "", ErrTooManyApiKeys
+	}
+
+	random, err := tokens.GenerateRandomToken(32)
+	if err != nil {
+		logrus.Errorf("error generating api key: %s", err)
+		return key, "", ErrInternal
+	}
+	secret := apiKeyPrefix + random
+
+	key.UserId = userId
+	key.Hash = tokens.HashToken(secret)
+	created, err := s.apiKeysRepo.Create(ctx, key)
+	if err != nil {
+		logrus.Errorf("error creating api key into repo: %s", err)
+		return key, "", ErrInternal
+	}
+
+	return created, secret, nil
+}
+
+func (s *ApiKeysService) GetApiKeys(ctx context.Context, userId uuid.UUID) ([]domain.ApiKey, error) {
+	keys, err := s.apiKeysRepo.GetAll(ctx, userId)
+	if err != nil {
+		logrus.Errorf("error getting api keys from repo: %s", err)
+		return nil, ErrInternal
+	}
+	return keys, nil
+}
+
+func (s *ApiKeysService) DeleteApiKey(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	if err := s.apiKeysRepo.Delete(ctx, userId, id); err != nil {
+		logrus.Errorf("error deleting api key from repo: %s", err)
+		if errors.Is(repository.ErrApiKeyNotFound, err) {
+			return ErrApiKeyNotFound
+		}
+		return ErrInternal
+	}
+	return nil
+}
+
+// AuthenticateApiKey returns principal of the key owner. Scopes and account
+// restriction are checked by the caller.
+func (s *ApiKeysService) AuthenticateApiKey(ctx context.Context, secret string) (domain.Principal, error) {
+	var principal domain.Principal
+
+	key, err := s.apiKeysRepo.GetByHash(ctx, tokens.HashToken(secret))
+	if err != nil {
+		if errors.Is(repository.ErrApiKeyNotFound, err) {
+			return principal, ErrTokenInvalid
+		}
+		logrus.Errorf("error getting api key from repo when authenticating: %s", err)
+		return principal, ErrInternal
+	}
+	if key.Expired(time.Now()) {
+		return principal, ErrTokenExpired
+	}
+
+	// last used time is informational, so failure doesn't reject the request
+	if err := s.apiKeysRepo.Touch(ctx, key.Id); err != nil {
+		logrus.Errorf("error touching api key: %s", err)
+	}
+
+	principal.UserId = key.UserId
+	principal.ApiKey = &key
+
+	return principal, nil
+}
@@ -2,7 +2,11 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/IvanMeln1k/go-bank-app-bank/internal/broker"
 	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
@@ -10,30 +14,72 @@ import (
 	"github.com/IvanMeln1k/go-bank-app-bank/pkg/hasher"
 	"github.com/IvanMeln1k/go-bank-app-bank/pkg/tokens"
 	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	passwordResetTokenKey      = "password-reset:token:%s"
+	emailChangeTokenKey        = "email-change:token:%s"
+	passwordResetEmailLimitKey = "ratelimit:password-reset:email:%s"
+	passwordResetIpLimitKey    = "ratelimit:password-reset:ip:%s"
+	sessionsRevokedKey         = "sessions:revoked:%s"
+	sessionRevokedKey          = "session:revoked:%s"
+	sessionSeenKey             = "session:seen:%s"
+)
+
+type AuthConfig struct {
+	EmailChangeTTL        time.Duration
+	PasswordResetTTL      time.Duration
+	PasswordResetLimit    int
+	PasswordResetWindow   time.Duration
+	TwoFactorIssuer       string
+	TwoFactorChallengeTTL time.Duration
+	RecoveryCodesCount    int
+	SignInGuard           SignInGuardConfig
+	MagicLinkTTL          time.Duration
+	MagicLinkLimit        int
+	MagicLinkWindow       time.Duration
+	PasskeyCeremonyTTL    time.Duration
+	// SessionTTL is lifetime of the session, equal to access token TTL
+	SessionTTL time.Duration
+}
+
 type AuthService struct {
 	usersRepo          repository.Users
+	twoFactorRepo      repository.TwoFactor
+	sessionsRepo       repository.Sessions
+	passkeysRepo       repository.Passkeys
 	rdb                *redis.Client
 	tokenManager       tokens.TokenManagerInterface
 	hasher             hasher.HasherInterface
 	transactionManager transactions.ManagerInterface
 	broker             broker.BrokerInterface
+	cfg                AuthConfig
+	signInGuard        *signInGuard
+	passkeys           *passkeyVerifier
 }
 
-func NewAuthService(usersRepo repository.Users, rdb *redis.Client,
+func NewAuthService(usersRepo repository.Users, twoFactorRepo repository.TwoFactor,
+	sessionsRepo repository.Sessions, passkeysRepo repository.Passkeys, rdb *redis.Client,
 	tokenManager tokens.TokenManagerInterface, hasher hasher.HasherInterface,
-	transactionManager transactions.ManagerInterface, broker broker.BrokerInterface) *AuthService {
+	transactionManager transactions.ManagerInterface, broker broker.BrokerInterface,
+	webAuthn *webauthn.WebAuthn, cfg AuthConfig) *AuthService {
 	return &AuthService{
 		usersRepo:          usersRepo,
+		twoFactorRepo:      twoFactorRepo,
+		sessionsRepo:       sessionsRepo,
+		passkeysRepo:       passkeysRepo,
 		rdb:                rdb,
 		tokenManager:       tokenManager,
 		hasher:             hasher,
 		transactionManager: transactionManager,
 		broker:             broker,
+		cfg:                cfg,
+		signInGuard:        newSignInGuard(rdb, cfg.SignInGuard),
+		passkeys:           newPasskeyVerifier(webAuthn, rdb, passkeysRepo, cfg.PasskeyCeremonyTTL),
 	}
 }
 
@@ -64,8 +110,26 @@ func (s *AuthService) SendEmailVerificationMessage(ctx context.Context, id uuid.
 	return s.writeTaskSendEmailVerificationMessage(ctx, user.Email)
 }
 
+func validateUser(user domain.User) error {
+	if !domain.ValidateName(user.Surname) || !domain.ValidateName(user.Name) ||
+		!domain.ValidatePatronyc(user.Patronyc) {
+		return ErrInvalidName
+	}
+	if !domain.ValidateEmail(user.Email) {
+		return ErrInvalidEmail
+	}
+	if !domain.ValidatePassword(user.Password) {
+		return ErrInvalidPassword
+	}
+	return nil
+}
+
 func (s *AuthService) SignUp(ctx context.Context, user domain.User) (uuid.UUID, error) {
 	var id uuid.UUID
+	if err := validateUser(user); err != nil {
+		return id, err
+	}
+
 	_, err := s.usersRepo.GetByEmail(ctx, user.Email)
 	if err != nil && !errors.Is(repository.ErrUserNotFound, err) {
 		logrus.Errorf("error getting user from users repo when signup: %s", err)
@@ -76,7 +140,11 @@ func (s *AuthService) SignUp(ctx context.Context, user domain.User) (uuid.UUID,
 		return id, ErrEmailAlreadyInUse
 	}
 
-	user.Password = s.hasher.Hash(user.Password)
+	user.Password, err = s.hasher.Hash(user.Password)
+	if err != nil {
+		logrus.Errorf("error hashing password when signing up: %s", err)
+		return id, ErrInternal
+	}
 	id, err = s.usersRepo.Create(ctx, user)
 	if err != nil {
 		logrus.Errorf("error creating user into repository when signing up: %s", err)
@@ -88,29 +156,65 @@ func (s *AuthService) SignUp(ctx context.Context, user domain.User) (uuid.UUID,
 	return id, nil
 }
 
-func (s *AuthService) SignIn(ctx context.Context, email string, password string) (string, error) {
+func (s *AuthService) SignIn(ctx context.Context, email string, password string,
+	device domain.Device) (domain.SignInResult, error) {
+	var result domain.SignInResult
+
+	if err := s.signInGuard.check(ctx, email, device.Ip); err != nil {
+		return result, err
+	}
+
 	user, err := s.usersRepo.GetByEmail(ctx, email)
-	if err != nil {
+	if err != nil && !errors.Is(repository.ErrUserNotFound, err) {
 		logrus.Errorf("error getting user from repo by email when signing in: %s", err)
-		if errors.Is(repository.ErrUserNotFound, err) {
-			return "", ErrInvalidEmailOrPassword
+		return result, ErrInternal
+	}
+
+	if err != nil || !s.hasher.Check(password, user.Password) {
+		logrus.Errorf("invalid email or password when signing in")
+		locked, err := s.signInGuard.fail(ctx, email, device.Ip)
+		if err != nil {
+			return result, err
 		}
-		return "", ErrInternal
+		if locked && user.Email != "" {
+			_ = s.broker.WriteSignInLockoutTask(ctx, user.Email, device.Ip)
+		}
+		return result, ErrInvalidEmailOrPassword
 	}
 
-	validPassword := s.hasher.Check(password, user.Password)
-	if !validPassword {
-		logrus.Errorf("invalid password when signing in")
-		return "", ErrInvalidEmailOrPassword
+	s.signInGuard.reset(ctx, email)
+
+	if s.hasher.NeedsRehash(user.Password) {
+		s.rehashPassword(ctx, user.Id, password)
 	}
 
-	accessToken, err := s.tokenManager.CreateAccessToken(user.Id)
-	if err != nil {
-		logrus.Errorf("error creating access token when signing in: %s", err)
-		return "", ErrInternal
+	twoFactor, err := s.getTwoFactor(ctx, user.Id)
+	if err != nil && !errors.Is(ErrTwoFactorNotEnabled, err) {
+		return result, err
+	}
+	if err == nil && twoFactor.Enabled {
+		result.TwoFactorToken, err = s.createTwoFactorChallenge(ctx, user.Id)
+		return result, err
 	}
 
-	return accessToken, nil
+	result.AccessToken, err = s.startSession(ctx, user, device)
+	return result, err
+}
+
+// rehashPassword upgrades stored hash to the current algorithm and cost parameters.
+// Errors are only logged: the user has already proved the password.
+func (s *AuthService) rehashPassword(ctx context.Context, id uuid.UUID, password string) {
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		logrus.Errorf("error hashing password when rehashing: %s", err)
+		return
+	}
+	_, err = s.usersRepo.Update(ctx, id, domain.UserUpdate{
+		Password: &hash,
+	})
+	if err != nil {
+		logrus.Errorf("error updating user password into repo when rehashing: %s", err)
+	}
 }
 
 func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
@@ -164,3 +268,336 @@ func (s *AuthService) Get(ctx context.Context, id uuid.UUID) (domain.User, error
 
 	return user, nil
 }
+
+func (s *AuthService) Authenticate(ctx context.Context, accessToken string) (domain.Principal, error) {
+	var principal domain.Principal
+
+	claims, err := s.tokenManager.ParseAccessToken(accessToken)
+	if err != nil {
+		logrus.Errorf("error parsing access token when authenticating: %s", err)
+		if errors.Is(tokens.ErrTokenExpired, err) {
+			return principal, ErrTokenExpired
+		}
+		if errors.Is(tokens.ErrTokenInvalid, err) {
+			return principal, ErrTokenInvalid
+		}
+		return principal, ErrInternal
+	}
+	// machine and email tokens are signed with the same keys, legacy HS256
+	// access tokens get the type when parsed
+	if claims.Type != tokens.AccessTokenType {
+		logrus.Errorf("error authenticating: token of type %q isn't access token", claims.Type)
+		return principal, ErrTokenInvalid
+	}
+
+	revokedAt, err := s.rdb.Get(ctx, fmt.Sprintf(sessionsRevokedKey, claims.Id)).Int64()
+	if err != nil && !errors.Is(redis.Nil, err) {
+		logrus.Errorf("error getting sessions revocation time from redis: %s", err)
+		return principal, ErrInternal
+	}
+	// tokens issued before issue time in microseconds was introduced are
+	// revoked along with ones issued in the same second
+	issuedAt := claims.IssuedAtMicro
+	if issuedAt == 0 {
+		issuedAt = time.Unix(claims.IssuedAt, 0).UnixMicro()
+	}
+	if err == nil && issuedAt <= revokedAt {
+		return principal, ErrSessionRevoked
+	}
+
+	// tokens issued before sessions were introduced have no session
+	if claims.SessionId != uuid.Nil {
+		if err := s.checkSession(ctx, claims.SessionId); err != nil {
+			return principal, err
+		}
+	}
+
+	principal.UserId = claims.Id
+	principal.Role = claims.Role
+	principal.SessionId = claims.SessionId
+	// tokens issued before roles were introduced
+	if principal.Role == "" {
+		principal.Role = domain.RoleCustomer
+	}
+
+	return principal, nil
+}
+
+// revokeSessions invalidates all access tokens of the user issued before now.
+// Revocation time is kept in unix microseconds, so a token issued right after
+// it in the same second stays valid.
+func revokeSessions(ctx context.Context, rdb *redis.Client, sessionsRepo repository.Sessions,
+	id uuid.UUID) error {
+	err := rdb.Set(ctx, fmt.Sprintf(sessionsRevokedKey, id),
+		strconv.FormatInt(time.Now().UnixMicro(), 10), 0).Err()
+	if err != nil {
+		logrus.Errorf("error setting sessions revocation time into redis: %s", err)
+		return ErrInternal
+	}
+	if err := sessionsRepo.RevokeAll(ctx, id); err != nil {
+		logrus.Errorf("error revoking sessions in repo: %s", err)
+		return ErrInternal
+	}
+	return nil
+}
+
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email string, ip string) error {
+	limited, err := hitRateLimit(ctx, s.rdb, fmt.Sprintf(passwordResetIpLimitKey, ip),
+		s.cfg.PasswordResetLimit, s.cfg.PasswordResetWindow)
+	if err != nil {
+		logrus.Errorf("error checking password reset ip rate limit: %s", err)
+		return ErrInternal
+	}
+	if limited {
+		return ErrTooManyRequests
+	}
+	limited, err = hitRateLimit(ctx, s.rdb, fmt.Sprintf(passwordResetEmailLimitKey, email),
+		s.cfg.PasswordResetLimit, s.cfg.PasswordResetWindow)
+	if err != nil {
+		logrus.Errorf("error checking password reset email rate limit: %s", err)
+		return ErrInternal
+	}
+	if limited {
+		return ErrTooManyRequests
+	}
+
+	user, err := s.usersRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(repository.ErrUserNotFound, err) {
+			// the caller mustn't know whether the email is registered
+			return nil
+		}
+		logrus.Errorf("error getting user from repo by email when requesting password reset: %s", err)
+		return ErrInternal
+	}
+
+	token, err := tokens.GenerateRandomToken(32)
+	if err != nil {
+		logrus.Errorf("error generating password reset token: %s", err)
+		return ErrInternal
+	}
+	err = s.rdb.Set(ctx, fmt.Sprintf(passwordResetTokenKey, tokens.HashToken(token)),
+		user.Id.String(), s.cfg.PasswordResetTTL).Err()
+	if err != nil {
+		logrus.Errorf("error saving password reset token into redis: %s", err)
+		return ErrInternal
+	}
+
+	if err := s.broker.WritePasswordResetTask(ctx, user.Email, token); err != nil {
+		return ErrInternal
+	}
+
+	return nil
+}
+
+func (s *AuthService) ConfirmPasswordReset(ctx context.Context, token string, password string) error {
+	if !domain.ValidatePassword(password) {
+		return ErrInvalidPassword
+	}
+
+	// GetDel makes the token single-use
+	userId, err := s.rdb.GetDel(ctx, fmt.Sprintf(passwordResetTokenKey, tokens.HashToken(token))).Result()
+	if err != nil {
+		if errors.Is(redis.Nil, err) {
+			return ErrTokenInvalid
+		}
+		logrus.Errorf("error getting password reset token from redis: %s", err)
+		return ErrInternal
+	}
+	id, err := uuid.Parse(userId)
+	if err != nil {
+		logrus.Errorf("error parsing user id of password reset token: %s", err)
+		return ErrInternal
+	}
+	user, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.ErasedAt != nil {
+		return ErrTokenInvalid
+	}
+
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		logrus.Errorf("error hashing password when confirming password reset: %s", err)
+		return ErrInternal
+	}
+	_, err = s.usersRepo.Update(ctx, id, domain.UserUpdate{
+		Password: &hash,
+	})
+	if err != nil {
+		logrus.Errorf("error updating user password into repo when confirming password reset: %s", err)
+		return ErrInternal
+	}
+
+	return revokeSessions(ctx, s.rdb, s.sessionsRepo, id)
+}
+
+type emailChange struct {
+	UserId uuid.UUID `json:"userId"`
+	Email  string    `json:"email"`
+}
+
+func (s *AuthService) UpdateProfile(ctx context.Context, id uuid.UUID,
+	data domain.UserUpdate) (domain.User, error) {
+	if (data.Surname != nil && !domain.ValidateName(*data.Surname)) ||
+		(data.Name != nil && !domain.ValidateName(*data.Name)) ||
+		(data.Patronyc != nil && !domain.ValidatePatronyc(*data.Patronyc)) {
+		return domain.User{}, ErrInvalidName
+	}
+	if data.Email != nil && !domain.ValidateEmail(*data.Email) {
+		return domain.User{}, ErrInvalidEmail
+	}
+
+	user, err := s.Get(ctx, id)
+	if err != nil {
+		return user, err
+	}
+
+	if data.Email != nil && *data.Email != user.Email {
+		if err := s.requestEmailChange(ctx, user, *data.Email); err != nil {
+			return user, err
+		}
+	}
+
+	// email is changed only after the new address is confirmed
+	update := domain.UserUpdate{
+		Surname:  data.Surname,
+		Name:     data.Name,
+		Patronyc: data.Patronyc,
+	}
+	if !update.Validate() {
+		return user, nil
+	}
+
+	user, err = s.usersRepo.Update(ctx, id, update)
+	if err != nil {
+		logrus.Errorf("error updating user into repo when updating profile: %s", err)
+		return user, ErrInternal
+	}
+
+	return user, nil
+}
+
+func (s *AuthService) checkEmailFree(ctx context.Context, email string) error {
+	_, err := s.usersRepo.GetByEmail(ctx, email)
+	if err == nil {
+		return ErrEmailAlreadyInUse
+	}
+	if !errors.Is(repository.ErrUserNotFound, err) {
+		logrus.Errorf("error getting user from repo by email when checking email: %s", err)
+		return ErrInternal
+	}
+	return nil
+}
+
+func (s *AuthService) requestEmailChange(ctx context.Context, user domain.User, email string) error {
+	if err := s.checkEmailFree(ctx, email); err != nil {
+		return err
+	}
+
+	token, err := tokens.GenerateRandomToken(32)
+	if err != nil {
+		logrus.Errorf("error generating email change token: %s", err)
+		return ErrInternal
+	}
+	data, err := json.Marshal(emailChange{
+		UserId: user.Id,
+		Email:  email,
+	})
+	if err != nil {
+		logrus.Errorf("error marshaling email change: %s", err)
+		return ErrInternal
+	}
+	err = s.rdb.Set(ctx, fmt.Sprintf(emailChangeTokenKey, tokens.HashToken(token)), data,
+		s.cfg.EmailChangeTTL).Err()
+	if err != nil {
+		logrus.Errorf("error saving email change token into redis: %s", err)
+		return ErrInternal
+	}
+
+	if err := s.broker.WriteEmailChangeTask(ctx, email, token); err != nil {
+		return ErrInternal
+	}
+	if err := s.broker.WriteEmailChangeNoticeTask(ctx, user.Email, email); err != nil {
+		return ErrInternal
+	}
+
+	return nil
+}
+
+func (s *AuthService) ConfirmEmailChange(ctx context.Context, token string) error {
+	data, err := s.rdb.GetDel(ctx, fmt.Sprintf(emailChangeTokenKey, tokens.HashToken(token))).Bytes()
+	if err != nil {
+		if errors.Is(redis.Nil, err) {
+			return ErrTokenInvalid
+		}
+		logrus.Errorf("error getting email change token from redis: %s", err)
+		return ErrInternal
+	}
+	var change emailChange
+	if err := json.Unmarshal(data, &change); err != nil {
+		logrus.Errorf("error unmarshaling email change: %s", err)
+		return ErrInternal
+	}
+	user, err := s.Get(ctx, change.UserId)
+	if err != nil {
+		return err
+	}
+	if user.ErasedAt != nil {
+		return ErrTokenInvalid
+	}
+
+	// the address could be taken while the change was pending
+	if err := s.checkEmailFree(ctx, change.Email); err != nil {
+		return err
+	}
+
+	verified := true
+	_, err = s.usersRepo.Update(ctx, change.UserId, domain.UserUpdate{
+		Email:    &change.Email,
+		Verified: &verified,
+	})
+	if err != nil {
+		logrus.Errorf("error updating user email into repo when confirming email change: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// ChangePassword sets new password, revokes all sessions and returns new access token
+// for the current one.
+func (s *AuthService) ChangePassword(ctx context.Context, id uuid.UUID, currentPassword string,
+	newPassword string, device domain.Device) (string, error) {
+	if !domain.ValidatePassword(newPassword) {
+		return "", ErrInvalidPassword
+	}
+
+	user, err := s.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if !s.hasher.Check(currentPassword, user.Password) {
+		return "", ErrWrongPassword
+	}
+
+	hash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		logrus.Errorf("error hashing password when changing password: %s", err)
+		return "", ErrInternal
+	}
+	_, err = s.usersRepo.Update(ctx, id, domain.UserUpdate{
+		Password: &hash,
+	})
+	if err != nil {
+		logrus.Errorf("error updating user password into repo when changing password: %s", err)
+		return "", ErrInternal
+	}
+
+	if err := revokeSessions(ctx, s.rdb, s.sessionsRepo, id); err != nil {
+		return "", err
+	}
+
+	return s.startSession(ctx, user, device)
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/hasher"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// panGenerationAttempts bounds retries when generated PAN is already taken.
+const panGenerationAttempts = 3
+
+type CardsConfig struct {
+	// Bin is issuer identification number, the first digits of every PAN
+	Bin            string
+	ValidityMonths int
+	PinAttempts    int
+	MaxPerAccount  int
+	// PanKey is secret key of PAN hashes, PANs are too short to be stored
+	// under plain hash
+	PanKey string
+}
+
+type CardsService struct {
+	cardsRepo    repository.Cards
+	accountsRepo repository.Accounts
+	usersRepo    repository.Users
+	hasher       hasher.HasherInterface
+	cfg          CardsConfig
+}
+
+func NewCardsService(cardsRepo repository.Cards, accountsRepo repository.Accounts,
+	usersRepo repository.Users, hasher hasher.HasherInterface, cfg CardsConfig) *CardsService {
+	return &CardsService{
+		cardsRepo:    cardsRepo,
+		accountsRepo: accountsRepo,
+		usersRepo:    usersRepo,
+		hasher:       hasher,
+		cfg:          cfg,
+	}
+}
+
+func (s *CardsService) hashPan(pan string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.PanKey))
+	mac.Write([]byte(pan))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// generatePan returns random PAN starting with BIN and ending with Luhn check digit.
+func (s *CardsService) generatePan() (string, error) {
+	pan := []byte(s.cfg.Bin)
+	for len(pan) < domain.PanLength-1 {
+		digit, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		pan = append(pan, byte('0'+digit.Int64()))
+	}
+	return string(append(pan, domain.LuhnCheckDigit(string(pan)))), nil
+}
+
+func (s *CardsService) getCard(ctx context.Context, userId uuid.UUID, id uuid.UUID) (domain.Card, error) {
+	card, err := s.cardsRepo.Get(ctx, id)
+	if err != nil {
+		logrus.Errorf("error getting card from repo: %s", err)
+		if errors.Is(repository.ErrCardNotFound, err) {
+			return card, ErrCardNotFound
+		}
+		return card, ErrInternal
+	}
+	if card.UserId != userId {
+		logrus.Errorf("error card %s doesn't belong user with id %s", id, userId)
+		return card, ErrCardNotFound
+	}
+	return card, nil
+}
+
+// IssueCard issues card linked to the account of the user. The PAN is returned
+// only here, the bank doesn't keep it.
+func (s *CardsService) IssueCard(ctx context.Context, userId uuid.UUID, accountId uuid.UUID,
+	pin string) (domain.IssuedCard, error) {
+	var issued domain.IssuedCard
+
+	if !domain.ValidatePin(pin) {
+		return issued, ErrInvalidPin
+	}
+
+	account, err := s.accountsRepo.Get(ctx, accountId)
+	if err != nil {
+		logrus.Errorf("error getting account from repo when issuing card: %s", err)
+		if errors.Is(repository.ErrAccountNotFound, err) {
+			return issued, ErrAccountNotFound
+		}
+		return issued, ErrInternal
+	}
+	if account.UserId != userId {
+		return issued, ErrAccountNotFound
+	}
+	if account.Frozen {
+		return issued, ErrAccountFrozen
+	}
+
+	cards, err := s.cardsRepo.GetAll(ctx, userId)
+	if err != nil {
+		logrus.Errorf("error getting cards from repo when issuing card: %s", err)
+		return issued, ErrInternal
+	}
+	active := 0
+	for _, card := range cards {
+		if card.AccountId == accountId && card.Status == domain.CardStatusActive {
+			active++
+		}
+	}
+	if active >= s.cfg.MaxPerAccount {
+		return issued, ErrTooManyCards
+	}
+
+	pinHash, err := s.hasher.Hash(pin)
+	if err != nil {
+		logrus.Errorf("error hashing card pin: %s", err)
+		return issued, ErrInternal
+	}
+
+	now := time.Now().UTC()
+	card := domain.Card{
+		UserId:    userId,
+		AccountId: accountId,
+		ExpiresOn: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).
+			AddDate(0, s.cfg.ValidityMonths, 0),
+		PinHash: pinHash,
+	}
+	for i := 0; i < panGenerationAttempts; i++ {
+		pan, err := s.generatePan()
+		if err != nil {
+			logrus.Errorf("error generating pan: %s", err)
+			return issued, ErrInternal
+		}
+		card.PanHash = s.hashPan(pan)
+		card.Last4 = pan[len(pan)-4:]
+
+		created, err := s.cardsRepo.Create(ctx, card)
+		if errors.Is(repository.ErrCardPanExists, err) {
+			continue
+		}
+		if err != nil {
+			return issued, ErrInternal
+		}
+		issued.Card = created
+		issued.Pan = pan
+		return issued, nil
+	}
+
+	logrus.Errorf("error generating unique pan for %d attempts", panGenerationAttempts)
+	return issued, ErrInternal
+}
+
+func (s *CardsService) GetCards(ctx context.Context, userId uuid.UUID) ([]domain.Card, error) {
+	cards, err := s.cardsRepo.GetAll(ctx, userId)
+	if err != nil {
+		logrus.Errorf("error getting cards from repo: %s", err)
+		return nil, ErrInternal
+	}
+	for i := range cards {
+		cards[i].PinLocked = cards[i].PinAttempts >= s.cfg.PinAttempts
+	}
+	return cards, nil
+}
+
+// BlockCard blocks the card for good, e.g. when it's lost. A new card has to
+// be issued instead.
+func (s *CardsService) BlockCard(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	if _, err := s.getCard(ctx, userId, id); err != nil {
+		return err
+	}
+
+	if err := s.cardsRepo.SetStatus(ctx, id, domain.CardStatusBlocked); err != nil {
+		if errors.Is(repository.ErrCardNotFound, err) {
+			return ErrCardNotFound
+		}
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// SetCardPin changes PIN of the card. It also unlocks the card after too many
+// wrong PINs, so the password of the user is required.
+func (s *CardsService) SetCardPin(ctx context.Context, userId uuid.UUID, id uuid.UUID,
+	password string, pin string) error {
+	if !domain.ValidatePin(pin) {
+		return ErrInvalidPin
+	}
+
+	user, err := s.usersRepo.Get(ctx, userId)
+	if err != nil {
+		logrus.Errorf("error getting user from repo when setting card pin: %s", err)
+		if errors.Is(repository.ErrUserNotFound, err) {
+			return ErrUserNotFound
+		}
+		return ErrInternal
+	}
+	if !s.hasher.Check(password, user.Password) {
+		return ErrWrongPassword
+	}
+
+	card, err := s.getCard(ctx, userId, id)
+	if err != nil {
+		return err
+	}
+	if card.Status != domain.CardStatusActive {
+		return ErrCardBlocked
+	}
+
+	pinHash, err := s.hasher.Hash(pin)
+	if err != nil {
+		logrus.Errorf("error hashing card pin: %s", err)
+		return ErrInternal
+	}
+	if err := s.cardsRepo.SetPin(ctx, id, pinHash); err != nil {
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// AuthenticateCard checks card read by ATM and PIN entered by the holder. The
+// card is locked after PinAttempts wrong PINs in a row until the holder sets
+// a new PIN.
+func (s *CardsService) AuthenticateCard(ctx context.Context,
+	credentials domain.CardCredentials) (domain.Card, error) {
+	var card domain.Card
+
+	if !domain.ValidatePan(credentials.Pan) || !domain.ValidateCardExpiry(credentials.Expiry) {
+		return card, ErrCardInvalid
+	}
+
+	card, err := s.cardsRepo.GetByPanHash(ctx, s.hashPan(credentials.Pan))
+	if err != nil {
+		if errors.Is(repository.ErrCardNotFound, err) {
+			return card, ErrCardInvalid
+		}
+		return card, ErrInternal
+	}
+	if card.Expiry() != credentials.Expiry {
+		logrus.Errorf("error card %s presented with wrong expiry", card.Id)
+		return card, ErrCardInvalid
+	}
+	if card.Status != domain.CardStatusActive {
+		return card, ErrCardBlocked
+	}
+	if card.Expired(time.Now()) {
+		return card, ErrCardExpired
+	}
+
+	attempts, err := s.cardsRepo.UsePinAttempt(ctx, card.Id, s.cfg.PinAttempts)
+	if err != nil {
+		if errors.Is(repository.ErrPinAttemptsExhausted, err) {
+			return card, ErrPinLocked
+		}
+		return card, ErrInternal
+	}
+	if !s.hasher.Check(credentials.Pin, card.PinHash) {
+		logrus.Errorf("error wrong pin of card %s, attempt %d", card.Id, attempts)
+		if attempts >= s.cfg.PinAttempts {
+			return card, ErrPinLocked
+		}
+		return card, ErrWrongPin
+	}
+	if err := s.cardsRepo.ResetPinAttempts(ctx, card.Id); err != nil {
+		return card, ErrInternal
+	}
+
+	return card, nil
+}
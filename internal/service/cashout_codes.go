@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const cashoutCodeGenerateAttempts = 3
+
+type CashoutCodesConfig struct {
+	TTL time.Duration
+	// MaxAmount is the largest amount of one code
+	MaxAmount int
+	// MaxPending is number of codes the user may hold at once
+	MaxPending int
+	// SecretAttempts are wrong secrets after which the code is blocked
+	SecretAttempts int
+	// MachineAttempts redemptions are allowed per machine within MachineWindow
+	MachineAttempts int
+	MachineWindow   time.Duration
+	SweepInterval   time.Duration
+	// CodeKey is HMAC key codes are looked up by
+	CodeKey string
+}
+
+func (s *MachinesService) hashCashoutCode(code string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.CashoutCodes.CodeKey))
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateCashoutCode reserves the amount on the account for cardless cashout.
+// Code and secret are returned once, the user enters both at the machine.
+func (s *MachinesService) CreateCashoutCode(ctx context.Context, userId uuid.UUID, accountId uuid.UUID,
+	amount int) (domain.IssuedCashoutCode, error) {
+	var issued domain.IssuedCashoutCode
+
+	if amount <= 0 || amount > s.cfg.CashoutCodes.MaxAmount {
+		return issued, ErrInvalidAmount
+	}
+	account, err := s.getAccount(ctx, accountId, userId)
+	if err != nil {
+		return issued, err
+	}
+	pending, err := s.cashoutCodesRepo.GetPending(ctx, userId)
+	if err != nil {
+		logrus.Errorf("error getting pending cashout codes from repo when creating: %s", err)
+		return issued, ErrInternal
+	}
+	if len(pending) >= s.cfg.CashoutCodes.MaxPending {
+		return issued, ErrTooManyCashoutCodes
+	}
+	if account.Money < amount {
+		return issued, ErrInsufficientFunds
+	}
+
+	secret, err := generateNumericCode(domain.CashoutSecretLength)
+	if err != nil {
+		logrus.Errorf("error generating cashout secret: %s", err)
+		return issued, ErrInternal
+	}
+	secretHash, err := s.hasher.Hash(secret)
+	if err != nil {
+		logrus.Errorf("error hashing cashout secret: %s", err)
+		return issued, ErrInternal
+	}
+
+	for i := 0; i < cashoutCodeGenerateAttempts; i++ {
+		code, err := generateNumericCode(domain.CashoutCodeLength)
+		if err != nil {
+			logrus.Errorf("error generating cashout code: %s", err)
+			return issued, ErrInternal
+		}
+
+		var created domain.CashoutCode
+		err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+			_, err := s.accountsRepo.Debit(ctx, account.Id, amount)
+			if err != nil {
+				return err
+			}
+			created, err = s.cashoutCodesRepo.Create(ctx, domain.CashoutCode{
+				UserId:     userId,
+				AccountId:  account.Id,
+				Amount:     amount,
+				CodeHash:   s.hashCashoutCode(code),
+				SecretHash: secretHash,
+				ExpiresAt:  time.Now().Add(s.cfg.CashoutCodes.TTL),
+			})
+			return err
+		})
+		if errors.Is(repository.ErrCashoutCodeExists, err) {
+			continue
+		}
+		if errors.Is(repository.ErrInsufficientFunds, err) {
+			return issued, ErrInsufficientFunds
+		}
+		if err != nil {
+			logrus.Errorf("error creating cashout code in transaction: %s", err)
+			return issued, ErrInternal
+		}
+		issued.CashoutCode = created
+		issued.Code = code
+		issued.Secret = secret
+		return issued, nil
+	}
+
+	logrus.Errorf("error creating cashout code: no unique code after %d attempts", cashoutCodeGenerateAttempts)
+	return issued, ErrInternal
+}
+
+func (s *MachinesService) GetCashoutCodes(ctx context.Context, userId uuid.UUID) ([]domain.CashoutCode, error) {
+	codes, err := s.cashoutCodesRepo.GetPending(ctx, userId)
+	if err != nil {
+		logrus.Errorf("error getting pending cashout codes from repo: %s", err)
+		return nil, ErrInternal
+	}
+	return codes, nil
+}
+
+// releaseCashoutCode finishes pending code with the status and credits the
+// reserved amount back to the account.
+func (s *MachinesService) releaseCashoutCode(ctx context.Context, id uuid.UUID, status string) error {
+	return s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		code, err := s.cashoutCodesRepo.Finish(ctx, id, status, uuid.NullUUID{})
+		if err != nil {
+			return err
+		}
+		return s.refundCashoutCode(ctx, code)
+	})
+}
+
+func (s *MachinesService) refundCashoutCode(ctx context.Context, code domain.CashoutCode) error {
+	_, err := s.accountsRepo.Credit(ctx, code.AccountId, code.Amount)
+	return err
+}
+
+// CancelCashoutCode releases reservation of the code which isn't needed anymore.
+func (s *MachinesService) CancelCashoutCode(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	pending, err := s.cashoutCodesRepo.GetPending(ctx, userId)
+	if err != nil {
+		logrus.Errorf("error getting pending cashout codes from repo when cancelling: %s", err)
+		return ErrInternal
+	}
+	found := false
+	for _, code := range pending {
+		found = found || code.Id == id
+	}
+	if !found {
+		return ErrCashoutCodeNotFound
+	}
+
+	err = s.releaseCashoutCode(ctx, id, domain.CashoutCodeCancelled)
+	if err != nil {
+		logrus.Errorf("error cancelling cashout code: %s", err)
+		if errors.Is(repository.ErrCashoutCodeNotFound, err) {
+			return ErrCashoutCodeNotFound
+		}
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// RedeemCashoutCode returns notes the machine should dispense for the code.
+// Attempts are limited per machine, the code is blocked and its reservation
+// released after SecretAttempts wrong secrets.
+func (s *MachinesService) RedeemCashoutCode(ctx context.Context, id uuid.UUID, code string,
+	secret string) ([]domain.Note, error) {
+	if _, err := s.getOperatingMachine(ctx, id, domain.MachineOperationCashOut); err != nil {
+		return nil, err
+	}
+
+	limited, err := hitRateLimit(ctx, s.rdb, fmt.Sprintf("cashout-code:machine:%s", id),
+		s.cfg.CashoutCodes.MachineAttempts, s.cfg.CashoutCodes.MachineWindow)
+	if err != nil {
+		logrus.Errorf("error hitting cashout code rate limit: %s", err)
+		return nil, ErrInternal
+	}
+	if limited {
+		return nil, ErrTooManyRequests
+	}
+
+	if !domain.ValidateCashoutCode(code) || !domain.ValidateCashoutSecret(secret) {
+		return nil, ErrCashoutCodeInvalid
+	}
+	cashoutCode, err := s.cashoutCodesRepo.GetByCodeHash(ctx, s.hashCashoutCode(code))
+	if err != nil {
+		if errors.Is(repository.ErrCashoutCodeNotFound, err) {
+			return nil, ErrCashoutCodeInvalid
+		}
+		logrus.Errorf("error getting cashout code from repo by hash: %s", err)
+		return nil, ErrInternal
+	}
+
+	if !s.hasher.Check(secret, cashoutCode.SecretHash) {
+		attempts, err := s.cashoutCodesRepo.UseSecretAttempt(ctx, cashoutCode.Id)
+		if err != nil {
+			logrus.Errorf("error using cashout secret attempt in repo: %s", err)
+			return nil, ErrInternal
+		}
+		if attempts >= s.cfg.CashoutCodes.SecretAttempts {
+			err := s.releaseCashoutCode(ctx, cashoutCode.Id, domain.CashoutCodeBlocked)
+			if err != nil && !errors.Is(repository.ErrCashoutCodeNotFound, err) {
+				logrus.Errorf("error blocking cashout code: %s", err)
+			}
+		}
+		return nil, ErrCashoutCodeInvalid
+	}
+
+	notes, err := s.redeemCashoutCode(ctx, id, cashoutCode)
+	if err != nil {
+		if !errors.Is(ErrCashoutCodeInvalid, err) {
+			s.journalDeclinedCashoutCode(ctx, id, cashoutCode, err)
+		}
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+func (s *MachinesService) redeemCashoutCode(ctx context.Context, id uuid.UUID,
+	code domain.CashoutCode) ([]domain.Note, error) {
+	user, err := s.getUser(ctx, code.UserId)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.getAccount(ctx, code.AccountId, code.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []domain.Note
+	var cassettes []domain.Cassette
+	err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		_, err := s.cashoutCodesRepo.Finish(ctx, code.Id, domain.CashoutCodeRedeemed,
+			uuid.NullUUID{UUID: id, Valid: true})
+		if err != nil {
+			return err
+		}
+		cassettes, err = s.cassettesRepo.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		var ok bool
+		notes, ok = domain.PlanDispense(cassettes, code.Amount)
+		if !ok {
+			return ErrAmountNotDispensable
+		}
+		if err := s.cassettesRepo.Withdraw(ctx, id, notes); err != nil {
+			return err
+		}
+		return s.journalRepo.Create(ctx, domain.JournalEntry{
+			CashoutCodeId: uuid.NullUUID{UUID: code.Id, Valid: true},
+			MachineId:     id,
+			AccountId:     code.AccountId,
+			Operation:     domain.JournalCashOut,
+			Amount:        code.Amount,
+			Notes:         notes,
+		})
+	})
+	if err != nil {
+		if errors.Is(ErrAmountNotDispensable, err) {
+			return nil, err
+		}
+		if errors.Is(repository.ErrCashoutCodeNotFound, err) {
+			return nil, ErrCashoutCodeInvalid
+		}
+		logrus.Errorf("error redeeming cashout code in transaction: %s", err)
+		return nil, ErrInternal
+	}
+
+	if err := s.broker.WriteCashoutTask(ctx, id, user.Email, account.Id, code.Amount, account.Money); err != nil {
+		logrus.Errorf("error writing cashout task: %s", err)
+	}
+	alertLowCash(ctx, s.broker, id, cassettes, notes)
+
+	return notes, nil
+}
+
+// journalDeclinedCashoutCode records redemption refused to the holder of valid
+// code and secret. The code stays pending and may be redeemed at other machine.
+func (s *MachinesService) journalDeclinedCashoutCode(ctx context.Context, id uuid.UUID,
+	code domain.CashoutCode, reason error) {
+	message := reason.Error()
+	err := s.journalRepo.Create(ctx, domain.JournalEntry{
+		CashoutCodeId: uuid.NullUUID{UUID: code.Id, Valid: true},
+		MachineId:     id,
+		AccountId:     code.AccountId,
+		Operation:     domain.JournalCashOut,
+		Amount:        code.Amount,
+		Outcome:       domain.JournalDeclined,
+		Reason:        &message,
+	})
+	if err != nil {
+		logrus.Errorf("error journaling declined cashout code: %s", err)
+	}
+}
+
+// RunCashoutCodeSweeper expires unused codes and releases their reservations
+// until ctx is done.
+func (s *MachinesService) RunCashoutCodeSweeper(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.CashoutCodes.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := s.transactionManager.Do(ctx, func(ctx context.Context) error {
+			codes, err := s.cashoutCodesRepo.ExpirePending(ctx, time.Now())
+			if err != nil {
+				return err
+			}
+			for _, code := range codes {
+				if err := s.refundCashoutCode(ctx, code); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			logrus.Errorf("error expiring cashout codes: %s", err)
+		}
+	}
+}
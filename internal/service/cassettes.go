@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/broker"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// alertLowCash notifies cash-in-transit staff about cassettes which fell below
+// their threshold after the notes were taken. Cassettes are the state before.
+func alertLowCash(ctx context.Context, broker broker.BrokerInterface, machineId uuid.UUID,
+	cassettes []domain.Cassette, taken []domain.Note) {
+	for _, cassette := range cassettes {
+		wasLow := cassette.Low()
+		for _, note := range taken {
+			if note.Denomination == cassette.Denomination {
+				cassette.Count -= note.Count
+			}
+		}
+		if wasLow || !cassette.Low() {
+			continue
+		}
+		err := broker.WriteLowCashTask(ctx, machineId, cassette.Denomination, cassette.Count)
+		if err != nil {
+			logrus.Errorf("error writing low cash task: %s", err)
+		}
+	}
+}
+
+func (s *AdminService) GetCassettes(ctx context.Context, machineId uuid.UUID) ([]domain.Cassette, error) {
+	if _, err := s.getMachine(ctx, machineId); err != nil {
+		return nil, err
+	}
+
+	cassettes, err := s.cassettesRepo.GetAll(ctx, machineId)
+	if err != nil {
+		logrus.Errorf("error getting cassettes from repo: %s", err)
+		return nil, ErrInternal
+	}
+
+	return cassettes, nil
+}
+
+// ReplenishCassettes loads notes brought by cash-in-transit staff into the machine.
+func (s *AdminService) ReplenishCassettes(ctx context.Context, actorId uuid.UUID, machineId uuid.UUID,
+	notes []domain.Note) ([]domain.Cassette, error) {
+	if !domain.ValidateNotes(notes) {
+		return nil, ErrInvalidNotes
+	}
+	machine, err := s.getMachine(ctx, machineId)
+	if err != nil {
+		return nil, err
+	}
+	if machine.Status == domain.MachineStatusDecommissioned {
+		return nil, ErrMachineDecommissioned
+	}
+
+	var cassettes []domain.Cassette
+	err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.cassettesRepo.Add(ctx, machineId, notes); err != nil {
+			return err
+		}
+		var err error
+		cassettes, err = s.cassettesRepo.GetAll(ctx, machineId)
+		if err != nil {
+			return err
+		}
+		return s.record(ctx, actorId, adminActionReplenishCassettes, adminTargetMachine, machineId.String(),
+			formatNotes(notes))
+	})
+	if err != nil {
+		logrus.Errorf("error replenishing cassettes in transaction: %s", err)
+		return nil, ErrInternal
+	}
+
+	return cassettes, nil
+}
+
+// CountCassettes stores notes counted in the machine by staff. Difference with
+// the recorded inventory is kept in audit log.
+func (s *AdminService) CountCassettes(ctx context.Context, actorId uuid.UUID, machineId uuid.UUID,
+	counted []domain.Cassette) ([]domain.Cassette, error) {
+	if len(counted) == 0 {
+		return nil, ErrInvalidNotes
+	}
+	seen := make(map[int]bool, len(counted))
+	for _, cassette := range counted {
+		if cassette.Denomination <= 0 || cassette.Count < 0 || cassette.LowThreshold < 0 ||
+			seen[cassette.Denomination] {
+			return nil, ErrInvalidNotes
+		}
+		seen[cassette.Denomination] = true
+	}
+	machine, err := s.getMachine(ctx, machineId)
+	if err != nil {
+		return nil, err
+	}
+	if machine.Status == domain.MachineStatusDecommissioned {
+		return nil, ErrMachineDecommissioned
+	}
+
+	var cassettes []domain.Cassette
+	err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		recorded, err := s.cassettesRepo.GetForUpdate(ctx, machineId)
+		if err != nil {
+			return err
+		}
+		recordedCounts := make(map[int]int, len(recorded))
+		for _, cassette := range recorded {
+			recordedCounts[cassette.Denomination] = cassette.Count
+		}
+
+		differences := []string{}
+		for _, cassette := range counted {
+			cassette.MachineId = machineId
+			if err := s.cassettesRepo.Set(ctx, cassette); err != nil {
+				return err
+			}
+			differences = append(differences, fmt.Sprintf("%d: %d -> %d", cassette.Denomination,
+				recordedCounts[cassette.Denomination], cassette.Count))
+		}
+
+		cassettes, err = s.cassettesRepo.GetAll(ctx, machineId)
+		if err != nil {
+			return err
+		}
+		return s.record(ctx, actorId, adminActionCountCassettes, adminTargetMachine, machineId.String(),
+			strings.Join(differences, ", "))
+	})
+	if err != nil {
+		logrus.Errorf("error counting cassettes in transaction: %s", err)
+		return nil, ErrInternal
+	}
+
+	for _, cassette := range cassettes {
+		if !cassette.Low() {
+			continue
+		}
+		err := s.broker.WriteLowCashTask(ctx, machineId, cassette.Denomination, cassette.Count)
+		if err != nil {
+			logrus.Errorf("error writing low cash task: %s", err)
+		}
+	}
+
+	return cassettes, nil
+}
+
+func formatNotes(notes []domain.Note) string {
+	parts := make([]string, len(notes))
+	for i, note := range notes {
+		parts[i] = fmt.Sprintf("%dx%d", note.Denomination, note.Count)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/broker"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/tokens"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeRedis speaks enough of RESP2 for the commands used by the services.
+// Expiration of keys is ignored.
+type fakeRedis struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error listening fake redis: %s", err)
+	}
+	srv := &fakeRedis{data: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go srv.serve(conn)
+		}
+	}()
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:             ln.Addr().String(),
+		Protocol:         2,
+		DisableIndentity: true,
+	})
+	t.Cleanup(func() {
+		rdb.Close()
+		ln.Close()
+	})
+	return rdb
+}
+
+func (r *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		if _, err := io.WriteString(conn, r.exec(args)); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected line %q", line)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+func bulk(value string, ok bool) string {
+	if !ok {
+		return "$-1\r\n"
+	}
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+}
+
+func (r *fakeRedis) exec(args []string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "SET":
+		nx := false
+		for _, arg := range args[3:] {
+			if strings.EqualFold(arg, "nx") {
+				nx = true
+			}
+		}
+		if _, ok := r.data[args[1]]; ok && nx {
+			return bulk("", false)
+		}
+		r.data[args[1]] = args[2]
+		return "+OK\r\n"
+	case "GET":
+		value, ok := r.data[args[1]]
+		return bulk(value, ok)
+	case "GETDEL":
+		value, ok := r.data[args[1]]
+		delete(r.data, args[1])
+		return bulk(value, ok)
+	case "DEL", "EXISTS":
+		count := 0
+		for _, key := range args[1:] {
+			if _, ok := r.data[key]; ok {
+				count++
+				if strings.EqualFold(args[0], "del") {
+					delete(r.data, key)
+				}
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", count)
+	case "INCR":
+		value, _ := strconv.Atoi(r.data[args[1]])
+		value++
+		r.data[args[1]] = strconv.Itoa(value)
+		return fmt.Sprintf(":%d\r\n", value)
+	case "EXPIRE", "PEXPIRE":
+		_, ok := r.data[args[1]]
+		if ok {
+			return ":1\r\n"
+		}
+		return ":0\r\n"
+	default:
+		return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+type testUsers struct {
+	repository.Users
+	users map[uuid.UUID]domain.User
+}
+
+func (r *testUsers) Get(ctx context.Context, id uuid.UUID) (domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return user, repository.ErrUserNotFound
+	}
+	return user, nil
+}
+
+type testTwoFactor struct {
+	repository.TwoFactor
+}
+
+func (r *testTwoFactor) Get(ctx context.Context, userId uuid.UUID) (domain.TwoFactor, error) {
+	return domain.TwoFactor{}, repository.ErrTwoFactorNotFound
+}
+
+type testPasskeys struct {
+	passkeys []domain.Passkey
+}
+
+func (r *testPasskeys) Create(ctx context.Context, passkey domain.Passkey) (domain.Passkey, error) {
+	passkey.Id = uuid.New()
+	r.passkeys = append(r.passkeys, passkey)
+	return passkey, nil
+}
+
+func (r *testPasskeys) GetAll(ctx context.Context, userId uuid.UUID) ([]domain.Passkey, error) {
+	var passkeys []domain.Passkey
+	for _, passkey := range r.passkeys {
+		if passkey.UserId == userId {
+			passkeys = append(passkeys, passkey)
+		}
+	}
+	return passkeys, nil
+}
+
+func (r *testPasskeys) Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	for i, passkey := range r.passkeys {
+		if passkey.UserId == userId && passkey.Id == id {
+			r.passkeys = append(r.passkeys[:i], r.passkeys[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrPasskeyNotFound
+}
+
+func (r *testPasskeys) DeleteAll(ctx context.Context, userId uuid.UUID) error {
+	passkeys, _ := r.GetAll(ctx, userId)
+	for _, passkey := range passkeys {
+		r.Delete(ctx, userId, passkey.Id)
+	}
+	return nil
+}
+
+func (r *testPasskeys) UpdateSignCount(ctx context.Context, id uuid.UUID, signCount int64) error {
+	for i := range r.passkeys {
+		if r.passkeys[i].Id == id {
+			r.passkeys[i].SignCount = signCount
+			return nil
+		}
+	}
+	return repository.ErrPasskeyNotFound
+}
+
+type testSessions struct {
+	repository.Sessions
+}
+
+func (r *testSessions) KnownDevice(ctx context.Context, userId uuid.UUID, userAgent string) (bool, error) {
+	return true, nil
+}
+
+func (r *testSessions) Create(ctx context.Context, session domain.Session) (uuid.UUID, error) {
+	return uuid.New(), nil
+}
+
+// testHasher keeps passwords as they are.
+type testHasher struct{}
+
+func (testHasher) Hash(password string) (string, error) {
+	return password, nil
+}
+
+func (testHasher) Check(password string, hash string) bool {
+	return password == hash
+}
+
+func (testHasher) NeedsRehash(hash string) bool {
+	return false
+}
+
+type testTokens struct {
+	tokens.TokenManagerInterface
+}
+
+func (testTokens) CreateAccessToken(userId uuid.UUID, role string, sessionId uuid.UUID) (string, error) {
+	return "access:" + sessionId.String(), nil
+}
+
+type testBroker struct {
+	broker.BrokerInterface
+	passkeysAdded []string
+}
+
+func (b *testBroker) WritePasskeyAddedTask(ctx context.Context, email string, name string) error {
+	b.passkeysAdded = append(b.passkeysAdded, email+":"+name)
+	return nil
+}
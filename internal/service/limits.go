@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// hitRateLimit increments counter under key and reports whether limit is exceeded
+// within window. The window starts with the first hit.
+func hitRateLimit(ctx context.Context, rdb *redis.Client, key string, limit int,
+	window time.Duration) (bool, error) {
+	count, err := rdb.Incr(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	if count == 1 {
+		if err := rdb.Expire(ctx, key, window).Err(); err != nil {
+			return false, err
+		}
+	}
+	return count > int64(limit), nil
+}
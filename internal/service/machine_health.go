@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+// Heartbeat stores state reported by the machine and marks it online.
+func (s *MachinesService) Heartbeat(ctx context.Context, heartbeat domain.MachineHeartbeat) error {
+	if !domain.ValidateDeviceStatus(heartbeat.Dispenser) ||
+		!domain.ValidateDeviceStatus(heartbeat.CardReader) ||
+		!domain.ValidateSoftwareVersion(heartbeat.SoftwareVersion) ||
+		!heartbeat.CashLevels.Validate() {
+		return ErrInvalidHeartbeat
+	}
+
+	if err := s.healthRepo.Save(ctx, heartbeat); err != nil {
+		logrus.Errorf("error saving machine heartbeat into repo: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// RunHealthMonitor marks active machines which stopped sending heartbeats as
+// offline and raises alert for each of them until ctx is done.
+func (s *MachinesService) RunHealthMonitor(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		machines, err := s.healthRepo.MarkOffline(ctx, time.Now().Add(-s.cfg.OfflineAfter))
+		if err != nil {
+			logrus.Errorf("error marking silent machines offline: %s", err)
+			continue
+		}
+		for _, machine := range machines {
+			err := s.broker.WriteMachineOfflineTask(ctx, machine.MachineId, machine.Name, *machine.LastSeenAt)
+			if err != nil {
+				logrus.Errorf("error writing machine offline task: %s", err)
+			}
+		}
+	}
+}
+
+func (s *AdminService) GetFleetHealth(ctx context.Context) (domain.FleetHealth, error) {
+	var fleet domain.FleetHealth
+
+	machines, err := s.healthRepo.GetFleet(ctx)
+	if err != nil {
+		logrus.Errorf("error getting fleet health from repo: %s", err)
+		return fleet, ErrInternal
+	}
+
+	fleet.Machines = []domain.MachineHealth{}
+	for _, machine := range machines {
+		fleet.Total++
+		attention := false
+		switch {
+		case machine.LastSeenAt == nil:
+			fleet.NeverSeen++
+		case machine.Online:
+			fleet.Online++
+		default:
+			fleet.Offline++
+			attention = true
+		}
+		if machine.Status == domain.MachineStatusMaintenance {
+			fleet.Maintenance++
+		}
+		if machine.Faulty() {
+			fleet.Faulty++
+			attention = true
+		}
+		if machine.LowCash {
+			fleet.LowCash++
+			attention = true
+		}
+		if attention {
+			fleet.Machines = append(fleet.Machines, machine)
+		}
+	}
+
+	return fleet, nil
+}
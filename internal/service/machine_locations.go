@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+const machineSearchMaxRadius = 50000
+
+// FindMachines returns active machines within the radius nearest first. The
+// box around the point is searched by index, machines in its corners are
+// dropped by exact distance.
+func (s *MachinesService) FindMachines(ctx context.Context,
+	search domain.MachineSearch) ([]domain.NearbyMachine, error) {
+	if !domain.ValidateCoordinates(search.Latitude, search.Longitude) ||
+		search.Radius <= 0 || search.Radius > machineSearchMaxRadius || search.Limit <= 0 {
+		return nil, ErrInvalidSearch
+	}
+	if search.Operation != "" && !domain.ValidateMachineOperations([]string{search.Operation}) {
+		return nil, ErrInvalidSearch
+	}
+
+	box := domain.NewBoundingBox(search.Latitude, search.Longitude, search.Radius)
+	candidates, err := s.machinesRepo.GetNearby(ctx, box, search.Operation)
+	if err != nil {
+		logrus.Errorf("error getting nearby machines from repo: %s", err)
+		return nil, ErrInternal
+	}
+
+	machines := make([]domain.NearbyMachine, 0, len(candidates))
+	for _, machine := range candidates {
+		machine.Distance = domain.Distance(search.Latitude, search.Longitude, machine.Latitude,
+			machine.Longitude)
+		if machine.Distance <= search.Radius {
+			machines = append(machines, machine)
+		}
+	}
+	sort.Slice(machines, func(i, j int) bool {
+		if machines[i].Distance != machines[j].Distance {
+			return machines[i].Distance < machines[j].Distance
+		}
+		return machines[i].Id.String() < machines[j].Id.String()
+	})
+	if len(machines) > search.Limit {
+		machines = machines[:search.Limit]
+	}
+
+	return machines, nil
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const miniStatementSize = 10
+
+// miniStatementOperations are journaled operations which move money.
+var miniStatementOperations = []string{domain.JournalCashOut, domain.JournalDeposit}
+
+func (s *MachinesService) journal(ctx context.Context, session domain.MachineSession, operation string,
+	amount int, notes []domain.Note) error {
+	return s.journalRepo.Create(ctx, domain.JournalEntry{
+		SessionId: uuid.NullUUID{UUID: session.Id, Valid: true},
+		MachineId: session.MachineId,
+		AccountId: session.AccountId,
+		Operation: operation,
+		Amount:    amount,
+		Notes:     notes,
+	})
+}
+
+// journalDeclined records operation refused to the session. It's kept out of
+// the refused transaction, failure is only logged not to mask the refusal.
+func (s *MachinesService) journalDeclined(ctx context.Context, session domain.MachineSession, operation string,
+	amount int, notes []domain.Note, reason error) {
+	message := reason.Error()
+	err := s.journalRepo.Create(ctx, domain.JournalEntry{
+		SessionId: uuid.NullUUID{UUID: session.Id, Valid: true},
+		MachineId: session.MachineId,
+		AccountId: session.AccountId,
+		Operation: operation,
+		Amount:    amount,
+		Notes:     notes,
+		Outcome:   domain.JournalDeclined,
+		Reason:    &message,
+	})
+	if err != nil {
+		logrus.Errorf("error journaling declined %s: %s", operation, err)
+	}
+}
+
+// OpenMachineSession starts serving the authenticated card at the machine. A
+// session the machine left open is closed, the machine serves one card at a
+// time. ErrMachineBusy is returned while the gateway serves a card there.
+func (s *MachinesService) OpenMachineSession(ctx context.Context, id uuid.UUID,
+	card domain.Card) (domain.MachineSession, error) {
+	return s.openSession(ctx, id, card, domain.MachineSessionChannelMachine)
+}
+
+// OpenGatewaySession starts serving the card of acquirer gateway request at the
+// machine. Sessions open on the machine aren't closed, ErrMachineBusy is
+// returned instead.
+func (s *MachinesService) OpenGatewaySession(ctx context.Context, id uuid.UUID,
+	card domain.Card) (domain.MachineSession, error) {
+	return s.openSession(ctx, id, card, domain.MachineSessionChannelGateway)
+}
+
+func (s *MachinesService) openSession(ctx context.Context, id uuid.UUID, card domain.Card,
+	channel string) (domain.MachineSession, error) {
+	var session domain.MachineSession
+
+	machine, err := s.getMachine(ctx, id)
+	if err != nil {
+		return session, err
+	}
+	if machine.Status != domain.MachineStatusActive {
+		return session, ErrMachineNotActive
+	}
+	if _, err := s.getAccount(ctx, card.AccountId, card.UserId); err != nil {
+		return session, err
+	}
+
+	err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		if channel == domain.MachineSessionChannelMachine {
+			previous, err := s.sessionsRepo.CloseOpen(ctx, id, channel, domain.MachineSessionReplaced)
+			if err != nil {
+				return err
+			}
+			if previous != nil {
+				if err := s.journal(ctx, *previous, domain.JournalSessionClose, 0, nil); err != nil {
+					return err
+				}
+			}
+		}
+		session, err = s.sessionsRepo.Create(ctx, domain.MachineSession{
+			MachineId: id,
+			Channel:   channel,
+			CardId:    card.Id,
+			UserId:    card.UserId,
+			AccountId: card.AccountId,
+		})
+		if err != nil {
+			return err
+		}
+		return s.journal(ctx, session, domain.JournalSessionOpen, 0, nil)
+	})
+	if err != nil {
+		if errors.Is(repository.ErrMachineSessionOpen, err) {
+			return session, ErrMachineBusy
+		}
+		logrus.Errorf("error opening machine session in transaction: %s", err)
+		return session, ErrInternal
+	}
+	session.ExpiresAt = session.LastActivityAt.Add(s.cfg.SessionIdleTimeout)
+
+	return session, nil
+}
+
+// getSession returns open session of the machine and prolongs it.
+func (s *MachinesService) getSession(ctx context.Context, id uuid.UUID,
+	sessionId uuid.UUID) (domain.MachineSession, error) {
+	session, err := s.sessionsRepo.Touch(ctx, sessionId, id, time.Now().Add(-s.cfg.SessionIdleTimeout))
+	if err != nil {
+		logrus.Errorf("error getting machine session from repo: %s", err)
+		if errors.Is(repository.ErrMachineSessionNotFound, err) {
+			return session, ErrMachineSessionNotFound
+		}
+		return session, ErrInternal
+	}
+	session.ExpiresAt = session.LastActivityAt.Add(s.cfg.SessionIdleTimeout)
+	return session, nil
+}
+
+// MachineBalance returns the account of the session for balance inquiry.
+func (s *MachinesService) MachineBalance(ctx context.Context, id uuid.UUID,
+	sessionId uuid.UUID) (domain.Account, error) {
+	session, err := s.getSession(ctx, id, sessionId)
+	if err != nil {
+		return domain.Account{}, err
+	}
+
+	account, err := s.getAccount(ctx, session.AccountId, session.UserId)
+	if err != nil {
+		return account, err
+	}
+
+	if err := s.journal(ctx, session, domain.JournalBalance, 0, nil); err != nil {
+		return account, ErrInternal
+	}
+
+	return account, nil
+}
+
+// MachineMiniStatement returns the latest cash operations on the account of the session.
+func (s *MachinesService) MachineMiniStatement(ctx context.Context, id uuid.UUID,
+	sessionId uuid.UUID) ([]domain.JournalEntry, error) {
+	session, err := s.getSession(ctx, id, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.getAccount(ctx, session.AccountId, session.UserId); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.journalRepo.GetByAccount(ctx, session.AccountId, miniStatementOperations,
+		miniStatementSize, 0)
+	if err != nil {
+		return nil, ErrInternal
+	}
+
+	if err := s.journal(ctx, session, domain.JournalMiniStatement, 0, nil); err != nil {
+		return nil, ErrInternal
+	}
+
+	return entries, nil
+}
+
+// MachineCashOut debits the account of the session. Reference is optional, the
+// cash out can be reversed by it.
+func (s *MachinesService) MachineCashOut(ctx context.Context, id uuid.UUID, sessionId uuid.UUID,
+	amount int, reference string) ([]domain.Note, error) {
+	session, err := s.getSession(ctx, id, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.cashOut(ctx, session, amount, reference)
+	if err != nil {
+		s.journalDeclined(ctx, session, domain.JournalCashOut, amount, nil, err)
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (s *MachinesService) MachineDeposit(ctx context.Context, id uuid.UUID, sessionId uuid.UUID,
+	amount int, notes []domain.Note) error {
+	session, err := s.getSession(ctx, id, sessionId)
+	if err != nil {
+		return err
+	}
+	if err := s.deposit(ctx, session, amount, notes); err != nil {
+		s.journalDeclined(ctx, session, domain.JournalDeposit, amount, notes, err)
+		return err
+	}
+	return nil
+}
+
+// CloseMachineSession ends the session when the card is returned to the holder.
+func (s *MachinesService) CloseMachineSession(ctx context.Context, id uuid.UUID, sessionId uuid.UUID) error {
+	session, err := s.getSession(ctx, id, sessionId)
+	if err != nil {
+		return err
+	}
+
+	err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.sessionsRepo.Close(ctx, session.Id, domain.MachineSessionClosed); err != nil {
+			return err
+		}
+		return s.journal(ctx, session, domain.JournalSessionClose, 0, nil)
+	})
+	if err != nil {
+		logrus.Errorf("error closing machine session in transaction: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// RunSessionSweeper closes sessions idle longer than SessionIdleTimeout until
+// ctx is done. Such sessions are already refused, closing records the timeout.
+func (s *MachinesService) RunSessionSweeper(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.SessionIdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := s.transactionManager.Do(ctx, func(ctx context.Context) error {
+			sessions, err := s.sessionsRepo.CloseIdle(ctx, time.Now().Add(-s.cfg.SessionIdleTimeout))
+			if err != nil {
+				return err
+			}
+			for _, session := range sessions {
+				if err := s.journal(ctx, session, domain.JournalSessionClose, 0, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			logrus.Errorf("error closing idle machine sessions: %s", err)
+		}
+	}
+}
@@ -3,29 +3,123 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/IvanMeln1k/go-bank-app-bank/internal/broker"
 	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
 	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/hasher"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/tokens"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
 
+type MachinesConfig struct {
+	// OfflineAfter is silence after which machine is considered offline
+	OfflineAfter        time.Duration
+	HealthCheckInterval time.Duration
+	// SessionIdleTimeout is inactivity after which machine session is closed
+	SessionIdleTimeout time.Duration
+	CashoutCodes       CashoutCodesConfig
+	ThirdPartyDeposits ThirdPartyDepositsConfig
+}
+
 type MachinesService struct {
-	machinesRepo repository.Machines
-	accountsRepo repository.Accounts
-	usersRepo    repository.Users
-	broker       broker.BrokerInterface
+	machinesRepo       repository.Machines
+	accountsRepo       repository.Accounts
+	usersRepo          repository.Users
+	cassettesRepo      repository.Cassettes
+	healthRepo         repository.MachineHealth
+	sessionsRepo       repository.MachineSessions
+	journalRepo        repository.MachineJournal
+	cashoutCodesRepo   repository.CashoutCodes
+	rdb                *redis.Client
+	hasher             hasher.HasherInterface
+	broker             broker.BrokerInterface
+	tokenManager       tokens.TokenManagerInterface
+	transactionManager transactions.ManagerInterface
+	cfg                MachinesConfig
 }
 
 func NewMachinesService(machinesRepo repository.Machines, accountsRepo repository.Accounts,
-	usersRepo repository.Users, broker broker.BrokerInterface) *MachinesService {
+	usersRepo repository.Users, cassettesRepo repository.Cassettes, healthRepo repository.MachineHealth,
+	sessionsRepo repository.MachineSessions, journalRepo repository.MachineJournal,
+	cashoutCodesRepo repository.CashoutCodes, rdb *redis.Client, hasher hasher.HasherInterface,
+	broker broker.BrokerInterface, tokenManager tokens.TokenManagerInterface,
+	transactionManager transactions.ManagerInterface, cfg MachinesConfig) *MachinesService {
 	return &MachinesService{
-		machinesRepo: machinesRepo,
-		accountsRepo: accountsRepo,
-		usersRepo:    usersRepo,
-		broker:       broker,
+		machinesRepo:       machinesRepo,
+		accountsRepo:       accountsRepo,
+		usersRepo:          usersRepo,
+		cassettesRepo:      cassettesRepo,
+		healthRepo:         healthRepo,
+		sessionsRepo:       sessionsRepo,
+		journalRepo:        journalRepo,
+		cashoutCodesRepo:   cashoutCodesRepo,
+		rdb:                rdb,
+		hasher:             hasher,
+		broker:             broker,
+		tokenManager:       tokenManager,
+		transactionManager: transactionManager,
+		cfg:                cfg,
+	}
+}
+
+// AuthenticateMachine checks machine token and returns id of the machine. Only the last
+// token issued to the machine is accepted.
+func (s *MachinesService) AuthenticateMachine(ctx context.Context, machineToken string) (uuid.UUID, error) {
+	claims, err := s.tokenManager.ParseMachineToken(machineToken)
+	if err != nil {
+		logrus.Errorf("error parsing machine token: %s", err)
+		if errors.Is(tokens.ErrTokenExpired, err) {
+			return uuid.UUID{}, ErrTokenExpired
+		}
+		return uuid.UUID{}, ErrTokenInvalid
+	}
+
+	machine, err := s.getMachine(ctx, claims.Id)
+	if err != nil {
+		if errors.Is(ErrMachineNotFound, err) {
+			return uuid.UUID{}, ErrTokenInvalid
+		}
+		return uuid.UUID{}, err
+	}
+	if machine.Status == domain.MachineStatusDecommissioned {
+		return uuid.UUID{}, ErrTokenInvalid
 	}
+	if !machine.TokenId.Valid || machine.TokenId.UUID.String() != claims.StandardClaims.Id {
+		logrus.Errorf("error machine %s token %s is revoked", machine.Id, claims.StandardClaims.Id)
+		return uuid.UUID{}, ErrTokenInvalid
+	}
+
+	return machine.Id, nil
+}
+
+// IssueToken creates new machine token and revokes the previous one.
+func (s *MachinesService) IssueToken(ctx context.Context, id uuid.UUID) (string, error) {
+	return issueMachineToken(ctx, s.machinesRepo, s.tokenManager, id)
+}
+
+func issueMachineToken(ctx context.Context, machinesRepo repository.Machines,
+	tokenManager tokens.TokenManagerInterface, id uuid.UUID) (string, error) {
+	tokenId := uuid.New()
+	if err := machinesRepo.SetTokenId(ctx, id, tokenId); err != nil {
+		logrus.Errorf("error setting machine token id into repo: %s", err)
+		if errors.Is(repository.ErrMachineNotFound, err) {
+			return "", ErrMachineNotFound
+		}
+		return "", ErrInternal
+	}
+
+	token, err := tokenManager.CreateMachineToken(id, tokenId)
+	if err != nil {
+		logrus.Errorf("error creating machine token: %s", err)
+		return "", ErrInternal
+	}
+
+	return token, nil
 }
 
 func (s *MachinesService) getAccount(ctx context.Context, id uuid.UUID,
@@ -42,6 +136,9 @@ func (s *MachinesService) getAccount(ctx context.Context, id uuid.UUID,
 		logrus.Errorf("error account #%d doesn't belong user with id %d", id, userId)
 		return account, ErrAccountNotFound
 	}
+	if account.Frozen {
+		return account, ErrAccountFrozen
+	}
 	return account, nil
 }
 
@@ -69,69 +166,192 @@ func (s *MachinesService) getMachine(ctx context.Context, id uuid.UUID) (domain.
 	return machine, nil
 }
 
-func (s *MachinesService) CashOut(ctx context.Context, id uuid.UUID, userId uuid.UUID,
-	accountId uuid.UUID, amount int) error {
-	_, err := s.getMachine(ctx, id)
+// getOperatingMachine returns machine which is active and supports the operation.
+func (s *MachinesService) getOperatingMachine(ctx context.Context, id uuid.UUID,
+	operation string) (domain.Machine, error) {
+	machine, err := s.getMachine(ctx, id)
 	if err != nil {
-		return err
+		return machine, err
 	}
+	if machine.Status != domain.MachineStatusActive {
+		return machine, ErrMachineNotActive
+	}
+	if !machine.Supports(operation) {
+		return machine, ErrOperationNotSupported
+	}
+	return machine, nil
+}
 
-	user, err := s.getUser(ctx, userId)
+// cashOut debits the account of the session and returns notes the machine should
+// dispense. The amount is refused when it can't be paid with notes the machine holds.
+// Non-empty reference lets the machine reverse the cash out later.
+func (s *MachinesService) cashOut(ctx context.Context, session domain.MachineSession, amount int,
+	reference string) ([]domain.Note, error) {
+	id := session.MachineId
+	_, err := s.getOperatingMachine(ctx, id, domain.MachineOperationCashOut)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	account, err := s.getAccount(ctx, accountId, userId)
+	user, err := s.getUser(ctx, session.UserId)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	account, err := s.getAccount(ctx, session.AccountId, session.UserId)
+	if err != nil {
+		return nil, err
 	}
 
 	if account.Money < amount {
-		logrus.Errorf("error insufficient funds in the account #%d for cash out", accountId)
-		return ErrInsufficientFunds
+		logrus.Errorf("error insufficient funds in the account #%d for cash out", account.Id)
+		return nil, ErrInsufficientFunds
 	}
 
-	newMoney := account.Money - amount
-	_, err = s.accountsRepo.Update(ctx, accountId, domain.AccountUpdate{
-		Money: &newMoney,
+	var notes []domain.Note
+	var cassettes []domain.Cassette
+	var debited domain.Account
+	err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		cassettes, err = s.cassettesRepo.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		var ok bool
+		notes, ok = domain.PlanDispense(cassettes, amount)
+		if !ok {
+			return ErrAmountNotDispensable
+		}
+		if err := s.cassettesRepo.Withdraw(ctx, id, notes); err != nil {
+			return err
+		}
+		debited, err = s.accountsRepo.Debit(ctx, account.Id, amount)
+		if err != nil {
+			return err
+		}
+		entry := domain.JournalEntry{
+			SessionId: uuid.NullUUID{UUID: session.Id, Valid: true},
+			MachineId: id,
+			AccountId: account.Id,
+			Operation: domain.JournalCashOut,
+			Amount:    amount,
+			Notes:     notes,
+		}
+		if reference != "" {
+			entry.Reference = &reference
+		}
+		return s.journalRepo.Create(ctx, entry)
 	})
 	if err != nil {
-		logrus.Errorf("error updating account when cashout: %s", err)
-		return ErrInternal
+		if errors.Is(ErrAmountNotDispensable, err) {
+			return nil, err
+		}
+		if errors.Is(repository.ErrInsufficientFunds, err) {
+			return nil, ErrInsufficientFunds
+		}
+		if errors.Is(repository.ErrJournalReferenceExists, err) {
+			return nil, ErrDuplicateTransaction
+		}
+		logrus.Errorf("error cash out in transaction: %s", err)
+		return nil, ErrInternal
 	}
 
-	s.broker.WriteCashoutTask(ctx, id, user.Email, accountId, amount, newMoney)
+	if err := s.broker.WriteCashoutTask(ctx, id, user.Email, account.Id, amount, debited.Money); err != nil {
+		logrus.Errorf("error writing cashout task: %s", err)
+	}
+	alertLowCash(ctx, s.broker, id, cassettes, notes)
 
-	return nil
+	return notes, nil
 }
 
-func (s *MachinesService) Deposit(ctx context.Context, id uuid.UUID, userId uuid.UUID,
-	accountId uuid.UUID, amount int) error {
-	_, err := s.getMachine(ctx, id)
+// deposit credits the account of the session with notes accepted by the machine.
+// The notes are put into cassettes of the machine.
+func (s *MachinesService) deposit(ctx context.Context, session domain.MachineSession, amount int,
+	notes []domain.Note) error {
+	if !domain.ValidateNotes(notes) || domain.NotesAmount(notes) != amount {
+		return ErrInvalidNotes
+	}
+
+	id := session.MachineId
+	_, err := s.getOperatingMachine(ctx, id, domain.MachineOperationDeposit)
 	if err != nil {
 		return err
 	}
 
-	user, err := s.getUser(ctx, userId)
+	user, err := s.getUser(ctx, session.UserId)
 	if err != nil {
 		return err
 	}
 
-	account, err := s.getAccount(ctx, accountId, userId)
+	account, err := s.getAccount(ctx, session.AccountId, session.UserId)
 	if err != nil {
 		return err
 	}
 
-	newMoney := account.Money + amount
-	_, err = s.accountsRepo.Update(ctx, accountId, domain.AccountUpdate{
-		Money: &newMoney,
+	var credited domain.Account
+	err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.cassettesRepo.Add(ctx, id, notes); err != nil {
+			return err
+		}
+		var err error
+		credited, err = s.accountsRepo.Credit(ctx, account.Id, amount)
+		if err != nil {
+			return err
+		}
+		return s.journal(ctx, session, domain.JournalDeposit, amount, notes)
 	})
 	if err != nil {
-		logrus.Errorf("error updating account when deposit: %s", err)
+		logrus.Errorf("error deposit in transaction: %s", err)
 		return ErrInternal
 	}
 
-	s.broker.WriteDepositTask(ctx, id, user.Email, accountId, amount, newMoney)
+	if err := s.broker.WriteDepositTask(ctx, id, user.Email, account.Id, amount, credited.Money); err != nil {
+		logrus.Errorf("error writing deposit task: %s", err)
+	}
+
+	return nil
+}
+
+// ReverseMachineCashOut returns money of the cash out the machine failed to
+// dispense. The notes are still in cassettes, so they are put back into the
+// inventory. Repeated reversal is ignored.
+func (s *MachinesService) ReverseMachineCashOut(ctx context.Context, id uuid.UUID, reference string) error {
+	original, err := s.journalRepo.GetByReference(ctx, id, domain.JournalCashOut, reference)
+	if err != nil {
+		logrus.Errorf("error getting original cash out from repo: %s", err)
+		if errors.Is(repository.ErrJournalEntryNotFound, err) {
+			return ErrOriginalNotFound
+		}
+		return ErrInternal
+	}
+
+	err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		err := s.journalRepo.Create(ctx, domain.JournalEntry{
+			SessionId:     original.SessionId,
+			CashoutCodeId: original.CashoutCodeId,
+			MachineId:     id,
+			AccountId:     original.AccountId,
+			Operation:     domain.JournalReversal,
+			Amount:        original.Amount,
+			Notes:         original.Notes,
+			Reference:     &reference,
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.cassettesRepo.Add(ctx, id, original.Notes); err != nil {
+			return err
+		}
+		_, err = s.accountsRepo.Credit(ctx, original.AccountId, original.Amount)
+		return err
+	})
+	if err != nil {
+		if errors.Is(repository.ErrJournalReferenceExists, err) {
+			return nil
+		}
+		logrus.Errorf("error reversing cash out in transaction: %s", err)
+		return ErrInternal
+	}
 
 	return nil
 }
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/tokens"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	magicLinkTokenKey      = "magic-link:token:%s"
+	magicLinkEmailLimitKey = "ratelimit:magic-link:email:%s"
+	magicLinkIpLimitKey    = "ratelimit:magic-link:ip:%s"
+)
+
+// magicLink is saved under hash of the link token. Only the browser which
+// requested the link knows the nonce.
+type magicLink struct {
+	UserId    uuid.UUID `json:"userId"`
+	NonceHash string    `json:"nonceHash"`
+}
+
+// RequestMagicLink sends single-use sign in link to the email and returns nonce the
+// requesting browser must present with the link token. Nonce is returned even if
+// the email isn't registered.
+func (s *AuthService) RequestMagicLink(ctx context.Context, email string, ip string) (string, error) {
+	for _, key := range []string{
+		fmt.Sprintf(magicLinkIpLimitKey, ip),
+		fmt.Sprintf(magicLinkEmailLimitKey, email),
+	} {
+		limited, err := hitRateLimit(ctx, s.rdb, key, s.cfg.MagicLinkLimit, s.cfg.MagicLinkWindow)
+		if err != nil {
+			logrus.Errorf("error checking magic link rate limit: %s", err)
+			return "", ErrInternal
+		}
+		if limited {
+			return "", ErrTooManyRequests
+		}
+	}
+
+	nonce, err := tokens.GenerateRandomToken(32)
+	if err != nil {
+		logrus.Errorf("error generating magic link nonce: %s", err)
+		return "", ErrInternal
+	}
+
+	user, err := s.usersRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(repository.ErrUserNotFound, err) {
+			// the caller mustn't know whether the email is registered
+			return nonce, nil
+		}
+		logrus.Errorf("error getting user from repo by email when requesting magic link: %s", err)
+		return "", ErrInternal
+	}
+
+	token, err := tokens.GenerateRandomToken(32)
+	if err != nil {
+		logrus.Errorf("error generating magic link token: %s", err)
+		return "", ErrInternal
+	}
+	data, err := json.Marshal(magicLink{
+		UserId:    user.Id,
+		NonceHash: tokens.HashToken(nonce),
+	})
+	if err != nil {
+		logrus.Errorf("error marshaling magic link: %s", err)
+		return "", ErrInternal
+	}
+	err = s.rdb.Set(ctx, fmt.Sprintf(magicLinkTokenKey, tokens.HashToken(token)), data,
+		s.cfg.MagicLinkTTL).Err()
+	if err != nil {
+		logrus.Errorf("error saving magic link token into redis: %s", err)
+		return "", ErrInternal
+	}
+
+	if err := s.broker.WriteMagicLinkTask(ctx, user.Email, token); err != nil {
+		logrus.Errorf("error writing magic link task: %s", err)
+		return "", ErrInternal
+	}
+
+	return nonce, nil
+}
+
+// SignInMagicLink exchanges link token for access token. Like password sign in it
+// returns two factor challenge if the user has 2FA enabled.
+func (s *AuthService) SignInMagicLink(ctx context.Context, token string, nonce string,
+	device domain.Device) (domain.SignInResult, error) {
+	var result domain.SignInResult
+	key := fmt.Sprintf(magicLinkTokenKey, tokens.HashToken(token))
+
+	data, err := s.rdb.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(redis.Nil, err) {
+			return result, ErrTokenInvalid
+		}
+		logrus.Errorf("error getting magic link token from redis: %s", err)
+		return result, ErrInternal
+	}
+	var link magicLink
+	if err := json.Unmarshal(data, &link); err != nil {
+		logrus.Errorf("error unmarshaling magic link: %s", err)
+		return result, ErrInternal
+	}
+	if tokens.HashToken(nonce) != link.NonceHash {
+		logrus.Errorf("magic link nonce mismatch for user %s", link.UserId)
+		return result, ErrTokenInvalid
+	}
+
+	// GetDel makes the token single-use
+	if err := s.rdb.GetDel(ctx, key).Err(); err != nil {
+		if errors.Is(redis.Nil, err) {
+			return result, ErrTokenInvalid
+		}
+		logrus.Errorf("error deleting magic link token from redis: %s", err)
+		return result, ErrInternal
+	}
+
+	user, err := s.Get(ctx, link.UserId)
+	if err != nil {
+		return result, err
+	}
+	if user.ErasedAt != nil {
+		return result, ErrTokenInvalid
+	}
+
+	twoFactor, err := s.getTwoFactor(ctx, user.Id)
+	if err != nil && !errors.Is(ErrTwoFactorNotEnabled, err) {
+		return result, err
+	}
+	if err == nil && twoFactor.Enabled {
+		result.TwoFactorToken, err = s.createTwoFactorChallenge(ctx, user.Id)
+		return result, err
+	}
+
+	result.AccessToken, err = s.startSession(ctx, user, device)
+	return result, err
+}
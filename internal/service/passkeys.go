@@ -0,0 +1,453 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/tokens"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+var passkeyCeremonyKey = "passkey:ceremony:%s"
+
+const passkeyMaxPasskeys = 10
+
+// webAuthnUser adapts the user and their passkeys to webauthn library. User handle
+// is id of the user.
+type webAuthnUser struct {
+	user     domain.User
+	passkeys []domain.Passkey
+}
+
+func (u webAuthnUser) WebAuthnID() []byte {
+	id := u.user.Id
+	return id[:]
+}
+
+func (u webAuthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u webAuthnUser) WebAuthnDisplayName() string {
+	return strings.TrimSpace(u.user.Name + " " + u.user.Surname)
+}
+
+func (u webAuthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (u webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, len(u.passkeys))
+	for i, passkey := range u.passkeys {
+		transports := make([]protocol.AuthenticatorTransport, len(passkey.Transports))
+		for j, transport := range passkey.Transports {
+			transports[j] = protocol.AuthenticatorTransport(transport)
+		}
+		credentials[i] = webauthn.Credential{
+			ID:              passkey.CredentialId,
+			PublicKey:       passkey.PublicKey,
+			AttestationType: passkey.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: passkey.BackupEligible,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    passkey.AAGUID,
+				SignCount: uint32(passkey.SignCount),
+			},
+		}
+	}
+	return credentials
+}
+
+func (u webAuthnUser) passkey(credentialId []byte) (domain.Passkey, bool) {
+	for _, passkey := range u.passkeys {
+		if bytes.Equal(passkey.CredentialId, credentialId) {
+			return passkey, true
+		}
+	}
+	return domain.Passkey{}, false
+}
+
+// passkeyVerifier runs WebAuthn ceremonies. State of sign in and registration
+// ceremonies is kept in redis between begin and finish steps.
+type passkeyVerifier struct {
+	webAuthn     *webauthn.WebAuthn
+	rdb          *redis.Client
+	passkeysRepo repository.Passkeys
+	ceremonyTTL  time.Duration
+}
+
+func newPasskeyVerifier(webAuthn *webauthn.WebAuthn, rdb *redis.Client, passkeysRepo repository.Passkeys,
+	ceremonyTTL time.Duration) *passkeyVerifier {
+	return &passkeyVerifier{
+		webAuthn:     webAuthn,
+		rdb:          rdb,
+		passkeysRepo: passkeysRepo,
+		ceremonyTTL:  ceremonyTTL,
+	}
+}
+
+func (v *passkeyVerifier) user(ctx context.Context, user domain.User) (webAuthnUser, error) {
+	passkeys, err := v.passkeysRepo.GetAll(ctx, user.Id)
+	if err != nil {
+		logrus.Errorf("error getting passkeys from repo: %s", err)
+		return webAuthnUser{}, ErrInternal
+	}
+	return webAuthnUser{
+		user:     user,
+		passkeys: passkeys,
+	}, nil
+}
+
+// saveCeremony keeps state of the ceremony until the browser finishes it.
+func (v *passkeyVerifier) saveCeremony(ctx context.Context, options interface{},
+	session *webauthn.SessionData) (domain.PasskeyCeremony, error) {
+	var ceremony domain.PasskeyCeremony
+
+	optionsData, err := json.Marshal(options)
+	if err != nil {
+		logrus.Errorf("error marshaling passkey ceremony options: %s", err)
+		return ceremony, ErrInternal
+	}
+	sessionData, err := json.Marshal(session)
+	if err != nil {
+		logrus.Errorf("error marshaling passkey ceremony session: %s", err)
+		return ceremony, ErrInternal
+	}
+
+	token, err := tokens.GenerateRandomToken(32)
+	if err != nil {
+		logrus.Errorf("error generating passkey ceremony token: %s", err)
+		return ceremony, ErrInternal
+	}
+	err = v.rdb.Set(ctx, fmt.Sprintf(passkeyCeremonyKey, tokens.HashToken(token)), sessionData,
+		v.ceremonyTTL).Err()
+	if err != nil {
+		logrus.Errorf("error saving passkey ceremony into redis: %s", err)
+		return ceremony, ErrInternal
+	}
+
+	ceremony.Token = token
+	ceremony.Options = optionsData
+	return ceremony, nil
+}
+
+// takeCeremony returns state of the ceremony. Every ceremony can be finished once.
+func (v *passkeyVerifier) takeCeremony(ctx context.Context, token string) (webauthn.SessionData, error) {
+	var session webauthn.SessionData
+
+	data, err := v.rdb.GetDel(ctx, fmt.Sprintf(passkeyCeremonyKey, tokens.HashToken(token))).Bytes()
+	if err != nil {
+		if errors.Is(redis.Nil, err) {
+			return session, ErrTokenInvalid
+		}
+		logrus.Errorf("error getting passkey ceremony from redis: %s", err)
+		return session, ErrInternal
+	}
+	if err := json.Unmarshal(data, &session); err != nil {
+		logrus.Errorf("error unmarshaling passkey ceremony session: %s", err)
+		return session, ErrInternal
+	}
+
+	return session, nil
+}
+
+// beginAssertion returns assertion options for passkeys of the user and state of
+// the ceremony. The state is kept by the caller.
+func (v *passkeyVerifier) beginAssertion(user webAuthnUser) (json.RawMessage, json.RawMessage, error) {
+	if len(user.passkeys) == 0 {
+		return nil, nil, ErrPasskeyNotFound
+	}
+	assertion, session, err := v.webAuthn.BeginLogin(user,
+		webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		logrus.Errorf("error beginning passkey assertion: %s", err)
+		return nil, nil, ErrInternal
+	}
+
+	options, err := json.Marshal(assertion)
+	if err != nil {
+		logrus.Errorf("error marshaling passkey assertion options: %s", err)
+		return nil, nil, ErrInternal
+	}
+	state, err := json.Marshal(session)
+	if err != nil {
+		logrus.Errorf("error marshaling passkey assertion session: %s", err)
+		return nil, nil, ErrInternal
+	}
+
+	return options, state, nil
+}
+
+// finishAssertion verifies assertion of the user's passkey made in the ceremony
+// started by beginAssertion.
+func (v *passkeyVerifier) finishAssertion(ctx context.Context, user webAuthnUser, state json.RawMessage,
+	response []byte) error {
+	var session webauthn.SessionData
+	if err := json.Unmarshal(state, &session); err != nil {
+		logrus.Errorf("error unmarshaling passkey assertion session: %s", err)
+		return ErrInternal
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		logrus.Errorf("error parsing passkey assertion: %s", err)
+		return ErrPasskeyInvalid
+	}
+	credential, err := v.webAuthn.ValidateLogin(user, session, parsed)
+	if err != nil {
+		logrus.Errorf("error validating passkey assertion: %s", err)
+		return ErrPasskeyInvalid
+	}
+
+	return v.used(ctx, user, credential)
+}
+
+// used checks signature counter of the asserted credential and saves it. Counter
+// which doesn't grow means the authenticator may be cloned.
+func (v *passkeyVerifier) used(ctx context.Context, user webAuthnUser, credential *webauthn.Credential) error {
+	passkey, ok := user.passkey(credential.ID)
+	if !ok {
+		return ErrPasskeyInvalid
+	}
+	if credential.Authenticator.CloneWarning {
+		logrus.Errorf("passkey %s of user %s may be cloned: sign count %d, stored %d", passkey.Id,
+			passkey.UserId, credential.Authenticator.SignCount, passkey.SignCount)
+		return ErrPasskeyInvalid
+	}
+
+	if err := v.passkeysRepo.UpdateSignCount(ctx, passkey.Id, int64(credential.Authenticator.SignCount)); err != nil {
+		logrus.Errorf("error updating passkey sign count in repo: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// reauthenticate confirms that the owner of the session changes sign in methods:
+// password is always asked and the code of the second factor when 2FA is enabled.
+func (s *AuthService) reauthenticate(ctx context.Context, user domain.User, password string, code string) error {
+	if !s.hasher.Check(password, user.Password) {
+		return ErrWrongPassword
+	}
+
+	twoFactor, err := s.getTwoFactor(ctx, user.Id)
+	if err != nil {
+		if errors.Is(ErrTwoFactorNotEnabled, err) {
+			return nil
+		}
+		return err
+	}
+	if !twoFactor.Enabled {
+		return nil
+	}
+
+	return s.verifySecondFactor(ctx, twoFactor, code)
+}
+
+// BeginPasskeyRegistration starts registration of a new passkey. Only the
+// reauthenticated user gets the ceremony, finish step is bound to it.
+func (s *AuthService) BeginPasskeyRegistration(ctx context.Context, userId uuid.UUID, password string,
+	code string) (domain.PasskeyCeremony, error) {
+	var ceremony domain.PasskeyCeremony
+
+	user, err := s.Get(ctx, userId)
+	if err != nil {
+		return ceremony, err
+	}
+	if err := s.reauthenticate(ctx, user, password, code); err != nil {
+		return ceremony, err
+	}
+	waUser, err := s.passkeys.user(ctx, user)
+	if err != nil {
+		return ceremony, err
+	}
+	if len(waUser.passkeys) >= passkeyMaxPasskeys {
+		return ceremony, ErrTooManyPasskeys
+	}
+
+	exclusions := make([]protocol.CredentialDescriptor, len(waUser.passkeys))
+	for i, credential := range waUser.WebAuthnCredentials() {
+		exclusions[i] = credential.Descriptor()
+	}
+	creation, session, err := s.passkeys.webAuthn.BeginRegistration(waUser,
+		webauthn.WithExclusions(exclusions),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			UserVerification:   protocol.VerificationRequired,
+		}))
+	if err != nil {
+		logrus.Errorf("error beginning passkey registration: %s", err)
+		return ceremony, ErrInternal
+	}
+
+	return s.passkeys.saveCeremony(ctx, creation, session)
+}
+
+func (s *AuthService) FinishPasskeyRegistration(ctx context.Context, userId uuid.UUID, token string,
+	name string, response []byte) (domain.Passkey, error) {
+	var passkey domain.Passkey
+
+	if !domain.ValidatePasskeyName(name) {
+		return passkey, ErrInvalidPasskeyName
+	}
+
+	session, err := s.passkeys.takeCeremony(ctx, token)
+	if err != nil {
+		return passkey, err
+	}
+	if !bytes.Equal(session.UserID, userId[:]) {
+		return passkey, ErrTokenInvalid
+	}
+
+	user, err := s.Get(ctx, userId)
+	if err != nil {
+		return passkey, err
+	}
+	waUser, err := s.passkeys.user(ctx, user)
+	if err != nil {
+		return passkey, err
+	}
+	if len(waUser.passkeys) >= passkeyMaxPasskeys {
+		return passkey, ErrTooManyPasskeys
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(response))
+	if err != nil {
+		logrus.Errorf("error parsing passkey attestation: %s", err)
+		return passkey, ErrPasskeyInvalid
+	}
+	credential, err := s.passkeys.webAuthn.CreateCredential(waUser, session, parsed)
+	if err != nil {
+		logrus.Errorf("error validating passkey attestation: %s", err)
+		return passkey, ErrPasskeyInvalid
+	}
+
+	transports := make([]string, len(credential.Transport))
+	for i, transport := range credential.Transport {
+		transports[i] = string(transport)
+	}
+	passkey, err = s.passkeysRepo.Create(ctx, domain.Passkey{
+		UserId:          userId,
+		Name:            name,
+		CredentialId:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       int64(credential.Authenticator.SignCount),
+		Transports:      transports,
+		BackupEligible:  credential.Flags.BackupEligible,
+	})
+	if err != nil {
+		logrus.Errorf("error creating passkey into repo: %s", err)
+		return passkey, ErrInternal
+	}
+
+	if err := s.broker.WritePasskeyAddedTask(ctx, user.Email, passkey.Name); err != nil {
+		logrus.Errorf("error writing passkey added task: %s", err)
+	}
+
+	return passkey, nil
+}
+
+func (s *AuthService) GetPasskeys(ctx context.Context, userId uuid.UUID) ([]domain.Passkey, error) {
+	passkeys, err := s.passkeysRepo.GetAll(ctx, userId)
+	if err != nil {
+		logrus.Errorf("error getting passkeys from repo: %s", err)
+		return nil, ErrInternal
+	}
+	return passkeys, nil
+}
+
+func (s *AuthService) DeletePasskey(ctx context.Context, userId uuid.UUID, id uuid.UUID, password string,
+	code string) error {
+	user, err := s.Get(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if err := s.reauthenticate(ctx, user, password, code); err != nil {
+		return err
+	}
+
+	if err := s.passkeysRepo.Delete(ctx, userId, id); err != nil {
+		logrus.Errorf("error deleting passkey from repo: %s", err)
+		if errors.Is(repository.ErrPasskeyNotFound, err) {
+			return ErrPasskeyNotFound
+		}
+		return ErrInternal
+	}
+	return nil
+}
+
+// BeginPasskeySignIn starts sign in with discoverable passkey, the user is
+// identified by the passkey the browser picks.
+func (s *AuthService) BeginPasskeySignIn(ctx context.Context) (domain.PasskeyCeremony, error) {
+	assertion, session, err := s.passkeys.webAuthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		logrus.Errorf("error beginning passkey sign in: %s", err)
+		return domain.PasskeyCeremony{}, ErrInternal
+	}
+	return s.passkeys.saveCeremony(ctx, assertion, session)
+}
+
+// FinishPasskeySignIn verifies assertion and starts session. Passkey with user
+// verification is a second factor itself, so TOTP isn't asked.
+func (s *AuthService) FinishPasskeySignIn(ctx context.Context, token string, response []byte,
+	device domain.Device) (string, error) {
+	session, err := s.passkeys.takeCeremony(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		logrus.Errorf("error parsing passkey assertion: %s", err)
+		return "", ErrPasskeyInvalid
+	}
+
+	var waUser webAuthnUser
+	credential, err := s.passkeys.webAuthn.ValidateDiscoverableLogin(
+		func(_, userHandle []byte) (webauthn.User, error) {
+			userId, err := uuid.FromBytes(userHandle)
+			if err != nil {
+				return nil, err
+			}
+			user, err := s.Get(ctx, userId)
+			if err != nil {
+				return nil, err
+			}
+			if user.ErasedAt != nil {
+				return nil, ErrUserNotFound
+			}
+			waUser, err = s.passkeys.user(ctx, user)
+			if err != nil {
+				return nil, err
+			}
+			return waUser, nil
+		}, session, parsed)
+	if err != nil {
+		logrus.Errorf("error validating passkey sign in: %s", err)
+		return "", ErrPasskeyInvalid
+	}
+
+	if err := s.passkeys.used(ctx, waUser, credential); err != nil {
+		return "", err
+	}
+
+	return s.startSession(ctx, waUser.user, device)
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+const (
+	testRPID     = "bank.example"
+	testRPOrigin = "https://bank.example"
+	testPassword = "Password123!"
+
+	authenticatorFlagUP = 0x01
+	authenticatorFlagUV = 0x04
+	authenticatorFlagAT = 0x40
+)
+
+// testAuthenticator is a software authenticator with single ES256 credential.
+// It answers options of navigator.credentials the way a browser would.
+type testAuthenticator struct {
+	origin       string
+	key          *ecdsa.PrivateKey
+	credentialId []byte
+	userHandle   []byte
+	signCount    uint32
+}
+
+func newTestAuthenticator(t *testing.T) *testAuthenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating authenticator key: %s", err)
+	}
+	credentialId := make([]byte, 16)
+	if _, err := rand.Read(credentialId); err != nil {
+		t.Fatalf("error generating credential id: %s", err)
+	}
+	return &testAuthenticator{
+		origin:       testRPOrigin,
+		key:          key,
+		credentialId: credentialId,
+	}
+}
+
+type testCeremonyOptions struct {
+	PublicKey struct {
+		Challenge string `json:"challenge"`
+		User      struct {
+			Id string `json:"id"`
+		} `json:"user"`
+	} `json:"publicKey"`
+}
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (a *testAuthenticator) clientData(t *testing.T, typ string, options json.RawMessage) []byte {
+	t.Helper()
+
+	var parsed testCeremonyOptions
+	if err := json.Unmarshal(options, &parsed); err != nil {
+		t.Fatalf("error unmarshaling ceremony options: %s", err)
+	}
+	if parsed.PublicKey.User.Id != "" {
+		userHandle, err := base64.RawURLEncoding.DecodeString(parsed.PublicKey.User.Id)
+		if err != nil {
+			t.Fatalf("error decoding user handle: %s", err)
+		}
+		a.userHandle = userHandle
+	}
+
+	data, err := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": parsed.PublicKey.Challenge,
+		"origin":    a.origin,
+	})
+	if err != nil {
+		t.Fatalf("error marshaling client data: %s", err)
+	}
+	return data
+}
+
+func (a *testAuthenticator) authData(flags byte) []byte {
+	rpIdHash := sha256.Sum256([]byte(testRPID))
+	data := append(rpIdHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, a.signCount)
+}
+
+// create answers registration options with "none" attestation.
+func (a *testAuthenticator) create(t *testing.T, options json.RawMessage) []byte {
+	t.Helper()
+
+	clientData := a.clientData(t, "webauthn.create", options)
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  1,
+		XCoord: a.key.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		t.Fatalf("error marshaling credential public key: %s", err)
+	}
+	authData := a.authData(authenticatorFlagUP | authenticatorFlagUV | authenticatorFlagAT)
+	authData = append(authData, make([]byte, 16)...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialId)))
+	authData = append(authData, a.credentialId...)
+	authData = append(authData, publicKey...)
+
+	attestation, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": authData,
+	})
+	if err != nil {
+		t.Fatalf("error marshaling attestation object: %s", err)
+	}
+
+	response, err := json.Marshal(map[string]interface{}{
+		"id":    b64(a.credentialId),
+		"rawId": b64(a.credentialId),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    b64(clientData),
+			"attestationObject": b64(attestation),
+		},
+	})
+	if err != nil {
+		t.Fatalf("error marshaling attestation response: %s", err)
+	}
+	return response
+}
+
+// get answers assertion options, every assertion increments the signature counter.
+func (a *testAuthenticator) get(t *testing.T, options json.RawMessage) []byte {
+	t.Helper()
+
+	a.signCount++
+	clientData := a.clientData(t, "webauthn.get", options)
+	authData := a.authData(authenticatorFlagUP | authenticatorFlagUV)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatalf("error signing assertion: %s", err)
+	}
+
+	response, err := json.Marshal(map[string]interface{}{
+		"id":    b64(a.credentialId),
+		"rawId": b64(a.credentialId),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    b64(clientData),
+			"authenticatorData": b64(authData),
+			"signature":         b64(signature),
+			"userHandle":        b64(a.userHandle),
+		},
+	})
+	if err != nil {
+		t.Fatalf("error marshaling assertion response: %s", err)
+	}
+	return response
+}
+
+type passkeysTest struct {
+	service  *AuthService
+	user     domain.User
+	passkeys *testPasskeys
+	broker   *testBroker
+}
+
+func newPasskeysTest(t *testing.T) passkeysTest {
+	t.Helper()
+
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          testRPID,
+		RPDisplayName: "Bank",
+		RPOrigins:     []string{testRPOrigin},
+	})
+	if err != nil {
+		t.Fatalf("error creating webauthn: %s", err)
+	}
+
+	user := domain.User{
+		Id:       uuid.New(),
+		Email:    "user@bank.example",
+		Password: testPassword,
+		Name:     "Ivan",
+		Surname:  "Ivanov",
+	}
+	passkeys := &testPasskeys{}
+	broker := &testBroker{}
+	service := NewAuthService(&testUsers{users: map[uuid.UUID]domain.User{user.Id: user}},
+		&testTwoFactor{}, &testSessions{}, passkeys, newTestRedis(t), testTokens{}, testHasher{}, nil,
+		broker, webAuthn, AuthConfig{
+			PasskeyCeremonyTTL: time.Minute,
+			SessionTTL:         time.Hour,
+		})
+
+	return passkeysTest{
+		service:  service,
+		user:     user,
+		passkeys: passkeys,
+		broker:   broker,
+	}
+}
+
+func (p passkeysTest) register(t *testing.T, authenticator *testAuthenticator) (domain.Passkey, error) {
+	t.Helper()
+
+	ctx := context.Background()
+	ceremony, err := p.service.BeginPasskeyRegistration(ctx, p.user.Id, testPassword, "")
+	if err != nil {
+		t.Fatalf("error beginning passkey registration: %s", err)
+	}
+	return p.service.FinishPasskeyRegistration(ctx, p.user.Id, ceremony.Token, "Laptop",
+		authenticator.create(t, ceremony.Options))
+}
+
+func (p passkeysTest) signIn(t *testing.T, authenticator *testAuthenticator) (string, error) {
+	t.Helper()
+
+	ctx := context.Background()
+	ceremony, err := p.service.BeginPasskeySignIn(ctx)
+	if err != nil {
+		t.Fatalf("error beginning passkey sign in: %s", err)
+	}
+	return p.service.FinishPasskeySignIn(ctx, ceremony.Token, authenticator.get(t, ceremony.Options),
+		domain.Device{UserAgent: "test"})
+}
+
+func TestPasskeyRegistrationAndSignIn(t *testing.T) {
+	p := newPasskeysTest(t)
+	authenticator := newTestAuthenticator(t)
+
+	passkey, err := p.register(t, authenticator)
+	if err != nil {
+		t.Fatalf("register: unexpected error: %s", err)
+	}
+	if passkey.UserId != p.user.Id || passkey.Name != "Laptop" {
+		t.Fatalf("register: unexpected passkey %+v", passkey)
+	}
+	if len(p.broker.passkeysAdded) != 1 || p.broker.passkeysAdded[0] != p.user.Email+":Laptop" {
+		t.Fatalf("register: user isn't notified, tasks %v", p.broker.passkeysAdded)
+	}
+
+	for i := 1; i <= 2; i++ {
+		token, err := p.signIn(t, authenticator)
+		if err != nil {
+			t.Fatalf("sign in %d: unexpected error: %s", i, err)
+		}
+		if token == "" {
+			t.Fatalf("sign in %d: empty token", i)
+		}
+		if signCount := p.passkeys.passkeys[0].SignCount; signCount != int64(i) {
+			t.Fatalf("sign in %d: sign count %d is not saved", i, signCount)
+		}
+	}
+}
+
+func TestPasskeySignInRejectsCloned(t *testing.T) {
+	p := newPasskeysTest(t)
+	authenticator := newTestAuthenticator(t)
+	if _, err := p.register(t, authenticator); err != nil {
+		t.Fatalf("register: unexpected error: %s", err)
+	}
+	if _, err := p.signIn(t, authenticator); err != nil {
+		t.Fatalf("sign in: unexpected error: %s", err)
+	}
+
+	// the clone has the same key, but its counter didn't move with the original
+	clone := *authenticator
+	clone.signCount = 0
+	if _, err := p.signIn(t, &clone); !errors.Is(err, ErrPasskeyInvalid) {
+		t.Fatalf("sign in with clone: expected %s, got %v", ErrPasskeyInvalid, err)
+	}
+	if signCount := p.passkeys.passkeys[0].SignCount; signCount != 1 {
+		t.Fatalf("sign count changed to %d by the clone", signCount)
+	}
+}
+
+func TestPasskeyWrongOrigin(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, p passkeysTest, authenticator *testAuthenticator) error
+	}{
+		{
+			name: "registration",
+			run: func(t *testing.T, p passkeysTest, authenticator *testAuthenticator) error {
+				authenticator.origin = "https://evil.example"
+				_, err := p.register(t, authenticator)
+				return err
+			},
+		},
+		{
+			name: "sign in",
+			run: func(t *testing.T, p passkeysTest, authenticator *testAuthenticator) error {
+				if _, err := p.register(t, authenticator); err != nil {
+					t.Fatalf("register: unexpected error: %s", err)
+				}
+				authenticator.origin = "https://evil.example"
+				_, err := p.signIn(t, authenticator)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPasskeysTest(t)
+			err := tt.run(t, p, newTestAuthenticator(t))
+			if !errors.Is(err, ErrPasskeyInvalid) {
+				t.Fatalf("expected %s, got %v", ErrPasskeyInvalid, err)
+			}
+		})
+	}
+}
+
+func TestPasskeyManagementRequiresPassword(t *testing.T) {
+	p := newPasskeysTest(t)
+	ctx := context.Background()
+
+	if _, err := p.service.BeginPasskeyRegistration(ctx, p.user.Id, "wrong", ""); !errors.Is(err, ErrWrongPassword) {
+		t.Fatalf("begin registration: expected %s, got %v", ErrWrongPassword, err)
+	}
+
+	passkey, err := p.register(t, newTestAuthenticator(t))
+	if err != nil {
+		t.Fatalf("register: unexpected error: %s", err)
+	}
+	if err := p.service.DeletePasskey(ctx, p.user.Id, passkey.Id, "wrong", ""); !errors.Is(err, ErrWrongPassword) {
+		t.Fatalf("delete: expected %s, got %v", ErrWrongPassword, err)
+	}
+	if err := p.service.DeletePasskey(ctx, p.user.Id, passkey.Id, testPassword, ""); err != nil {
+		t.Fatalf("delete: unexpected error: %s", err)
+	}
+	if len(p.passkeys.passkeys) != 0 {
+		t.Fatalf("passkey isn't deleted")
+	}
+}
@@ -0,0 +1,511 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/broker"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/hasher"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	dataExportKey      = "export:%s"
+	dataExportLimitKey = "export:limit:%s"
+)
+
+const exportTaskWaitTimeout = 5 * time.Second
+
+type PrivacyConfig struct {
+	ExportTTL    time.Duration
+	ExportLimit  int
+	ExportWindow time.Duration
+}
+
+type PrivacyService struct {
+	usersRepo          repository.Users
+	accountsRepo       repository.Accounts
+	apiKeysRepo        repository.ApiKeys
+	twoFactorRepo      repository.TwoFactor
+	sessionsRepo       repository.Sessions
+	passkeysRepo       repository.Passkeys
+	cardsRepo          repository.Cards
+	cashoutCodesRepo   repository.CashoutCodes
+	journalRepo        repository.MachineJournal
+	rdb                *redis.Client
+	hasher             hasher.HasherInterface
+	transactionManager transactions.ManagerInterface
+	broker             broker.BrokerInterface
+	cfg                PrivacyConfig
+}
+
+func NewPrivacyService(usersRepo repository.Users, accountsRepo repository.Accounts,
+	apiKeysRepo repository.ApiKeys, twoFactorRepo repository.TwoFactor,
+	sessionsRepo repository.Sessions, passkeysRepo repository.Passkeys, cardsRepo repository.Cards,
+	cashoutCodesRepo repository.CashoutCodes, journalRepo repository.MachineJournal, rdb *redis.Client,
+	hasher hasher.HasherInterface, transactionManager transactions.ManagerInterface,
+	broker broker.BrokerInterface, cfg PrivacyConfig) *PrivacyService {
+	return &PrivacyService{
+		usersRepo:          usersRepo,
+		accountsRepo:       accountsRepo,
+		apiKeysRepo:        apiKeysRepo,
+		twoFactorRepo:      twoFactorRepo,
+		sessionsRepo:       sessionsRepo,
+		passkeysRepo:       passkeysRepo,
+		cardsRepo:          cardsRepo,
+		cashoutCodesRepo:   cashoutCodesRepo,
+		journalRepo:        journalRepo,
+		rdb:                rdb,
+		hasher:             hasher,
+		transactionManager: transactionManager,
+		broker:             broker,
+		cfg:                cfg,
+	}
+}
+
+func (s *PrivacyService) getUser(ctx context.Context, id uuid.UUID) (domain.User, error) {
+	user, err := s.usersRepo.Get(ctx, id)
+	if err != nil {
+		logrus.Errorf("error getting user from repo in privacy service: %s", err)
+		if errors.Is(repository.ErrUserNotFound, err) {
+			return user, ErrUserNotFound
+		}
+		return user, ErrInternal
+	}
+	return user, nil
+}
+
+func (s *PrivacyService) saveExport(ctx context.Context, export domain.DataExport) error {
+	data, err := json.Marshal(export)
+	if err != nil {
+		logrus.Errorf("error marshaling data export: %s", err)
+		return ErrInternal
+	}
+	err = s.rdb.Set(ctx, fmt.Sprintf(dataExportKey, export.Id), data, s.cfg.ExportTTL).Err()
+	if err != nil {
+		logrus.Errorf("error saving data export into redis: %s", err)
+		return ErrInternal
+	}
+	return nil
+}
+
+func (s *PrivacyService) loadExport(ctx context.Context, id uuid.UUID) (domain.DataExport, error) {
+	var export domain.DataExport
+
+	data, err := s.rdb.Get(ctx, fmt.Sprintf(dataExportKey, id)).Bytes()
+	if err != nil {
+		if errors.Is(redis.Nil, err) {
+			return export, ErrExportNotFound
+		}
+		logrus.Errorf("error getting data export from redis: %s", err)
+		return export, ErrInternal
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		logrus.Errorf("error unmarshaling data export: %s", err)
+		return export, ErrInternal
+	}
+	return export, nil
+}
+
+// RequestExport enqueues building of the archive with all personal data of the
+// user. The archive can be downloaded until ExportTTL passes.
+func (s *PrivacyService) RequestExport(ctx context.Context, userId uuid.UUID) (uuid.UUID, error) {
+	limited, err := hitRateLimit(ctx, s.rdb, fmt.Sprintf(dataExportLimitKey, userId),
+		s.cfg.ExportLimit, s.cfg.ExportWindow)
+	if err != nil {
+		logrus.Errorf("error checking data export limit: %s", err)
+		return uuid.UUID{}, ErrInternal
+	}
+	if limited {
+		return uuid.UUID{}, ErrTooManyRequests
+	}
+
+	export := domain.DataExport{
+		Id:        uuid.New(),
+		UserId:    userId,
+		Status:    domain.DataExportPending,
+		CreatedAt: time.Now(),
+	}
+	if err := s.saveExport(ctx, export); err != nil {
+		return uuid.UUID{}, err
+	}
+	if err := s.broker.WriteExportTask(ctx, export.Id); err != nil {
+		return uuid.UUID{}, ErrInternal
+	}
+
+	return export.Id, nil
+}
+
+func (s *PrivacyService) GetExport(ctx context.Context, userId uuid.UUID,
+	id uuid.UUID) (domain.DataExport, error) {
+	export, err := s.loadExport(ctx, id)
+	if err != nil {
+		return export, err
+	}
+	if export.UserId != userId {
+		return domain.DataExport{}, ErrExportNotFound
+	}
+	return export, nil
+}
+
+// RunExportWorker builds requested archives until ctx is done.
+func (s *PrivacyService) RunExportWorker(ctx context.Context) {
+	for ctx.Err() == nil {
+		id, err := s.broker.ReadExportTask(ctx, exportTaskWaitTimeout)
+		if err != nil {
+			// back off when redis is unavailable
+			if !errors.Is(broker.ErrNoTask, err) {
+				select {
+				case <-ctx.Done():
+				case <-time.After(exportTaskWaitTimeout):
+				}
+			}
+			continue
+		}
+		if err := s.BuildExport(ctx, id); err != nil {
+			logrus.Errorf("error building data export %s: %s", id, err)
+		}
+	}
+}
+
+func (s *PrivacyService) BuildExport(ctx context.Context, id uuid.UUID) error {
+	export, err := s.loadExport(ctx, id)
+	if err != nil {
+		return err
+	}
+	if export.Status != domain.DataExportPending {
+		return nil
+	}
+
+	user, err := s.getUser(ctx, export.UserId)
+	if err != nil {
+		return err
+	}
+
+	archive, err := s.buildArchive(ctx, user)
+	if err != nil {
+		export.Status = domain.DataExportFailed
+		s.saveExport(ctx, export)
+		return err
+	}
+
+	export.Status = domain.DataExportReady
+	export.Archive = archive
+	if err := s.saveExport(ctx, export); err != nil {
+		return err
+	}
+
+	if err := s.broker.WriteExportReadyTask(ctx, user.Email, export.Id); err != nil {
+		logrus.Errorf("error writing export ready task: %s", err)
+	}
+
+	return nil
+}
+
+type archiveProfile struct {
+	Id       uuid.UUID `json:"id"`
+	Surname  string    `json:"surname"`
+	Name     string    `json:"name"`
+	Patronyc string    `json:"patronyc"`
+	Email    string    `json:"email"`
+	Verified bool      `json:"verified"`
+	Role     string    `json:"role"`
+}
+
+type archiveAccount struct {
+	Id     uuid.UUID `json:"id"`
+	Money  int       `json:"money"`
+	Frozen bool      `json:"frozen"`
+}
+
+type archiveMovement struct {
+	Id         uuid.UUID `json:"id"`
+	AccountId  uuid.UUID `json:"accountId"`
+	MachineId  uuid.UUID `json:"machineId"`
+	Operation  string    `json:"operation"`
+	Amount     int       `json:"amount"`
+	ThirdParty bool      `json:"thirdParty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type archiveNotification struct {
+	Kind   string    `json:"kind"`
+	SentAt time.Time `json:"sentAt"`
+}
+
+type archiveApiKey struct {
+	Id         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	AccountId  *uuid.UUID `json:"accountId,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type archiveSession struct {
+	Id         uuid.UUID  `json:"id"`
+	UserAgent  string     `json:"userAgent"`
+	Ip         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastSeenAt time.Time  `json:"lastSeenAt"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+type archivePasskey struct {
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+type archiveCard struct {
+	Id        uuid.UUID `json:"id"`
+	AccountId uuid.UUID `json:"accountId"`
+	Last4     string    `json:"last4"`
+	Expiry    string    `json:"expiry"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type archive struct {
+	GeneratedAt      time.Time             `json:"generatedAt"`
+	Profile          archiveProfile        `json:"profile"`
+	Accounts         []archiveAccount      `json:"accounts"`
+	Movements        []archiveMovement     `json:"movements"`
+	ApiKeys          []archiveApiKey       `json:"apiKeys"`
+	Sessions         []archiveSession      `json:"sessions"`
+	Passkeys         []archivePasskey      `json:"passkeys"`
+	Cards            []archiveCard         `json:"cards"`
+	Notifications    []archiveNotification `json:"notifications"`
+	TwoFactorEnabled bool                  `json:"twoFactorEnabled"`
+}
+
+func (s *PrivacyService) buildArchive(ctx context.Context, user domain.User) ([]byte, error) {
+	data := archive{
+		GeneratedAt: time.Now(),
+		Profile: archiveProfile{
+			Id:       user.Id,
+			Surname:  user.Surname,
+			Name:     user.Name,
+			Patronyc: user.Patronyc,
+			Email:    user.Email,
+			Verified: user.Verified,
+			Role:     user.Role,
+		},
+		Accounts:      []archiveAccount{},
+		Movements:     []archiveMovement{},
+		ApiKeys:       []archiveApiKey{},
+		Sessions:      []archiveSession{},
+		Passkeys:      []archivePasskey{},
+		Cards:         []archiveCard{},
+		Notifications: []archiveNotification{},
+	}
+
+	accounts, err := s.accountsRepo.GetAll(ctx, user.Id)
+	if err != nil {
+		logrus.Errorf("error getting accounts from repo when building export: %s", err)
+		return nil, ErrInternal
+	}
+	for _, account := range accounts {
+		data.Accounts = append(data.Accounts, archiveAccount{
+			Id:     account.Id,
+			Money:  account.Money,
+			Frozen: account.Frozen,
+		})
+
+		entries, err := s.journalRepo.GetAllByAccount(ctx, account.Id, movementOperations)
+		if err != nil {
+			logrus.Errorf("error getting movements from repo when building export: %s", err)
+			return nil, ErrInternal
+		}
+		for _, entry := range entries {
+			data.Movements = append(data.Movements, archiveMovement{
+				Id:         entry.Id,
+				AccountId:  entry.AccountId,
+				MachineId:  entry.MachineId,
+				Operation:  entry.Operation,
+				Amount:     entry.Amount,
+				ThirdParty: entry.ThirdParty,
+				CreatedAt:  entry.CreatedAt,
+			})
+		}
+	}
+
+	keys, err := s.apiKeysRepo.GetAll(ctx, user.Id)
+	if err != nil {
+		logrus.Errorf("error getting api keys from repo when building export: %s", err)
+		return nil, ErrInternal
+	}
+	for _, key := range keys {
+		apiKey := archiveApiKey{
+			Id:         key.Id,
+			Name:       key.Name,
+			Scopes:     key.Scopes,
+			ExpiresAt:  key.ExpiresAt,
+			LastUsedAt: key.LastUsedAt,
+			CreatedAt:  key.CreatedAt,
+		}
+		if key.AccountId.Valid {
+			apiKey.AccountId = &key.AccountId.UUID
+		}
+		data.ApiKeys = append(data.ApiKeys, apiKey)
+	}
+
+	sessions, err := s.sessionsRepo.GetAll(ctx, user.Id)
+	if err != nil {
+		logrus.Errorf("error getting sessions from repo when building export: %s", err)
+		return nil, ErrInternal
+	}
+	for _, session := range sessions {
+		data.Sessions = append(data.Sessions, archiveSession{
+			Id:         session.Id,
+			UserAgent:  session.UserAgent,
+			Ip:         session.Ip,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			RevokedAt:  session.RevokedAt,
+		})
+	}
+
+	passkeys, err := s.passkeysRepo.GetAll(ctx, user.Id)
+	if err != nil {
+		logrus.Errorf("error getting passkeys from repo when building export: %s", err)
+		return nil, ErrInternal
+	}
+	for _, passkey := range passkeys {
+		data.Passkeys = append(data.Passkeys, archivePasskey{
+			Name:       passkey.Name,
+			CreatedAt:  passkey.CreatedAt,
+			LastUsedAt: passkey.LastUsedAt,
+		})
+	}
+
+	cards, err := s.cardsRepo.GetAll(ctx, user.Id)
+	if err != nil {
+		logrus.Errorf("error getting cards from repo when building export: %s", err)
+		return nil, ErrInternal
+	}
+	for _, card := range cards {
+		data.Cards = append(data.Cards, archiveCard{
+			Id:        card.Id,
+			AccountId: card.AccountId,
+			Last4:     card.Last4,
+			Expiry:    card.Expiry(),
+			Status:    card.Status,
+			CreatedAt: card.CreatedAt,
+		})
+	}
+
+	notifications, err := s.broker.GetNotifications(ctx, user.Email)
+	if err != nil {
+		logrus.Errorf("error getting notifications when building export: %s", err)
+		return nil, ErrInternal
+	}
+	for _, notification := range notifications {
+		data.Notifications = append(data.Notifications, archiveNotification{
+			Kind:   notification.Kind,
+			SentAt: notification.SentAt,
+		})
+	}
+
+	twoFactor, err := s.twoFactorRepo.Get(ctx, user.Id)
+	if err != nil && !errors.Is(repository.ErrTwoFactorNotFound, err) {
+		logrus.Errorf("error getting two factor from repo when building export: %s", err)
+		return nil, ErrInternal
+	}
+	data.TwoFactorEnabled = err == nil && twoFactor.Enabled
+
+	result, err := json.Marshal(data)
+	if err != nil {
+		logrus.Errorf("error marshaling export archive: %s", err)
+		return nil, ErrInternal
+	}
+	return result, nil
+}
+
+// EraseAccount closes relationship of the user with the bank. Personal data is
+// pseudonymized, accounts are kept frozen for regulators and all credentials
+// are revoked. It's refused while any account holds money, including money
+// reserved by cashout codes.
+func (s *PrivacyService) EraseAccount(ctx context.Context, userId uuid.UUID, password string) error {
+	user, err := s.getUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if !s.hasher.Check(password, user.Password) {
+		return ErrWrongPassword
+	}
+
+	err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		// accounts are locked, so money can't come or be reserved by cashout
+		// code after the check
+		accounts, err := s.accountsRepo.GetAllForUpdate(ctx, userId)
+		if err != nil {
+			return err
+		}
+		for _, account := range accounts {
+			if account.Money != 0 {
+				return ErrAccountHasMoney
+			}
+		}
+		cashoutCodes, err := s.cashoutCodesRepo.GetPending(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if len(cashoutCodes) != 0 {
+			return ErrAccountHasMoney
+		}
+
+		frozen := true
+		for _, account := range accounts {
+			_, err := s.accountsRepo.Update(ctx, account.Id, domain.AccountUpdate{
+				Frozen: &frozen,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if err := s.apiKeysRepo.DeleteAll(ctx, userId); err != nil {
+			return err
+		}
+		if err := s.twoFactorRepo.Delete(ctx, userId); err != nil {
+			return err
+		}
+		if err := s.passkeysRepo.DeleteAll(ctx, userId); err != nil {
+			return err
+		}
+		if err := s.cardsRepo.BlockAll(ctx, userId); err != nil {
+			return err
+		}
+		return s.usersRepo.Erase(ctx, userId)
+	})
+	if err != nil {
+		if errors.Is(ErrAccountHasMoney, err) {
+			return err
+		}
+		logrus.Errorf("error erasing user in transaction: %s", err)
+		return ErrInternal
+	}
+
+	if err := s.broker.DeleteNotifications(ctx, user.Email); err != nil {
+		logrus.Errorf("error deleting notifications of erased user: %s", err)
+	}
+
+	if err := revokeSessions(ctx, s.rdb, s.sessionsRepo, userId); err != nil {
+		return err
+	}
+
+	if err := s.broker.WriteAccountErasedTask(ctx, user.Email); err != nil {
+		logrus.Errorf("error writing account erased task: %s", err)
+	}
+
+	return nil
+}
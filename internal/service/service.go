@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/IvanMeln1k/go-bank-app-bank/internal/broker"
 	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
@@ -10,52 +11,211 @@ import (
 	"github.com/IvanMeln1k/go-bank-app-bank/pkg/hasher"
 	"github.com/IvanMeln1k/go-bank-app-bank/pkg/tokens"
 	"github.com/IvanMeln1k/go-bank-app-bank/pkg/transactions"
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
 var (
-	ErrInternal               = errors.New("error internal")
-	ErrUserNotFound           = errors.New("user not found")
-	ErrAccountNotFound        = errors.New("account not found")
-	ErrInvalidEmailOrPassword = errors.New("invalid email or password")
-	ErrMachineNotFound        = errors.New("machine not found")
-	ErrEmailAlreadyInUse      = errors.New("email already in use")
-	ErrTokenExpired           = errors.New("token is expired")
-	ErrTokenInvalid           = errors.New("token is invalid")
-	ErrEmailNotVerified       = errors.New("email not verified")
-	ErrInsufficientFunds      = errors.New("insufficient funds in the account")
-	ErrTooManyAccounts        = errors.New("accounts can't be more 3")
-	ErrEmailAlreadyVerified   = errors.New("email already verified")
+	ErrInternal                = errors.New("error internal")
+	ErrUserNotFound            = errors.New("user not found")
+	ErrAccountNotFound         = errors.New("account not found")
+	ErrInvalidEmailOrPassword  = errors.New("invalid email or password")
+	ErrMachineNotFound         = errors.New("machine not found")
+	ErrEmailAlreadyInUse       = errors.New("email already in use")
+	ErrTokenExpired            = errors.New("token is expired")
+	ErrTokenInvalid            = errors.New("token is invalid")
+	ErrEmailNotVerified        = errors.New("email not verified")
+	ErrInsufficientFunds       = errors.New("insufficient funds in the account")
+	ErrTooManyAccounts         = errors.New("accounts can't be more 3")
+	ErrEmailAlreadyVerified    = errors.New("email already verified")
+	ErrSessionRevoked          = errors.New("session is revoked")
+	ErrTooManyRequests         = errors.New("too many requests")
+	ErrInvalidName             = errors.New("invalid name")
+	ErrInvalidEmail            = errors.New("invalid email")
+	ErrInvalidPassword         = errors.New("invalid password")
+	ErrWrongPassword           = errors.New("wrong password")
+	ErrInvalidCode             = errors.New("invalid code")
+	ErrTwoFactorNotEnabled     = errors.New("two factor authentication not enabled")
+	ErrTwoFactorAlreadyEnabled = errors.New("two factor authentication already enabled")
+	ErrAccountFrozen           = errors.New("account is frozen")
+	ErrInvalidRole             = errors.New("invalid role")
+	ErrApiKeyNotFound          = errors.New("api key not found")
+	ErrInvalidApiKeyName       = errors.New("invalid api key name")
+	ErrInvalidScopes           = errors.New("invalid scopes")
+	ErrInvalidExpiry           = errors.New("expiry must be in the future")
+	ErrTooManyApiKeys          = errors.New("too many api keys")
+	ErrExportNotFound          = errors.New("data export not found")
+	ErrAccountHasMoney         = errors.New("account holds money")
+	ErrSessionNotFound         = errors.New("session not found")
+	ErrOperationNotFound       = errors.New("pending operation not found")
+	ErrPasskeyNotFound         = errors.New("passkey not found")
+	ErrInvalidPasskeyName      = errors.New("invalid passkey name")
+	ErrTooManyPasskeys         = errors.New("too many passkeys")
+	ErrPasskeyInvalid          = errors.New("passkey verification failed")
+	ErrMachineNotActive        = errors.New("machine is not active")
+	ErrOperationNotSupported   = errors.New("operation is not supported by machine")
+	ErrMachineDecommissioned   = errors.New("machine is decommissioned")
+	ErrInvalidMachine          = errors.New("invalid machine data")
+	ErrAmountNotDispensable    = errors.New("amount can't be dispensed by machine")
+	ErrInvalidNotes            = errors.New("invalid notes")
+	ErrInvalidHeartbeat        = errors.New("invalid heartbeat")
+	ErrCardNotFound            = errors.New("card not found")
+	ErrCardInvalid             = errors.New("card is invalid")
+	ErrCardBlocked             = errors.New("card is blocked")
+	ErrCardExpired             = errors.New("card is expired")
+	ErrInvalidPin              = errors.New("invalid pin")
+	ErrWrongPin                = errors.New("wrong pin")
+	ErrPinLocked               = errors.New("pin is locked after too many wrong attempts")
+	ErrTooManyCards            = errors.New("too many cards")
+	ErrMachineSessionNotFound  = errors.New("machine session not found or expired")
+	ErrMachineBusy             = errors.New("machine is serving another card")
+	ErrInvalidAmount           = errors.New("invalid amount")
+	ErrCashoutCodeNotFound     = errors.New("cashout code not found")
+	ErrCashoutCodeInvalid      = errors.New("cashout code or secret is invalid")
+	ErrTooManyCashoutCodes     = errors.New("too many cashout codes")
+	ErrDuplicateTransaction    = errors.New("transaction with the reference already made")
+	ErrOriginalNotFound        = errors.New("original transaction not found")
+	ErrInvalidSearch           = errors.New("invalid search")
+	ErrDepositLimitExceeded    = errors.New("deposit exceeds limit")
+	ErrRecipientUnavailable    = errors.New("account can't receive deposit")
 )
 
 type Auth interface {
 	SignUp(ctx context.Context, user domain.User) (uuid.UUID, error)
-	SignIn(ctx context.Context, email string, password string) (string, error)
+	SignIn(ctx context.Context, email string, password string, device domain.Device) (domain.SignInResult, error)
+	SignInTwoFactor(ctx context.Context, challengeToken string, code string, device domain.Device) (string, error)
 	SendEmailVerificationMessage(ctx context.Context, id uuid.UUID) error
 	VerifyEmail(ctx context.Context, token string) error
 	Get(ctx context.Context, id uuid.UUID) (domain.User, error)
+	Authenticate(ctx context.Context, accessToken string) (domain.Principal, error)
+	RequestPasswordReset(ctx context.Context, email string, ip string) error
+	ConfirmPasswordReset(ctx context.Context, token string, password string) error
+	UpdateProfile(ctx context.Context, id uuid.UUID, data domain.UserUpdate) (domain.User, error)
+	ConfirmEmailChange(ctx context.Context, token string) error
+	ChangePassword(ctx context.Context, id uuid.UUID, currentPassword string,
+		newPassword string, device domain.Device) (string, error)
+	EnrollTwoFactor(ctx context.Context, userId uuid.UUID) (domain.TwoFactorEnrollment, error)
+	ConfirmTwoFactor(ctx context.Context, userId uuid.UUID, code string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, userId uuid.UUID, code string) error
+	GetSessions(ctx context.Context, userId uuid.UUID) ([]domain.Session, error)
+	RevokeSession(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+	RequestMagicLink(ctx context.Context, email string, ip string) (string, error)
+	SignInMagicLink(ctx context.Context, token string, nonce string,
+		device domain.Device) (domain.SignInResult, error)
+	BeginPasskeyRegistration(ctx context.Context, userId uuid.UUID, password string,
+		code string) (domain.PasskeyCeremony, error)
+	FinishPasskeyRegistration(ctx context.Context, userId uuid.UUID, token string, name string,
+		response []byte) (domain.Passkey, error)
+	GetPasskeys(ctx context.Context, userId uuid.UUID) ([]domain.Passkey, error)
+	DeletePasskey(ctx context.Context, userId uuid.UUID, id uuid.UUID, password string, code string) error
+	BeginPasskeySignIn(ctx context.Context) (domain.PasskeyCeremony, error)
+	FinishPasskeySignIn(ctx context.Context, token string, response []byte,
+		device domain.Device) (string, error)
 }
 
 type Accounts interface {
 	Create(ctx context.Context, userId uuid.UUID, account domain.Account) (uuid.UUID, error)
 	Get(ctx context.Context, userId uuid.UUID, id uuid.UUID) (domain.Account, error)
 	GetAll(ctx context.Context, userId uuid.UUID) ([]domain.Account, error)
+	GetMovements(ctx context.Context, userId uuid.UUID, id uuid.UUID, limit int,
+		offset int) ([]domain.JournalEntry, error)
 	Delete(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
-	Transfer(ctx context.Context, userId uuid.UUID, id uuid.UUID, to uuid.UUID, amount int) error
+	Transfer(ctx context.Context, userId uuid.UUID, id uuid.UUID, to uuid.UUID,
+		amount int) (domain.TransferResult, error)
+	ConfirmTransfer(ctx context.Context, userId uuid.UUID, id uuid.UUID, proof domain.StepUpProof) error
 }
 
 type Machines interface {
-	CashOut(ctx context.Context, id uuid.UUID, userId uuid.UUID, accountId uuid.UUID,
-		amount int) error
-	Deposit(ctx context.Context, id uuid.UUID, userId uuid.UUID, accountId uuid.UUID,
-		amount int) error
+	OpenMachineSession(ctx context.Context, id uuid.UUID, card domain.Card) (domain.MachineSession, error)
+	OpenGatewaySession(ctx context.Context, id uuid.UUID, card domain.Card) (domain.MachineSession, error)
+	MachineBalance(ctx context.Context, id uuid.UUID, sessionId uuid.UUID) (domain.Account, error)
+	MachineMiniStatement(ctx context.Context, id uuid.UUID, sessionId uuid.UUID) ([]domain.JournalEntry, error)
+	MachineCashOut(ctx context.Context, id uuid.UUID, sessionId uuid.UUID, amount int,
+		reference string) ([]domain.Note, error)
+	ReverseMachineCashOut(ctx context.Context, id uuid.UUID, reference string) error
+	MachineDeposit(ctx context.Context, id uuid.UUID, sessionId uuid.UUID, amount int,
+		notes []domain.Note) error
+	CloseMachineSession(ctx context.Context, id uuid.UUID, sessionId uuid.UUID) error
+	RunSessionSweeper(ctx context.Context)
+	CreateCashoutCode(ctx context.Context, userId uuid.UUID, accountId uuid.UUID,
+		amount int) (domain.IssuedCashoutCode, error)
+	GetCashoutCodes(ctx context.Context, userId uuid.UUID) ([]domain.CashoutCode, error)
+	CancelCashoutCode(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+	RedeemCashoutCode(ctx context.Context, id uuid.UUID, code string, secret string) ([]domain.Note, error)
+	RunCashoutCodeSweeper(ctx context.Context)
+	AuthenticateMachine(ctx context.Context, machineToken string) (uuid.UUID, error)
+	IssueToken(ctx context.Context, id uuid.UUID) (string, error)
+	Heartbeat(ctx context.Context, heartbeat domain.MachineHeartbeat) error
+	RunHealthMonitor(ctx context.Context)
+	FindMachines(ctx context.Context, search domain.MachineSearch) ([]domain.NearbyMachine, error)
+	GetDepositRecipient(ctx context.Context, id uuid.UUID, accountId uuid.UUID) (domain.DepositRecipient, error)
+	MachineThirdPartyDeposit(ctx context.Context, id uuid.UUID, accountId uuid.UUID, amount int,
+		notes []domain.Note) error
+}
+
+type Cards interface {
+	IssueCard(ctx context.Context, userId uuid.UUID, accountId uuid.UUID, pin string) (domain.IssuedCard, error)
+	GetCards(ctx context.Context, userId uuid.UUID) ([]domain.Card, error)
+	BlockCard(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+	SetCardPin(ctx context.Context, userId uuid.UUID, id uuid.UUID, password string, pin string) error
+	AuthenticateCard(ctx context.Context, credentials domain.CardCredentials) (domain.Card, error)
+}
+
+// Admin is used by staff. Every action is recorded into audit log on behalf of actorId.
+type Admin interface {
+	SearchUsers(ctx context.Context, actorId uuid.UUID, query string, limit int,
+		offset int) ([]domain.User, error)
+	GetUserAccounts(ctx context.Context, actorId uuid.UUID, userId uuid.UUID) ([]domain.Account, error)
+	FreezeAccount(ctx context.Context, actorId uuid.UUID, accountId uuid.UUID) error
+	UnfreezeAccount(ctx context.Context, actorId uuid.UUID, accountId uuid.UUID) error
+	ResendVerification(ctx context.Context, actorId uuid.UUID, userId uuid.UUID) error
+	SetRole(ctx context.Context, actorId uuid.UUID, userId uuid.UUID, role string) error
+	CreateMachine(ctx context.Context, actorId uuid.UUID, machine domain.Machine) (domain.Machine, string, error)
+	GetMachines(ctx context.Context, status string, limit int, offset int) ([]domain.Machine, error)
+	UpdateMachine(ctx context.Context, actorId uuid.UUID, id uuid.UUID,
+		data domain.MachineUpdate) (domain.Machine, error)
+	DecommissionMachine(ctx context.Context, actorId uuid.UUID, id uuid.UUID) error
+	ResetMachineToken(ctx context.Context, actorId uuid.UUID, id uuid.UUID) (string, error)
+	GetCassettes(ctx context.Context, machineId uuid.UUID) ([]domain.Cassette, error)
+	ReplenishCassettes(ctx context.Context, actorId uuid.UUID, machineId uuid.UUID,
+		notes []domain.Note) ([]domain.Cassette, error)
+	CountCassettes(ctx context.Context, actorId uuid.UUID, machineId uuid.UUID,
+		cassettes []domain.Cassette) ([]domain.Cassette, error)
+	GetFleetHealth(ctx context.Context) (domain.FleetHealth, error)
+	SettleMachine(ctx context.Context, actorId uuid.UUID, machineId uuid.UUID,
+		counted []domain.Note) (domain.MachineSettlement, error)
+	GetMachineSettlements(ctx context.Context, machineId uuid.UUID, limit int,
+		offset int) ([]domain.MachineSettlement, error)
+	GetSettlements(ctx context.Context, discrepancyOnly bool, limit int,
+		offset int) ([]domain.MachineSettlement, error)
+	GetMachineJournal(ctx context.Context, machineId uuid.UUID, from time.Time, to time.Time, limit int,
+		offset int) ([]domain.JournalEntry, error)
+}
+
+type ApiKeys interface {
+	CreateApiKey(ctx context.Context, userId uuid.UUID, key domain.ApiKey) (domain.ApiKey, string, error)
+	GetApiKeys(ctx context.Context, userId uuid.UUID) ([]domain.ApiKey, error)
+	DeleteApiKey(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+	AuthenticateApiKey(ctx context.Context, secret string) (domain.Principal, error)
+}
+
+type Privacy interface {
+	RequestExport(ctx context.Context, userId uuid.UUID) (uuid.UUID, error)
+	GetExport(ctx context.Context, userId uuid.UUID, id uuid.UUID) (domain.DataExport, error)
+	BuildExport(ctx context.Context, id uuid.UUID) error
+	RunExportWorker(ctx context.Context)
+	EraseAccount(ctx context.Context, userId uuid.UUID, password string) error
 }
 
 type Service struct {
 	Auth
 	Accounts
 	Machines
+	Cards
+	Admin
+	ApiKeys
+	Privacy
 }
 
 type Deps struct {
@@ -65,15 +225,37 @@ type Deps struct {
 	Hasher             hasher.HasherInterface
 	TransactionManager transactions.ManagerInterface
 	Broker             broker.BrokerInterface
+	WebAuthn           *webauthn.WebAuthn
+	AuthConfig         AuthConfig
+	PrivacyConfig      PrivacyConfig
+	StepUpConfig       StepUpConfig
+	MachinesConfig     MachinesConfig
+	CardsConfig        CardsConfig
 }
 
 func NewService(deps Deps) *Service {
 	return &Service{
-		Auth: NewAuthService(deps.Repos.Users, deps.RDB, deps.TokenManager, deps.Hasher,
-			deps.TransactionManager, deps.Broker),
+		Auth: NewAuthService(deps.Repos.Users, deps.Repos.TwoFactor, deps.Repos.Sessions, deps.Repos.Passkeys,
+			deps.RDB, deps.TokenManager, deps.Hasher, deps.TransactionManager, deps.Broker, deps.WebAuthn,
+			deps.AuthConfig),
 		Accounts: NewAccountsService(deps.RDB, deps.Repos.Users, deps.Repos.Accounts,
-			deps.TransactionManager),
+			deps.Repos.TwoFactor, deps.Repos.Recipients, deps.Repos.MachineJournal, deps.TransactionManager,
+			deps.Broker, deps.Repos.Passkeys, deps.WebAuthn, deps.StepUpConfig),
 		Machines: NewMachinesService(deps.Repos.Machines, deps.Repos.Accounts, deps.Repos.Users,
+			deps.Repos.Cassettes, deps.Repos.MachineHealth, deps.Repos.MachineSessions,
+			deps.Repos.MachineJournal, deps.Repos.CashoutCodes, deps.RDB, deps.Hasher, deps.Broker,
+			deps.TokenManager, deps.TransactionManager, deps.MachinesConfig),
+		Cards: NewCardsService(deps.Repos.Cards, deps.Repos.Accounts, deps.Repos.Users, deps.Hasher,
+			deps.CardsConfig),
+		Admin: NewAdminService(deps.Repos.Users, deps.Repos.Accounts, deps.Repos.AdminActions,
+			deps.Repos.Sessions, deps.Repos.Machines, deps.Repos.Cassettes,
+			deps.Repos.MachineHealth, deps.Repos.MachineJournal, deps.Repos.MachineSettlements, deps.RDB,
+			deps.TokenManager, deps.TransactionManager,
 			deps.Broker),
+		ApiKeys: NewApiKeysService(deps.Repos.ApiKeys, deps.Repos.Accounts),
+		Privacy: NewPrivacyService(deps.Repos.Users, deps.Repos.Accounts, deps.Repos.ApiKeys,
+			deps.Repos.TwoFactor, deps.Repos.Sessions, deps.Repos.Passkeys, deps.Repos.Cards,
+			deps.Repos.CashoutCodes, deps.Repos.MachineJournal, deps.RDB, deps.Hasher,
+			deps.TransactionManager, deps.Broker, deps.PrivacyConfig),
 	}
 }
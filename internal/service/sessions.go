@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// sessionSeenEvery limits how often last seen time of the session is written.
+const sessionSeenEvery = time.Minute
+
+// startSession records session of the device and returns access token bound to it.
+// Sign in from device the user hasn't used before is notified by email.
+func (s *AuthService) startSession(ctx context.Context, user domain.User, device domain.Device) (string, error) {
+	known, err := s.sessionsRepo.KnownDevice(ctx, user.Id, device.UserAgent)
+	if err != nil {
+		logrus.Errorf("error checking known device in repo: %s", err)
+		return "", ErrInternal
+	}
+
+	sessionId, err := s.sessionsRepo.Create(ctx, domain.Session{
+		UserId:    user.Id,
+		UserAgent: device.UserAgent,
+		Ip:        device.Ip,
+		ExpiresAt: time.Now().Add(s.cfg.SessionTTL),
+	})
+	if err != nil {
+		logrus.Errorf("error creating session into repo: %s", err)
+		return "", ErrInternal
+	}
+
+	accessToken, err := s.tokenManager.CreateAccessToken(user.Id, user.Role, sessionId)
+	if err != nil {
+		logrus.Errorf("error creating access token: %s", err)
+		return "", ErrInternal
+	}
+
+	if !known {
+		err := s.broker.WriteNewLoginTask(ctx, user.Email, device.UserAgent, device.Ip)
+		if err != nil {
+			logrus.Errorf("error writing new login task: %s", err)
+		}
+	}
+
+	return accessToken, nil
+}
+
+// checkSession rejects revoked session and updates its last seen time.
+func (s *AuthService) checkSession(ctx context.Context, sessionId uuid.UUID) error {
+	revoked, err := s.rdb.Exists(ctx, fmt.Sprintf(sessionRevokedKey, sessionId)).Result()
+	if err != nil {
+		logrus.Errorf("error checking session revocation in redis: %s", err)
+		return ErrInternal
+	}
+	if revoked > 0 {
+		return ErrSessionRevoked
+	}
+
+	fresh, err := s.rdb.SetNX(ctx, fmt.Sprintf(sessionSeenKey, sessionId), 1, sessionSeenEvery).Result()
+	if err != nil {
+		logrus.Errorf("error setting session seen mark into redis: %s", err)
+		return nil
+	}
+	if fresh {
+		if err := s.sessionsRepo.Touch(ctx, sessionId); err != nil {
+			logrus.Errorf("error touching session: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *AuthService) GetSessions(ctx context.Context, userId uuid.UUID) ([]domain.Session, error) {
+	sessions, err := s.sessionsRepo.GetActive(ctx, userId)
+	if err != nil {
+		logrus.Errorf("error getting sessions from repo: %s", err)
+		return nil, ErrInternal
+	}
+	return sessions, nil
+}
+
+// RevokeSession can be retried, revocation in repo is idempotent so retry
+// reaches redis if it failed before.
+func (s *AuthService) RevokeSession(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	if err := s.sessionsRepo.Revoke(ctx, userId, id); err != nil {
+		logrus.Errorf("error revoking session in repo: %s", err)
+		if errors.Is(repository.ErrSessionDoesntExist, err) {
+			return ErrSessionNotFound
+		}
+		return ErrInternal
+	}
+
+	// the session can't outlive its access token
+	err := s.rdb.Set(ctx, fmt.Sprintf(sessionRevokedKey, id), 1, s.cfg.SessionTTL).Err()
+	if err != nil {
+		logrus.Errorf("error setting session revocation into redis: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	journalMaxLimit     = 500
+	settlementsMaxLimit = 100
+	journalDefaultRange = 24 * time.Hour
+)
+
+// validateCountedNotes accepts empty cassettes, unlike notes moved by an operation.
+func validateCountedNotes(notes []domain.Note) bool {
+	seen := make(map[int]bool, len(notes))
+	for _, note := range notes {
+		if note.Denomination <= 0 || note.Count < 0 || seen[note.Denomination] {
+			return false
+		}
+		seen[note.Denomination] = true
+	}
+	return true
+}
+
+// SettleMachine closes the business day of the machine. Cash counted by staff
+// is compared with the inventory, totals are taken from the journal since the
+// previous settlement. Counted cash becomes the inventory of the machine.
+func (s *AdminService) SettleMachine(ctx context.Context, actorId uuid.UUID, machineId uuid.UUID,
+	counted []domain.Note) (domain.MachineSettlement, error) {
+	var settlement domain.MachineSettlement
+
+	if !validateCountedNotes(counted) {
+		return settlement, ErrInvalidNotes
+	}
+	machine, err := s.getMachine(ctx, machineId)
+	if err != nil {
+		return settlement, err
+	}
+	if machine.Status == domain.MachineStatusDecommissioned {
+		return settlement, ErrMachineDecommissioned
+	}
+
+	err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		cassettes, err := s.cassettesRepo.GetForUpdate(ctx, machineId)
+		if err != nil {
+			return err
+		}
+
+		periodStart := machine.CreatedAt
+		last, err := s.settlementsRepo.GetLast(ctx, machineId)
+		if err != nil {
+			return err
+		}
+		if last != nil {
+			periodStart = last.PeriodEnd
+		}
+		periodEnd := time.Now()
+		totals, err := s.journalRepo.Totals(ctx, machineId, periodStart, periodEnd)
+		if err != nil {
+			return err
+		}
+
+		expected := make([]domain.Note, len(cassettes))
+		thresholds := make(map[int]int, len(cassettes))
+		for i, cassette := range cassettes {
+			expected[i] = domain.Note{Denomination: cassette.Denomination, Count: cassette.Count}
+			thresholds[cassette.Denomination] = cassette.LowThreshold
+		}
+		settlement = domain.MachineSettlement{
+			MachineId:     machineId,
+			PeriodStart:   periodStart,
+			PeriodEnd:     periodEnd,
+			Expected:      expected,
+			Counted:       counted,
+			SettledBy:     actorId,
+			JournalTotals: totals,
+		}
+
+		differences := []string{}
+		for _, difference := range settlement.Differences() {
+			if difference.Difference() != 0 {
+				settlement.Discrepancy = true
+				differences = append(differences, fmt.Sprintf("%d: %d -> %d", difference.Denomination,
+					difference.Expected, difference.Counted))
+			}
+			err := s.cassettesRepo.Set(ctx, domain.Cassette{
+				MachineId:    machineId,
+				Denomination: difference.Denomination,
+				Count:        difference.Counted,
+				LowThreshold: thresholds[difference.Denomination],
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		settlement, err = s.settlementsRepo.Create(ctx, settlement)
+		if err != nil {
+			return err
+		}
+		details := "balanced"
+		if settlement.Discrepancy {
+			details = "discrepancy " + strings.Join(differences, ", ")
+		}
+		return s.record(ctx, actorId, adminActionSettleMachine, adminTargetMachine, machineId.String(), details)
+	})
+	if err != nil {
+		logrus.Errorf("error settling machine in transaction: %s", err)
+		return settlement, ErrInternal
+	}
+
+	if settlement.Discrepancy {
+		err := s.broker.WriteDiscrepancyTask(ctx, machineId, settlement.Id, settlement.ExpectedAmount(),
+			settlement.CountedAmount())
+		if err != nil {
+			logrus.Errorf("error writing discrepancy task: %s", err)
+		}
+	}
+
+	return settlement, nil
+}
+
+func (s *AdminService) GetMachineSettlements(ctx context.Context, machineId uuid.UUID, limit int,
+	offset int) ([]domain.MachineSettlement, error) {
+	if limit <= 0 || limit > settlementsMaxLimit {
+		limit = settlementsMaxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := s.getMachine(ctx, machineId); err != nil {
+		return nil, err
+	}
+
+	settlements, err := s.settlementsRepo.GetByMachine(ctx, machineId, limit, offset)
+	if err != nil {
+		logrus.Errorf("error getting machine settlements from repo: %s", err)
+		return nil, ErrInternal
+	}
+
+	return settlements, nil
+}
+
+// GetSettlements returns settlements of the fleet, only flagged ones if
+// discrepancyOnly is set.
+func (s *AdminService) GetSettlements(ctx context.Context, discrepancyOnly bool, limit int,
+	offset int) ([]domain.MachineSettlement, error) {
+	if limit <= 0 || limit > settlementsMaxLimit {
+		limit = settlementsMaxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	settlements, err := s.settlementsRepo.GetAll(ctx, discrepancyOnly, limit, offset)
+	if err != nil {
+		logrus.Errorf("error getting settlements from repo: %s", err)
+		return nil, ErrInternal
+	}
+
+	return settlements, nil
+}
+
+// GetMachineJournal returns the electronic journal of the machine in [from, to).
+// The last day is returned when the period isn't set.
+func (s *AdminService) GetMachineJournal(ctx context.Context, machineId uuid.UUID, from time.Time,
+	to time.Time, limit int, offset int) ([]domain.JournalEntry, error) {
+	if limit <= 0 || limit > journalMaxLimit {
+		limit = journalMaxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-journalDefaultRange)
+	}
+	if _, err := s.getMachine(ctx, machineId); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.journalRepo.GetByMachine(ctx, machineId, from, to, limit, offset)
+	if err != nil {
+		logrus.Errorf("error getting machine journal from repo: %s", err)
+		return nil, ErrInternal
+	}
+
+	return entries, nil
+}
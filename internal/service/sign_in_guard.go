@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	signInFailsKey = "signin:fails:%s:%s"
+	signInLockKey  = "signin:lock:%s:%s"
+)
+
+const (
+	signInScopeEmail     = "email"
+	signInScopeIp        = "ip"
+	signInScopeTwoFactor = "2fa"
+)
+
+type SignInGuardConfig struct {
+	AccountAttempts int
+	IpAttempts      int
+	// TwoFactorAttempts limits wrong second factor codes of the user, counted
+	// across sign in challenges
+	TwoFactorAttempts int
+	Window            time.Duration
+	BaseLockout       time.Duration
+	MaxLockout        time.Duration
+}
+
+// LockedError is returned when sign in is temporarily locked after too many failed attempts.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("sign in is locked, retry after %s", e.RetryAfter)
+}
+
+// signInGuard counts failed sign in attempts per account and per ip and locks
+// sign in with exponential back-off once the limit is reached.
+type signInGuard struct {
+	rdb *redis.Client
+	cfg SignInGuardConfig
+}
+
+func newSignInGuard(rdb *redis.Client, cfg SignInGuardConfig) *signInGuard {
+	return &signInGuard{
+		rdb: rdb,
+		cfg: cfg,
+	}
+}
+
+// check returns LockedError if email or ip is locked.
+func (g *signInGuard) check(ctx context.Context, email string, ip string) error {
+	return g.locked(ctx,
+		fmt.Sprintf(signInLockKey, signInScopeEmail, email),
+		fmt.Sprintf(signInLockKey, signInScopeIp, ip))
+}
+
+// checkTwoFactor returns LockedError if second factor of the user is locked.
+func (g *signInGuard) checkTwoFactor(ctx context.Context, userId uuid.UUID) error {
+	return g.locked(ctx, fmt.Sprintf(signInLockKey, signInScopeTwoFactor, userId))
+}
+
+func (g *signInGuard) locked(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		ttl, err := g.rdb.PTTL(ctx, key).Result()
+		if err != nil {
+			logrus.Errorf("error getting sign in lock ttl from redis: %s", err)
+			return ErrInternal
+		}
+		if ttl > 0 {
+			return &LockedError{
+				RetryAfter: ttl,
+			}
+		}
+	}
+	return nil
+}
+
+// fail registers failed attempt. It reports whether the account has just been locked.
+func (g *signInGuard) fail(ctx context.Context, email string, ip string) (bool, error) {
+	accountLocked, err := g.hit(ctx, signInScopeEmail, email, g.cfg.AccountAttempts)
+	if err != nil {
+		return false, err
+	}
+	if _, err := g.hit(ctx, signInScopeIp, ip, g.cfg.IpAttempts); err != nil {
+		return false, err
+	}
+	return accountLocked, nil
+}
+
+// failTwoFactor registers wrong second factor code of the user. It reports
+// whether second factor has just been locked.
+func (g *signInGuard) failTwoFactor(ctx context.Context, userId uuid.UUID) (bool, error) {
+	return g.hit(ctx, signInScopeTwoFactor, userId.String(), g.cfg.TwoFactorAttempts)
+}
+
+func (g *signInGuard) hit(ctx context.Context, scope string, value string, limit int) (bool, error) {
+	failsKey := fmt.Sprintf(signInFailsKey, scope, value)
+
+	fails, err := g.rdb.Incr(ctx, failsKey).Result()
+	if err != nil {
+		logrus.Errorf("error incrementing sign in fails into redis: %s", err)
+		return false, ErrInternal
+	}
+	if fails < int64(limit) {
+		if err := g.rdb.Expire(ctx, failsKey, g.cfg.Window).Err(); err != nil {
+			logrus.Errorf("error setting sign in fails ttl into redis: %s", err)
+			return false, ErrInternal
+		}
+		return false, nil
+	}
+
+	// every failure over the limit doubles the lockout
+	lockout := g.cfg.BaseLockout
+	for i := int64(limit); i < fails && lockout < g.cfg.MaxLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > g.cfg.MaxLockout {
+		lockout = g.cfg.MaxLockout
+	}
+
+	pipe := g.rdb.TxPipeline()
+	pipe.Set(ctx, fmt.Sprintf(signInLockKey, scope, value), 1, lockout)
+	pipe.Expire(ctx, failsKey, lockout+g.cfg.Window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logrus.Errorf("error setting sign in lock into redis: %s", err)
+		return false, ErrInternal
+	}
+
+	return true, nil
+}
+
+// reset forgets failed attempts of the account after successful sign in.
+func (g *signInGuard) reset(ctx context.Context, email string) {
+	if err := g.rdb.Del(ctx, fmt.Sprintf(signInFailsKey, signInScopeEmail, email)).Err(); err != nil {
+		logrus.Errorf("error deleting sign in fails from redis: %s", err)
+	}
+}
+
+// resetTwoFactor forgets wrong second factor codes of the user after a valid one.
+func (g *signInGuard) resetTwoFactor(ctx context.Context, userId uuid.UUID) {
+	if err := g.rdb.Del(ctx, fmt.Sprintf(signInFailsKey, signInScopeTwoFactor, userId)).Err(); err != nil {
+		logrus.Errorf("error deleting two factor fails from redis: %s", err)
+	}
+}
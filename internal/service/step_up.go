@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/tokens"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	pendingTransferKey         = "step-up:transfer:%s"
+	pendingTransferAttemptsKey = "step-up:transfer:attempts:%s"
+	stepUpFailsKey             = "step-up:fails:%s"
+)
+
+const stepUpCodeDigits = 6
+
+type StepUpConfig struct {
+	// Thresholds maps user tier to transfer amount above which the transfer
+	// must be confirmed. Users of unknown tier get threshold of standard tier.
+	Thresholds   map[string]int
+	OperationTTL time.Duration
+	// CodeAttempts limits proofs of one pending transfer
+	CodeAttempts int
+	// UserAttempts limits wrong proofs of the user across pending transfers
+	// within UserWindow
+	UserAttempts int
+	UserWindow   time.Duration
+}
+
+func (c StepUpConfig) threshold(tier string) int {
+	if threshold, ok := c.Thresholds[tier]; ok {
+		return threshold
+	}
+	return c.Thresholds[domain.TierStandard]
+}
+
+// stepUpRequired reports whether transfer must be confirmed with second factor:
+// the amount is above threshold of the user's tier or money goes to new recipient.
+func (s *AccountsService) stepUpRequired(ctx context.Context, user domain.User, to uuid.UUID,
+	amount int) (bool, error) {
+	if amount > s.cfg.threshold(user.Tier) {
+		return true, nil
+	}
+	known, err := s.recipientsRepo.Known(ctx, user.Id, to)
+	if err != nil {
+		logrus.Errorf("error checking known recipient in repo: %s", err)
+		return false, ErrInternal
+	}
+	return !known, nil
+}
+
+// createPendingTransfer saves transfer until it is confirmed. Users with passkeys
+// confirm it with passkey, users with 2FA enabled with TOTP code, others with
+// one-time code sent by email.
+func (s *AccountsService) createPendingTransfer(ctx context.Context, user domain.User, id uuid.UUID,
+	to uuid.UUID, amount int) (domain.PendingTransfer, error) {
+	pending := domain.PendingTransfer{
+		Id:        uuid.New(),
+		UserId:    user.Id,
+		AccountId: id,
+		To:        to,
+		Amount:    amount,
+		Method:    domain.StepUpMethodEmail,
+		ExpiresAt: time.Now().Add(s.cfg.OperationTTL),
+	}
+
+	waUser, err := s.passkeys.user(ctx, user)
+	if err != nil {
+		return pending, err
+	}
+	twoFactor, err := s.twoFactorRepo.Get(ctx, user.Id)
+	if err != nil && !errors.Is(repository.ErrTwoFactorNotFound, err) {
+		logrus.Errorf("error getting two factor from repo when creating pending transfer: %s", err)
+		return pending, ErrInternal
+	}
+	totpEnabled := err == nil && twoFactor.Enabled
+
+	switch {
+	case len(waUser.passkeys) > 0:
+		pending.Method = domain.StepUpMethodPasskey
+		pending.PasskeyOptions, pending.Ceremony, err = s.passkeys.beginAssertion(waUser)
+		if err != nil {
+			return pending, err
+		}
+	case totpEnabled:
+		pending.Method = domain.StepUpMethodTotp
+	}
+
+	if pending.Method == domain.StepUpMethodEmail {
+		code, err := generateNumericCode(stepUpCodeDigits)
+		if err != nil {
+			logrus.Errorf("error generating step-up code: %s", err)
+			return pending, ErrInternal
+		}
+		pending.CodeHash = tokens.HashToken(code)
+		if err := s.broker.WriteStepUpCodeTask(ctx, user.Email, code, amount); err != nil {
+			logrus.Errorf("error writing step-up code task: %s", err)
+			return pending, ErrInternal
+		}
+	}
+
+	data, err := json.Marshal(pending)
+	if err != nil {
+		logrus.Errorf("error marshaling pending transfer: %s", err)
+		return pending, ErrInternal
+	}
+	err = s.rdb.Set(ctx, fmt.Sprintf(pendingTransferKey, pending.Id), data, s.cfg.OperationTTL).Err()
+	if err != nil {
+		logrus.Errorf("error saving pending transfer into redis: %s", err)
+		return pending, ErrInternal
+	}
+
+	return pending, nil
+}
+
+// ConfirmTransfer checks proof of the pending transfer and executes it. The pending
+// transfer is dropped after too many wrong proofs. Wrong proofs are counted per
+// user as well, as every new pending transfer has attempts of its own.
+func (s *AccountsService) ConfirmTransfer(ctx context.Context, userId uuid.UUID, id uuid.UUID,
+	proof domain.StepUpProof) error {
+	key := fmt.Sprintf(pendingTransferKey, id)
+
+	data, err := s.rdb.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(redis.Nil, err) {
+			return ErrOperationNotFound
+		}
+		logrus.Errorf("error getting pending transfer from redis: %s", err)
+		return ErrInternal
+	}
+	var pending domain.PendingTransfer
+	if err := json.Unmarshal(data, &pending); err != nil {
+		logrus.Errorf("error unmarshaling pending transfer: %s", err)
+		return ErrInternal
+	}
+	if pending.UserId != userId {
+		return ErrOperationNotFound
+	}
+
+	limited, err := hitRateLimit(ctx, s.rdb, fmt.Sprintf(pendingTransferAttemptsKey, id),
+		s.cfg.CodeAttempts, s.cfg.OperationTTL)
+	if err != nil {
+		logrus.Errorf("error checking pending transfer attempts: %s", err)
+		return ErrInternal
+	}
+	if limited {
+		s.rdb.Del(ctx, key)
+		return ErrOperationNotFound
+	}
+
+	failsKey := fmt.Sprintf(stepUpFailsKey, userId)
+	fails, err := s.rdb.Get(ctx, failsKey).Int()
+	if err != nil && !errors.Is(redis.Nil, err) {
+		logrus.Errorf("error getting step-up fails from redis: %s", err)
+		return ErrInternal
+	}
+	if fails >= s.cfg.UserAttempts {
+		return ErrTooManyRequests
+	}
+
+	if err := s.checkStepUpProof(ctx, pending, proof); err != nil {
+		if errors.Is(ErrInvalidCode, err) || errors.Is(ErrPasskeyInvalid, err) {
+			if _, err := hitRateLimit(ctx, s.rdb, failsKey, s.cfg.UserAttempts, s.cfg.UserWindow); err != nil {
+				logrus.Errorf("error counting step-up fails: %s", err)
+				return ErrInternal
+			}
+		}
+		return err
+	}
+
+	// GetDel guards against concurrent confirmation of the same transfer
+	if err := s.rdb.GetDel(ctx, key).Err(); err != nil {
+		if errors.Is(redis.Nil, err) {
+			return ErrOperationNotFound
+		}
+		logrus.Errorf("error deleting pending transfer from redis: %s", err)
+		return ErrInternal
+	}
+
+	// balance could change while the transfer was pending
+	account, accountTo, err := s.checkTransfer(ctx, userId, pending.AccountId, pending.To, pending.Amount)
+	if err != nil {
+		return err
+	}
+
+	return s.transfer(ctx, userId, account, accountTo, pending.Amount)
+}
+
+func (s *AccountsService) checkStepUpProof(ctx context.Context, pending domain.PendingTransfer,
+	proof domain.StepUpProof) error {
+	switch pending.Method {
+	case domain.StepUpMethodEmail:
+		if tokens.HashToken(proof.Code) != pending.CodeHash {
+			return ErrInvalidCode
+		}
+		return nil
+	case domain.StepUpMethodPasskey:
+		user, err := s.usersRepo.Get(ctx, pending.UserId)
+		if err != nil {
+			logrus.Errorf("error getting user from repo when confirming transfer: %s", err)
+			return ErrInternal
+		}
+		waUser, err := s.passkeys.user(ctx, user)
+		if err != nil {
+			return err
+		}
+		return s.passkeys.finishAssertion(ctx, waUser, pending.Ceremony, proof.PasskeyAssertion)
+	}
+
+	twoFactor, err := s.twoFactorRepo.Get(ctx, pending.UserId)
+	if err != nil {
+		if errors.Is(repository.ErrTwoFactorNotFound, err) {
+			return ErrInvalidCode
+		}
+		logrus.Errorf("error getting two factor from repo when confirming transfer: %s", err)
+		return ErrInternal
+	}
+	if !twoFactor.Enabled {
+		return ErrInvalidCode
+	}
+
+	return checkSecondFactor(ctx, s.rdb, s.twoFactorRepo, twoFactor, proof.Code)
+}
+
+func generateNumericCode(digits int) (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < digits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", digits, n), nil
+}
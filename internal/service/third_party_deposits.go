@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var depositRecipientLimitKey = "deposit-recipient:machine:%s"
+
+type ThirdPartyDepositsConfig struct {
+	// MaxAmount is the largest cash deposit to account of someone else
+	MaxAmount int
+	// ReviewThreshold is amount from which the deposit is flagged for review
+	ReviewThreshold int
+	// LookupAttempts recipient lookups and deposits are allowed per machine
+	// within LookupWindow
+	LookupAttempts int
+	LookupWindow   time.Duration
+}
+
+// limitRecipientLookup counts lookup of deposit recipient made by the machine,
+// so account numbers can't be probed by trying them.
+func (s *MachinesService) limitRecipientLookup(ctx context.Context, id uuid.UUID) error {
+	limited, err := hitRateLimit(ctx, s.rdb, fmt.Sprintf(depositRecipientLimitKey, id),
+		s.cfg.ThirdPartyDeposits.LookupAttempts, s.cfg.ThirdPartyDeposits.LookupWindow)
+	if err != nil {
+		logrus.Errorf("error hitting deposit recipient rate limit: %s", err)
+		return ErrInternal
+	}
+	if limited {
+		return ErrTooManyRequests
+	}
+	return nil
+}
+
+// getRecipient returns account which cash may be deposited to by anyone and
+// its holder. Missing, frozen and erased user's accounts are declined alike
+// with ErrRecipientUnavailable, the depositor learns nothing about them.
+func (s *MachinesService) getRecipient(ctx context.Context, accountId uuid.UUID) (domain.Account,
+	domain.User, error) {
+	account, err := s.accountsRepo.Get(ctx, accountId)
+	if err != nil {
+		logrus.Errorf("error get recipient account from repo: %s", err)
+		if errors.Is(repository.ErrAccountNotFound, err) {
+			return account, domain.User{}, ErrRecipientUnavailable
+		}
+		return account, domain.User{}, ErrInternal
+	}
+	user, err := s.getUser(ctx, account.UserId)
+	if err != nil {
+		if errors.Is(ErrUserNotFound, err) {
+			return account, user, ErrRecipientUnavailable
+		}
+		return account, user, err
+	}
+	if user.ErasedAt != nil {
+		logrus.Errorf("error deposit recipient account %s belongs to erased user", account.Id)
+		return account, user, ErrRecipientUnavailable
+	}
+	if account.Frozen {
+		logrus.Errorf("error deposit recipient account %s is frozen", account.Id)
+		return account, user, ErrRecipientUnavailable
+	}
+	return account, user, nil
+}
+
+// GetDepositRecipient returns masked name of the account holder for the
+// depositor to confirm. Lookups are limited per machine so names can't be
+// harvested by trying account numbers.
+func (s *MachinesService) GetDepositRecipient(ctx context.Context, id uuid.UUID,
+	accountId uuid.UUID) (domain.DepositRecipient, error) {
+	var recipient domain.DepositRecipient
+
+	if _, err := s.getOperatingMachine(ctx, id, domain.MachineOperationDeposit); err != nil {
+		return recipient, err
+	}
+
+	if err := s.limitRecipientLookup(ctx, id); err != nil {
+		return recipient, err
+	}
+
+	account, user, err := s.getRecipient(ctx, accountId)
+	if err != nil {
+		return recipient, err
+	}
+
+	recipient.AccountId = account.Id
+	recipient.Name = domain.MaskName(user)
+	return recipient, nil
+}
+
+// MachineThirdPartyDeposit credits cash inserted by anyone to the account. The
+// depositor isn't identified, so the amount is limited and large deposits are
+// flagged for review. The holder is notified as for own deposit. Deposits
+// count against the same per machine limit as recipient lookups.
+func (s *MachinesService) MachineThirdPartyDeposit(ctx context.Context, id uuid.UUID, accountId uuid.UUID,
+	amount int, notes []domain.Note) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if !domain.ValidateNotes(notes) || domain.NotesAmount(notes) != amount {
+		return ErrInvalidNotes
+	}
+	if _, err := s.getOperatingMachine(ctx, id, domain.MachineOperationDeposit); err != nil {
+		return err
+	}
+	if err := s.limitRecipientLookup(ctx, id); err != nil {
+		return err
+	}
+
+	account, user, err := s.getRecipient(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	if amount > s.cfg.ThirdPartyDeposits.MaxAmount {
+		s.journalDeclinedThirdPartyDeposit(ctx, id, account.Id, amount, notes, ErrDepositLimitExceeded)
+		return ErrDepositLimitExceeded
+	}
+	flagged := amount >= s.cfg.ThirdPartyDeposits.ReviewThreshold
+
+	var credited domain.Account
+	err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.cassettesRepo.Add(ctx, id, notes); err != nil {
+			return err
+		}
+		var err error
+		credited, err = s.accountsRepo.Credit(ctx, account.Id, amount)
+		if err != nil {
+			return err
+		}
+		return s.journalRepo.Create(ctx, domain.JournalEntry{
+			MachineId:  id,
+			AccountId:  account.Id,
+			Operation:  domain.JournalDeposit,
+			Amount:     amount,
+			Notes:      notes,
+			ThirdParty: true,
+			Flagged:    flagged,
+		})
+	})
+	if err != nil {
+		logrus.Errorf("error third party deposit in transaction: %s", err)
+		return ErrInternal
+	}
+
+	if err := s.broker.WriteDepositTask(ctx, id, user.Email, account.Id, amount, credited.Money); err != nil {
+		logrus.Errorf("error writing deposit task: %s", err)
+	}
+	if flagged {
+		if err := s.broker.WriteDepositReviewTask(ctx, id, account.Id, amount); err != nil {
+			logrus.Errorf("error writing deposit review task: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// journalDeclinedThirdPartyDeposit records deposit refused to the account, the
+// machine returns the cash to the depositor.
+func (s *MachinesService) journalDeclinedThirdPartyDeposit(ctx context.Context, id uuid.UUID,
+	accountId uuid.UUID, amount int, notes []domain.Note, reason error) {
+	message := reason.Error()
+	err := s.journalRepo.Create(ctx, domain.JournalEntry{
+		MachineId:  id,
+		AccountId:  accountId,
+		Operation:  domain.JournalDeposit,
+		Amount:     amount,
+		Notes:      notes,
+		Outcome:    domain.JournalDeclined,
+		Reason:     &message,
+		ThirdParty: true,
+	})
+	if err != nil {
+		logrus.Errorf("error journaling declined third party deposit: %s", err)
+	}
+}
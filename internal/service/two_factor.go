@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/domain"
+	"github.com/IvanMeln1k/go-bank-app-bank/internal/repository"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/tokens"
+	"github.com/IvanMeln1k/go-bank-app-bank/pkg/totp"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	twoFactorChallengeKey         = "2fa:challenge:%s"
+	twoFactorChallengeAttemptsKey = "2fa:challenge:attempts:%s"
+	twoFactorUsedStepKey          = "2fa:used:%s:%d"
+)
+
+const (
+	totpSkew                   = 1
+	recoveryCodeLen            = 10
+	twoFactorChallengeAttempts = 5
+)
+
+func (s *AuthService) EnrollTwoFactor(ctx context.Context, userId uuid.UUID) (domain.TwoFactorEnrollment, error) {
+	var enrollment domain.TwoFactorEnrollment
+
+	user, err := s.Get(ctx, userId)
+	if err != nil {
+		return enrollment, err
+	}
+
+	twoFactor, err := s.getTwoFactor(ctx, userId)
+	if err != nil && !errors.Is(ErrTwoFactorNotEnabled, err) {
+		return enrollment, err
+	}
+	if err == nil && twoFactor.Enabled {
+		return enrollment, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		logrus.Errorf("error generating totp secret: %s", err)
+		return enrollment, ErrInternal
+	}
+	if err := s.twoFactorRepo.Save(ctx, userId, secret); err != nil {
+		logrus.Errorf("error saving two factor into repo when enrolling: %s", err)
+		return enrollment, ErrInternal
+	}
+
+	enrollment.Secret = secret
+	enrollment.URI = totp.URI(s.cfg.TwoFactorIssuer, user.Email, secret)
+
+	return enrollment, nil
+}
+
+// ConfirmTwoFactor enables 2FA after the first valid code and returns recovery codes.
+// Recovery codes are shown only once, only their hashes are stored.
+func (s *AuthService) ConfirmTwoFactor(ctx context.Context, userId uuid.UUID, code string) ([]string, error) {
+	twoFactor, err := s.getTwoFactor(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	if err := checkTOTP(ctx, s.rdb, twoFactor, code); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, s.cfg.RecoveryCodesCount)
+	hashes := make([]string, s.cfg.RecoveryCodesCount)
+	for i := range codes {
+		codes[i], err = generateRecoveryCode()
+		if err != nil {
+			logrus.Errorf("error generating recovery code: %s", err)
+			return nil, ErrInternal
+		}
+		hashes[i] = tokens.HashToken(normalizeRecoveryCode(codes[i]))
+	}
+
+	err = s.transactionManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.twoFactorRepo.Enable(ctx, userId); err != nil {
+			return err
+		}
+		return s.twoFactorRepo.SetRecoveryCodes(ctx, userId, hashes)
+	})
+	if err != nil {
+		logrus.Errorf("error enabling two factor in transaction: %s", err)
+		return nil, ErrInternal
+	}
+
+	return codes, nil
+}
+
+func (s *AuthService) DisableTwoFactor(ctx context.Context, userId uuid.UUID, code string) error {
+	twoFactor, err := s.getTwoFactor(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if !twoFactor.Enabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if err := s.verifySecondFactor(ctx, twoFactor, code); err != nil {
+		return err
+	}
+
+	if err := s.twoFactorRepo.Delete(ctx, userId); err != nil {
+		logrus.Errorf("error deleting two factor from repo: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// createTwoFactorChallenge is the first step of sign in for users with 2FA enabled.
+func (s *AuthService) createTwoFactorChallenge(ctx context.Context, userId uuid.UUID) (string, error) {
+	token, err := tokens.GenerateRandomToken(32)
+	if err != nil {
+		logrus.Errorf("error generating two factor challenge token: %s", err)
+		return "", ErrInternal
+	}
+	err = s.rdb.Set(ctx, fmt.Sprintf(twoFactorChallengeKey, tokens.HashToken(token)), userId.String(),
+		s.cfg.TwoFactorChallengeTTL).Err()
+	if err != nil {
+		logrus.Errorf("error saving two factor challenge into redis: %s", err)
+		return "", ErrInternal
+	}
+	return token, nil
+}
+
+func (s *AuthService) SignInTwoFactor(ctx context.Context, challengeToken string, code string,
+	device domain.Device) (string, error) {
+	hash := tokens.HashToken(challengeToken)
+	challengeKey := fmt.Sprintf(twoFactorChallengeKey, hash)
+
+	value, err := s.rdb.Get(ctx, challengeKey).Result()
+	if err != nil {
+		if errors.Is(redis.Nil, err) {
+			return "", ErrTokenInvalid
+		}
+		logrus.Errorf("error getting two factor challenge from redis: %s", err)
+		return "", ErrInternal
+	}
+	userId, err := uuid.Parse(value)
+	if err != nil {
+		logrus.Errorf("error parsing user id of two factor challenge: %s", err)
+		return "", ErrInternal
+	}
+
+	limited, err := hitRateLimit(ctx, s.rdb, fmt.Sprintf(twoFactorChallengeAttemptsKey, hash),
+		twoFactorChallengeAttempts, s.cfg.TwoFactorChallengeTTL)
+	if err != nil {
+		logrus.Errorf("error checking two factor challenge attempts: %s", err)
+		return "", ErrInternal
+	}
+	if limited {
+		s.rdb.Del(ctx, challengeKey)
+		return "", ErrTokenInvalid
+	}
+
+	user, err := s.Get(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+	twoFactor, err := s.getTwoFactor(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+	if err := s.verifySecondFactor(ctx, twoFactor, code); err != nil {
+		return "", err
+	}
+
+	// GetDel guards against concurrent use of the same challenge
+	if err := s.rdb.GetDel(ctx, challengeKey).Err(); err != nil {
+		if errors.Is(redis.Nil, err) {
+			return "", ErrTokenInvalid
+		}
+		logrus.Errorf("error deleting two factor challenge from redis: %s", err)
+		return "", ErrInternal
+	}
+
+	return s.startSession(ctx, user, device)
+}
+
+func (s *AuthService) getTwoFactor(ctx context.Context, userId uuid.UUID) (domain.TwoFactor, error) {
+	twoFactor, err := s.twoFactorRepo.Get(ctx, userId)
+	if err != nil {
+		if errors.Is(repository.ErrTwoFactorNotFound, err) {
+			return twoFactor, ErrTwoFactorNotEnabled
+		}
+		logrus.Errorf("error getting two factor from repo: %s", err)
+		return twoFactor, ErrInternal
+	}
+	return twoFactor, nil
+}
+
+// checkTOTP validates code and rejects reuse of the already accepted code.
+func checkTOTP(ctx context.Context, rdb *redis.Client, twoFactor domain.TwoFactor, code string) error {
+	step, ok := totp.Validate(twoFactor.Secret, code, time.Now(), totpSkew)
+	if !ok {
+		return ErrInvalidCode
+	}
+
+	ttl := time.Duration((2*totpSkew+1)*totp.Period) * time.Second
+	fresh, err := rdb.SetNX(ctx, fmt.Sprintf(twoFactorUsedStepKey, twoFactor.UserId, step), 1, ttl).Result()
+	if err != nil {
+		logrus.Errorf("error saving used totp step into redis: %s", err)
+		return ErrInternal
+	}
+	if !fresh {
+		return ErrInvalidCode
+	}
+
+	return nil
+}
+
+// verifySecondFactor checks code of the user's second factor. Wrong codes are
+// counted per user, not per challenge, and lock second factor like sign in.
+func (s *AuthService) verifySecondFactor(ctx context.Context, twoFactor domain.TwoFactor, code string) error {
+	if err := s.signInGuard.checkTwoFactor(ctx, twoFactor.UserId); err != nil {
+		return err
+	}
+
+	err := checkSecondFactor(ctx, s.rdb, s.twoFactorRepo, twoFactor, code)
+	if errors.Is(ErrInvalidCode, err) {
+		if _, err := s.signInGuard.failTwoFactor(ctx, twoFactor.UserId); err != nil {
+			return err
+		}
+		return ErrInvalidCode
+	}
+	if err != nil {
+		return err
+	}
+
+	s.signInGuard.resetTwoFactor(ctx, twoFactor.UserId)
+	return nil
+}
+
+// checkSecondFactor accepts either TOTP code or one of the recovery codes.
+func checkSecondFactor(ctx context.Context, rdb *redis.Client, twoFactorRepo repository.TwoFactor,
+	twoFactor domain.TwoFactor, code string) error {
+	if len(code) == totp.Digits {
+		return checkTOTP(ctx, rdb, twoFactor, code)
+	}
+
+	hash := tokens.HashToken(normalizeRecoveryCode(code))
+	if err := twoFactorRepo.UseRecoveryCode(ctx, twoFactor.UserId, hash); err != nil {
+		if errors.Is(repository.ErrRecoveryCodeNotFound, err) {
+			return ErrInvalidCode
+		}
+		logrus.Errorf("error using recovery code: %s", err)
+		return ErrInternal
+	}
+
+	return nil
+}
+
+func generateRecoveryCode() (string, error) {
+	bytes := make([]byte, recoveryCodeLen)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	code := base32.StdEncoding.EncodeToString(bytes)[:recoveryCodeLen]
+	return code[:recoveryCodeLen/2] + "-" + code[recoveryCodeLen/2:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
@@ -0,0 +1 @@
+ALTER TABLE users ALTER COLUMN hash_password TYPE VARCHAR(255);
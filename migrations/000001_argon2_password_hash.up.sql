@@ -0,0 +1 @@
+ALTER TABLE users ALTER COLUMN hash_password TYPE TEXT;
@@ -0,0 +1,14 @@
+CREATE TABLE two_factor (
+    user_id UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT false
+);
+
+CREATE TABLE recovery_codes (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    hash_code TEXT NOT NULL,
+    used BOOLEAN NOT NULL DEFAULT false
+);
+
+CREATE INDEX recovery_codes_user_id_idx ON recovery_codes (user_id);
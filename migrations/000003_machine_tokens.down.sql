@@ -0,0 +1 @@
+ALTER TABLE machines DROP COLUMN token_id;
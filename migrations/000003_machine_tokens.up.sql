@@ -0,0 +1 @@
+ALTER TABLE machines ADD COLUMN token_id UUID;
@@ -0,0 +1,5 @@
+DROP TABLE admin_actions;
+
+ALTER TABLE accounts DROP COLUMN frozen;
+
+ALTER TABLE users DROP COLUMN role;
@@ -0,0 +1,15 @@
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'customer';
+
+ALTER TABLE accounts ADD COLUMN frozen BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE admin_actions (
+    id UUID PRIMARY KEY,
+    actor_id UUID NOT NULL REFERENCES users (id),
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id TEXT NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX admin_actions_actor_id_idx ON admin_actions (actor_id);
@@ -0,0 +1,3 @@
+DROP TABLE transfer_recipients;
+
+ALTER TABLE users DROP COLUMN tier;
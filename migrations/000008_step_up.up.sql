@@ -0,0 +1,8 @@
+ALTER TABLE users ADD COLUMN tier TEXT NOT NULL DEFAULT 'standard';
+
+CREATE TABLE transfer_recipients (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    account_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, account_id)
+);
@@ -0,0 +1,12 @@
+DROP INDEX machines_status_idx;
+
+ALTER TABLE machines
+    DROP COLUMN name,
+    DROP COLUMN address,
+    DROP COLUMN latitude,
+    DROP COLUMN longitude,
+    DROP COLUMN operations,
+    DROP COLUMN currency,
+    DROP COLUMN status,
+    DROP COLUMN created_at,
+    DROP COLUMN decommissioned_at;
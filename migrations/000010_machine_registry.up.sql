@@ -0,0 +1,12 @@
+ALTER TABLE machines
+    ADD COLUMN name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN address TEXT NOT NULL DEFAULT '',
+    ADD COLUMN latitude DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN longitude DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN operations TEXT[] NOT NULL DEFAULT '{cash_out,deposit}',
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'RUB',
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'active',
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN decommissioned_at TIMESTAMPTZ;
+
+CREATE INDEX machines_status_idx ON machines (status);
@@ -0,0 +1,8 @@
+CREATE TABLE machine_cassettes (
+    machine_id UUID NOT NULL REFERENCES machines (id) ON DELETE CASCADE,
+    denomination INT NOT NULL CHECK (denomination > 0),
+    count INT NOT NULL DEFAULT 0 CHECK (count >= 0),
+    low_threshold INT NOT NULL DEFAULT 0 CHECK (low_threshold >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (machine_id, denomination)
+);
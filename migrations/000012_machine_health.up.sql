@@ -0,0 +1,12 @@
+CREATE TABLE machine_health (
+    machine_id UUID PRIMARY KEY REFERENCES machines (id) ON DELETE CASCADE,
+    dispenser TEXT NOT NULL,
+    card_reader TEXT NOT NULL,
+    software_version TEXT NOT NULL,
+    cash_levels JSONB NOT NULL DEFAULT '[]',
+    online BOOLEAN NOT NULL DEFAULT true,
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    offline_since TIMESTAMPTZ
+);
+
+CREATE INDEX machine_health_online_idx ON machine_health (last_seen_at) WHERE online;
@@ -0,0 +1,15 @@
+CREATE TABLE cards (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    pan_hash TEXT NOT NULL UNIQUE,
+    last4 TEXT NOT NULL,
+    expires_on DATE NOT NULL,
+    status TEXT NOT NULL DEFAULT 'active',
+    pin_hash TEXT NOT NULL,
+    pin_attempts INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX cards_user_id_idx ON cards (user_id);
+CREATE INDEX cards_account_id_idx ON cards (account_id);
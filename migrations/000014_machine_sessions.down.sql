@@ -0,0 +1,2 @@
+DROP TABLE machine_journal;
+DROP TABLE machine_sessions;
@@ -0,0 +1,28 @@
+CREATE TABLE machine_sessions (
+    id UUID PRIMARY KEY,
+    machine_id UUID NOT NULL REFERENCES machines (id) ON DELETE CASCADE,
+    card_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    account_id UUID NOT NULL,
+    opened_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_activity_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    closed_at TIMESTAMPTZ,
+    close_reason TEXT
+);
+
+CREATE UNIQUE INDEX machine_sessions_open_idx ON machine_sessions (machine_id) WHERE closed_at IS NULL;
+CREATE INDEX machine_sessions_idle_idx ON machine_sessions (last_activity_at) WHERE closed_at IS NULL;
+
+CREATE TABLE machine_journal (
+    id UUID PRIMARY KEY,
+    session_id UUID NOT NULL REFERENCES machine_sessions (id) ON DELETE CASCADE,
+    machine_id UUID NOT NULL REFERENCES machines (id) ON DELETE CASCADE,
+    account_id UUID NOT NULL,
+    operation TEXT NOT NULL,
+    amount INT NOT NULL DEFAULT 0,
+    notes JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX machine_journal_session_id_idx ON machine_journal (session_id);
+CREATE INDEX machine_journal_account_id_idx ON machine_journal (account_id, created_at);
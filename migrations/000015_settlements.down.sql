@@ -0,0 +1,7 @@
+DROP TABLE machine_settlements;
+
+DROP INDEX machine_journal_machine_id_idx;
+
+ALTER TABLE machine_journal
+    DROP COLUMN outcome,
+    DROP COLUMN reason;
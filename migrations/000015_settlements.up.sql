@@ -0,0 +1,25 @@
+ALTER TABLE machine_journal
+    ADD COLUMN outcome TEXT NOT NULL DEFAULT 'approved',
+    ADD COLUMN reason TEXT;
+
+CREATE INDEX machine_journal_machine_id_idx ON machine_journal (machine_id, created_at);
+
+CREATE TABLE machine_settlements (
+    id UUID PRIMARY KEY,
+    machine_id UUID NOT NULL REFERENCES machines (id) ON DELETE CASCADE,
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end TIMESTAMPTZ NOT NULL,
+    dispensed INT NOT NULL,
+    dispensed_count INT NOT NULL,
+    deposited INT NOT NULL,
+    deposited_count INT NOT NULL,
+    declined_count INT NOT NULL,
+    expected JSONB NOT NULL,
+    counted JSONB NOT NULL,
+    discrepancy BOOLEAN NOT NULL,
+    settled_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX machine_settlements_machine_id_idx ON machine_settlements (machine_id, period_end);
+CREATE INDEX machine_settlements_discrepancy_idx ON machine_settlements (period_end) WHERE discrepancy;
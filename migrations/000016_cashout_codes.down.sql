@@ -0,0 +1,7 @@
+DELETE FROM machine_journal WHERE session_id IS NULL;
+
+ALTER TABLE machine_journal
+    DROP COLUMN cashout_code_id,
+    ALTER COLUMN session_id SET NOT NULL;
+
+DROP TABLE cashout_codes;
@@ -0,0 +1,22 @@
+CREATE TABLE cashout_codes (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    amount INT NOT NULL,
+    code_hash TEXT NOT NULL,
+    secret_hash TEXT NOT NULL,
+    secret_attempts INT NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'pending',
+    expires_at TIMESTAMPTZ NOT NULL,
+    machine_id UUID REFERENCES machines (id) ON DELETE SET NULL,
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX cashout_codes_code_hash_idx ON cashout_codes (code_hash) WHERE status = 'pending';
+CREATE INDEX cashout_codes_user_id_idx ON cashout_codes (user_id);
+CREATE INDEX cashout_codes_expires_at_idx ON cashout_codes (expires_at) WHERE status = 'pending';
+
+ALTER TABLE machine_journal
+    ALTER COLUMN session_id DROP NOT NULL,
+    ADD COLUMN cashout_code_id UUID REFERENCES cashout_codes (id) ON DELETE SET NULL;
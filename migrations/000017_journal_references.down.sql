@@ -0,0 +1,3 @@
+DROP INDEX machine_journal_reference_idx;
+
+ALTER TABLE machine_journal DROP COLUMN reference;
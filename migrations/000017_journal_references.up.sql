@@ -0,0 +1,4 @@
+ALTER TABLE machine_journal ADD COLUMN reference TEXT;
+
+CREATE UNIQUE INDEX machine_journal_reference_idx ON machine_journal (machine_id, operation, reference)
+    WHERE reference IS NOT NULL AND outcome = 'approved';
@@ -0,0 +1 @@
+DROP INDEX machines_location_idx;
@@ -0,0 +1 @@
+CREATE INDEX machines_location_idx ON machines (latitude, longitude) WHERE status = 'active';
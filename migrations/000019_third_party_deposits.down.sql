@@ -0,0 +1,5 @@
+DROP INDEX machine_journal_flagged_idx;
+
+ALTER TABLE machine_journal
+    DROP COLUMN third_party,
+    DROP COLUMN flagged;
@@ -0,0 +1,5 @@
+ALTER TABLE machine_journal
+    ADD COLUMN third_party BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN flagged BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX machine_journal_flagged_idx ON machine_journal (created_at) WHERE flagged;
@@ -0,0 +1 @@
+ALTER TABLE machine_sessions DROP COLUMN channel;
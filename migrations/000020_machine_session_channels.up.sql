@@ -0,0 +1 @@
+ALTER TABLE machine_sessions ADD COLUMN channel TEXT NOT NULL DEFAULT 'machine';
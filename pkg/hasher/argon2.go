@@ -0,0 +1,132 @@
+package hasher
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const argon2Prefix = "$argon2id$"
+
+type Argon2Config struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	KeyLen  uint32
+	SaltLen uint32
+}
+
+// Argon2Hasher hashes passwords with argon2id and a random salt per hash.
+// Hashes are stored in the PHC string format:
+// $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>
+type Argon2Hasher struct {
+	cfg    Argon2Config
+	legacy *Hasher
+}
+
+// NewArgon2Hasher creates argon2id hasher. If legacy is not nil, hashes in the
+// old sha256 format are still accepted by Check. Zero cost parameters and
+// lengths are refused, argon2 panics on zero time or threads.
+func NewArgon2Hasher(cfg Argon2Config, legacy *Hasher) (*Argon2Hasher, error) {
+	if cfg.Time == 0 || cfg.Threads == 0 || cfg.Memory == 0 || cfg.KeyLen == 0 || cfg.SaltLen == 0 {
+		return nil, ErrInvalidConfig
+	}
+	return &Argon2Hasher{
+		cfg:    cfg,
+		legacy: legacy,
+	}, nil
+}
+
+type argon2Hash struct {
+	version int
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+func parseArgon2Hash(hash string) (argon2Hash, bool) {
+	var h argon2Hash
+
+	if !strings.HasPrefix(hash, argon2Prefix) {
+		return h, false
+	}
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return h, false
+	}
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &h.version); err != nil {
+		return h, false
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &h.memory, &h.time, &h.threads); err != nil {
+		return h, false
+	}
+	if h.time == 0 || h.threads == 0 {
+		return h, false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return h, false
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return h, false
+	}
+	// empty key would match any password
+	if len(salt) == 0 || len(key) == 0 {
+		return h, false
+	}
+	h.salt = salt
+	h.key = key
+
+	return h, true
+}
+
+func (h *Argon2Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.cfg.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", ErrInternal
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.cfg.Time, h.cfg.Memory, h.cfg.Threads, h.cfg.KeyLen)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2Prefix, argon2.Version,
+		h.cfg.Memory, h.cfg.Time, h.cfg.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h *Argon2Hasher) Check(password string, hash string) bool {
+	parsed, ok := parseArgon2Hash(hash)
+	if !ok {
+		if h.legacy == nil {
+			return false
+		}
+		return h.legacy.Check(password, hash)
+	}
+	if parsed.version != argon2.Version {
+		return false
+	}
+
+	key := argon2.IDKey([]byte(password), parsed.salt, parsed.time, parsed.memory, parsed.threads,
+		uint32(len(parsed.key)))
+
+	return subtle.ConstantTimeCompare(key, parsed.key) == 1
+}
+
+// NeedsRehash reports whether hash is in the legacy format or was created
+// with cost parameters that differ from the current config.
+func (h *Argon2Hasher) NeedsRehash(hash string) bool {
+	parsed, ok := parseArgon2Hash(hash)
+	if !ok {
+		return true
+	}
+	return parsed.version != argon2.Version || parsed.memory != h.cfg.Memory ||
+		parsed.time != h.cfg.Time || parsed.threads != h.cfg.Threads ||
+		uint32(len(parsed.key)) != h.cfg.KeyLen || uint32(len(parsed.salt)) != h.cfg.SaltLen
+}
@@ -2,14 +2,24 @@ package hasher
 
 import (
 	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
 	"fmt"
 )
 
+var (
+	ErrInternal      = errors.New("error internal")
+	ErrInvalidConfig = errors.New("invalid hasher config")
+)
+
 type HasherInterface interface {
-	Hash(password string) string
+	Hash(password string) (string, error)
 	Check(password string, hash string) bool
+	NeedsRehash(hash string) bool
 }
 
+// Hasher is the legacy hasher: sha256(password + salt) with one global salt.
+// It is kept to verify hashes created before the migration to argon2id.
 type Hasher struct {
 	salt string
 }
@@ -20,11 +30,19 @@ func NewHasher(salt string) *Hasher {
 	}
 }
 
-func (h *Hasher) Hash(password string) string {
+func (h *Hasher) hash(password string) string {
 	bytes := sha256.Sum256([]byte(password + h.salt))
 	return fmt.Sprintf("%x", bytes)
 }
 
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.hash(password), nil
+}
+
 func (h *Hasher) Check(password string, hash string) bool {
-	return h.Hash(password) == hash
+	return subtle.ConstantTimeCompare([]byte(h.hash(password)), []byte(hash)) == 1
+}
+
+func (h *Hasher) NeedsRehash(hash string) bool {
+	return false
 }
@@ -0,0 +1,57 @@
+package iso8583
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// MaxFrameSize bounds message accepted from the peer.
+const MaxFrameSize = 8192
+
+var ErrFrameSize = errors.New("invalid frame size")
+
+// ReadFrame reads message prefixed with its length as big-endian binary
+// number of headerSize bytes, 2 or 4.
+func ReadFrame(r io.Reader, headerSize int) ([]byte, error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	var size int
+	switch headerSize {
+	case 2:
+		size = int(binary.BigEndian.Uint16(header))
+	case 4:
+		size = int(binary.BigEndian.Uint32(header))
+	default:
+		return nil, ErrFrameSize
+	}
+	if size == 0 || size > MaxFrameSize {
+		return nil, ErrFrameSize
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// WriteFrame writes message prefixed with its length, see ReadFrame.
+func WriteFrame(w io.Writer, headerSize int, data []byte) error {
+	if len(data) == 0 || len(data) > MaxFrameSize {
+		return ErrFrameSize
+	}
+	frame := make([]byte, headerSize, headerSize+len(data))
+	switch headerSize {
+	case 2:
+		binary.BigEndian.PutUint16(frame, uint16(len(data)))
+	case 4:
+		binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	default:
+		return ErrFrameSize
+	}
+	_, err := w.Write(append(frame, data...))
+	return err
+}
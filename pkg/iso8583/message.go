@@ -0,0 +1,65 @@
+package iso8583
+
+import "sort"
+
+// Message is ISO 8583 message. Values are kept as strings, binary fields hold
+// raw bytes.
+type Message struct {
+	MTI    string
+	fields map[int]string
+}
+
+func NewMessage(mti string) *Message {
+	return &Message{
+		MTI:    mti,
+		fields: map[int]string{},
+	}
+}
+
+func (m *Message) Set(field int, value string) {
+	m.fields[field] = value
+}
+
+func (m *Message) Get(field int) string {
+	return m.fields[field]
+}
+
+func (m *Message) Has(field int) bool {
+	_, ok := m.fields[field]
+	return ok
+}
+
+// Fields returns numbers of present fields in ascending order.
+func (m *Message) Fields() []int {
+	fields := make([]int, 0, len(m.fields))
+	for field := range m.fields {
+		fields = append(fields, field)
+	}
+	sort.Ints(fields)
+	return fields
+}
+
+// Response starts response to the message copying the fields the peer matches
+// the response by.
+func (m *Message) Response(echo ...int) *Message {
+	response := NewMessage(ResponseMTI(m.MTI))
+	for _, field := range echo {
+		if value, ok := m.fields[field]; ok {
+			response.fields[field] = value
+		}
+	}
+	return response
+}
+
+// ResponseMTI returns MTI of response to the request, e.g. 0210 for 0200.
+// Repeats (0101, 0421) are answered as the original requests.
+func ResponseMTI(mti string) string {
+	if len(mti) != 4 {
+		return mti
+	}
+	function := mti[2]
+	if function%2 == 0 {
+		function++
+	}
+	return mti[:2] + string(function) + "0"
+}
@@ -0,0 +1,237 @@
+package iso8583
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxField   = 128
+	bitmapSize = 8
+)
+
+var (
+	ErrInvalidMTI   = errors.New("invalid message type indicator")
+	ErrUnknownField = errors.New("field is not in spec")
+	ErrFieldLength  = errors.New("invalid field length")
+	ErrFieldFormat  = errors.New("invalid field format")
+	ErrShortMessage = errors.New("message is truncated")
+)
+
+func validMTI(mti string) bool {
+	return len(mti) == 4 && isDigits(mti)
+}
+
+func isDigits(value string) bool {
+	for _, char := range value {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isPrintable(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] < 0x20 || value[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func (f Field) validate(value string) error {
+	switch f.Format {
+	case Numeric:
+		if !isDigits(value) {
+			return ErrFieldFormat
+		}
+	case Alpha:
+		if !isPrintable(value) {
+			return ErrFieldFormat
+		}
+	}
+	return nil
+}
+
+// pack encodes value of the field with length prefix or padding.
+func (f Field) pack(value string) ([]byte, error) {
+	if err := f.validate(value); err != nil {
+		return nil, err
+	}
+	switch f.Prefix {
+	case LLVar, LLLVar:
+		digits := 2
+		if f.Prefix == LLLVar {
+			digits = 3
+		}
+		if len(value) > f.Length {
+			return nil, ErrFieldLength
+		}
+		return []byte(fmt.Sprintf("%0*d%s", digits, len(value), value)), nil
+	}
+
+	if len(value) > f.Length {
+		return nil, ErrFieldLength
+	}
+	padding := f.Length - len(value)
+	switch f.Format {
+	case Numeric:
+		value = strings.Repeat("0", padding) + value
+	case Alpha:
+		value += strings.Repeat(" ", padding)
+	case Binary:
+		if padding != 0 {
+			return nil, ErrFieldLength
+		}
+	}
+	return []byte(value), nil
+}
+
+// unpack decodes the field at the start of data and returns its value and
+// number of bytes read.
+func (f Field) unpack(data []byte) (string, int, error) {
+	length := f.Length
+	read := 0
+	switch f.Prefix {
+	case LLVar, LLLVar:
+		digits := 2
+		if f.Prefix == LLLVar {
+			digits = 3
+		}
+		if len(data) < digits {
+			return "", 0, ErrShortMessage
+		}
+		prefix := string(data[:digits])
+		if !isDigits(prefix) {
+			return "", 0, ErrFieldLength
+		}
+		var err error
+		length, err = strconv.Atoi(prefix)
+		if err != nil || length < 0 || length > f.Length {
+			return "", 0, ErrFieldLength
+		}
+		read = digits
+	}
+	if len(data) < read+length {
+		return "", 0, ErrShortMessage
+	}
+	value := string(data[read : read+length])
+	if err := f.validate(value); err != nil {
+		return "", 0, err
+	}
+	if f.Prefix == Fixed && f.Format == Alpha {
+		value = strings.TrimRight(value, " ")
+	}
+	return value, read + length, nil
+}
+
+func (s *Spec) packBitmap(bitmap []byte) []byte {
+	if s.HexBitmap {
+		return []byte(strings.ToUpper(hex.EncodeToString(bitmap)))
+	}
+	return bitmap
+}
+
+func (s *Spec) unpackBitmap(data []byte) ([]byte, int, error) {
+	if !s.HexBitmap {
+		if len(data) < bitmapSize {
+			return nil, 0, ErrShortMessage
+		}
+		return data[:bitmapSize], bitmapSize, nil
+	}
+	if len(data) < bitmapSize*2 {
+		return nil, 0, ErrShortMessage
+	}
+	bitmap, err := hex.DecodeString(string(data[:bitmapSize*2]))
+	if err != nil {
+		return nil, 0, ErrFieldFormat
+	}
+	return bitmap, bitmapSize * 2, nil
+}
+
+// Pack encodes the message as MTI, bitmaps and fields in ascending order.
+// Secondary bitmap is added when any of fields 65-128 is present.
+func (s *Spec) Pack(m *Message) ([]byte, error) {
+	if !validMTI(m.MTI) {
+		return nil, ErrInvalidMTI
+	}
+
+	bitmap := make([]byte, bitmapSize*2)
+	var body []byte
+	for _, number := range m.Fields() {
+		field, ok := s.Fields[number]
+		if !ok || number < 2 || number > maxField {
+			return nil, fmt.Errorf("field %d: %w", number, ErrUnknownField)
+		}
+		packed, err := field.pack(m.fields[number])
+		if err != nil {
+			return nil, fmt.Errorf("field %d: %w", number, err)
+		}
+		bitmap[(number-1)/8] |= 0x80 >> ((number - 1) % 8)
+		body = append(body, packed...)
+	}
+
+	data := []byte(m.MTI)
+	secondary := false
+	for _, b := range bitmap[bitmapSize:] {
+		secondary = secondary || b != 0
+	}
+	if secondary {
+		bitmap[0] |= 0x80
+		data = append(data, s.packBitmap(bitmap)...)
+	} else {
+		data = append(data, s.packBitmap(bitmap[:bitmapSize])...)
+	}
+	return append(data, body...), nil
+}
+
+// Unpack decodes the message packed by the peer with the same spec.
+func (s *Spec) Unpack(data []byte) (*Message, error) {
+	if len(data) < 4 {
+		return nil, ErrShortMessage
+	}
+	m := NewMessage(string(data[:4]))
+	if !validMTI(m.MTI) {
+		return nil, ErrInvalidMTI
+	}
+	offset := 4
+
+	bitmap, read, err := s.unpackBitmap(data[offset:])
+	if err != nil {
+		return nil, err
+	}
+	offset += read
+	if bitmap[0]&0x80 != 0 {
+		secondary, read, err := s.unpackBitmap(data[offset:])
+		if err != nil {
+			return nil, err
+		}
+		offset += read
+		bitmap = append(append([]byte{}, bitmap...), secondary...)
+	}
+
+	for number := 2; number <= len(bitmap)*8; number++ {
+		if bitmap[(number-1)/8]&(0x80>>((number-1)%8)) == 0 {
+			continue
+		}
+		field, ok := s.Fields[number]
+		if !ok {
+			return nil, fmt.Errorf("field %d: %w", number, ErrUnknownField)
+		}
+		value, read, err := field.unpack(data[offset:])
+		if err != nil {
+			return nil, fmt.Errorf("field %d: %w", number, err)
+		}
+		m.fields[number] = value
+		offset += read
+	}
+	if offset != len(data) {
+		return nil, ErrFieldLength
+	}
+
+	return m, nil
+}
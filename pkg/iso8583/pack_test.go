@@ -0,0 +1,247 @@
+package iso8583
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func hexSpec() *Spec {
+	spec := DefaultSpec()
+	spec.HexBitmap = true
+	return spec
+}
+
+func TestPackUnpackRoundTrip(t *testing.T) {
+	pinBlock := string([]byte{0x04, 0x12, 0x25, 0xee, 0x00, 0xee, 0x0a, 0xee})
+
+	tests := []struct {
+		name   string
+		mti    string
+		fields map[int]string
+	}{
+		{
+			name: "primary bitmap only",
+			mti:  "0200",
+			fields: map[int]string{
+				2:  "4111111111111111",
+				3:  "010000",
+				4:  "000000005000",
+				11: "000123",
+				41: "ATM00001",
+			},
+		},
+		{
+			name: "secondary bitmap",
+			mti:  "0800",
+			fields: map[int]string{
+				7:  "1019103000",
+				11: "000001",
+				70: "001",
+			},
+		},
+		{
+			name: "binary field",
+			mti:  "0100",
+			fields: map[int]string{
+				2:  "4111111111111111",
+				52: pinBlock,
+			},
+		},
+		{
+			name: "empty and max variable fields",
+			mti:  "0420",
+			fields: map[int]string{
+				32: "",
+				2:  "1234567890123456789",
+				90: "020000012310191030000000000000000000000000",
+			},
+		},
+	}
+
+	specs := map[string]*Spec{
+		"binary bitmap": DefaultSpec(),
+		"hex bitmap":    hexSpec(),
+	}
+	for specName, spec := range specs {
+		for _, tt := range tests {
+			t.Run(specName+"/"+tt.name, func(t *testing.T) {
+				m := NewMessage(tt.mti)
+				for field, value := range tt.fields {
+					m.Set(field, value)
+				}
+
+				data, err := spec.Pack(m)
+				if err != nil {
+					t.Fatalf("pack: unexpected error: %s", err)
+				}
+				unpacked, err := spec.Unpack(data)
+				if err != nil {
+					t.Fatalf("unpack: unexpected error: %s", err)
+				}
+
+				if unpacked.MTI != tt.mti {
+					t.Errorf("MTI: expected %s, got %s", tt.mti, unpacked.MTI)
+				}
+				if !reflect.DeepEqual(unpacked.Fields(), m.Fields()) {
+					t.Fatalf("fields: expected %v, got %v", m.Fields(), unpacked.Fields())
+				}
+				for field, value := range tt.fields {
+					if got := unpacked.Get(field); got != value {
+						t.Errorf("field %d: expected %q, got %q", field, value, got)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestPackPadding(t *testing.T) {
+	m := NewMessage("0200")
+	m.Set(4, "5000")
+	m.Set(41, "ATM1")
+
+	data, err := hexSpec().Pack(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := "0200" + "1000000000800000" + "000000005000" + "ATM1    "
+	if string(data) != expected {
+		t.Fatalf("expected %q, got %q", expected, data)
+	}
+
+	unpacked, err := hexSpec().Unpack(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if unpacked.Get(4) != "000000005000" || unpacked.Get(41) != "ATM1" {
+		t.Fatalf("unexpected fields %q, %q", unpacked.Get(4), unpacked.Get(41))
+	}
+}
+
+func TestPackBitmap(t *testing.T) {
+	m := NewMessage("0800")
+	m.Set(11, "000001")
+	m.Set(70, "001")
+
+	tests := []struct {
+		name     string
+		spec     *Spec
+		expected []byte
+	}{
+		{
+			name:     "hex",
+			spec:     hexSpec(),
+			expected: []byte("0800" + "8020000000000000" + "0400000000000000" + "000001" + "001"),
+		},
+		{
+			name: "binary",
+			spec: DefaultSpec(),
+			expected: append(append([]byte("0800"),
+				0x80, 0x20, 0, 0, 0, 0, 0, 0,
+				0x04, 0, 0, 0, 0, 0, 0, 0), "000001001"...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.spec.Pack(m)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !bytes.Equal(data, tt.expected) {
+				t.Fatalf("expected %q, got %q", tt.expected, data)
+			}
+		})
+	}
+}
+
+func TestPackErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		mti    string
+		field  int
+		value  string
+		expErr error
+	}{
+		{name: "invalid MTI", mti: "02A0", field: 3, value: "010000", expErr: ErrInvalidMTI},
+		{name: "short MTI", mti: "200", field: 3, value: "010000", expErr: ErrInvalidMTI},
+		{name: "unknown field", mti: "0200", field: 5, value: "1", expErr: ErrUnknownField},
+		{name: "field 1 is bitmap", mti: "0200", field: 1, value: "1", expErr: ErrUnknownField},
+		{name: "fixed too long", mti: "0200", field: 3, value: "0100000", expErr: ErrFieldLength},
+		{name: "variable too long", mti: "0200", field: 2, value: "12345678901234567890", expErr: ErrFieldLength},
+		{name: "numeric with letters", mti: "0200", field: 4, value: "10O", expErr: ErrFieldFormat},
+		{name: "alpha with control characters", mti: "0200", field: 41, value: "ATM\n", expErr: ErrFieldFormat},
+		{name: "short binary", mti: "0200", field: 52, value: "1234", expErr: ErrFieldLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMessage(tt.mti)
+			m.Set(tt.field, tt.value)
+			if _, err := DefaultSpec().Pack(m); !errors.Is(err, tt.expErr) {
+				t.Fatalf("expected %s, got %v", tt.expErr, err)
+			}
+		})
+	}
+}
+
+func TestUnpackMalformed(t *testing.T) {
+	// field 2 in primary bitmap
+	const pan = "0200" + "4000000000000000"
+	// field 48 in primary bitmap
+	const additional = "0200" + "0000000000010000"
+
+	tests := []struct {
+		name   string
+		data   string
+		expErr error
+	}{
+		{name: "empty", data: "", expErr: ErrShortMessage},
+		{name: "truncated MTI", data: "020", expErr: ErrShortMessage},
+		{name: "invalid MTI", data: "02A0" + "0000000000000000", expErr: ErrInvalidMTI},
+		{name: "truncated bitmap", data: "0200" + "40000000", expErr: ErrShortMessage},
+		{name: "bitmap isn't hex", data: "0200" + "40000000000000ZZ", expErr: ErrFieldFormat},
+		{name: "truncated secondary bitmap", data: "0800" + "8000000000000000", expErr: ErrShortMessage},
+		{name: "unknown field", data: "0200" + "0800000000000000" + "1", expErr: ErrUnknownField},
+		{name: "truncated length prefix", data: pan + "1", expErr: ErrShortMessage},
+		{name: "negative length prefix", data: pan + "-1" + "4", expErr: ErrFieldLength},
+		{name: "signed length prefix", data: pan + "+5" + "41111", expErr: ErrFieldLength},
+		{name: "space in length prefix", data: pan + " 5" + "41111", expErr: ErrFieldLength},
+		{name: "hex length prefix", data: pan + "0F" + "411111111111111", expErr: ErrFieldLength},
+		{name: "length over max", data: pan + "20" + "41111111111111111111", expErr: ErrFieldLength},
+		{name: "truncated variable field", data: pan + "16" + "4111111111", expErr: ErrShortMessage},
+		{name: "negative LLL prefix", data: additional + "-01" + "A", expErr: ErrFieldLength},
+		{name: "truncated LLL prefix", data: additional + "00", expErr: ErrShortMessage},
+		{name: "truncated fixed field", data: "0200" + "2000000000000000" + "0100", expErr: ErrShortMessage},
+		{name: "numeric with letters", data: "0200" + "2000000000000000" + "01000A", expErr: ErrFieldFormat},
+		{name: "trailing bytes", data: pan + "16" + "4111111111111111" + "00", expErr: ErrFieldLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := hexSpec().Unpack([]byte(tt.data)); !errors.Is(err, tt.expErr) {
+				t.Fatalf("expected %s, got %v", tt.expErr, err)
+			}
+		})
+	}
+}
+
+func TestUnpackBinaryBitmapTruncated(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "primary", data: []byte{'0', '2', '0', '0', 0x40, 0, 0}},
+		{name: "secondary", data: []byte{'0', '8', '0', '0', 0x80, 0, 0, 0, 0, 0, 0, 0, 0x04}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DefaultSpec().Unpack(tt.data); !errors.Is(err, ErrShortMessage) {
+				t.Fatalf("expected %s, got %v", ErrShortMessage, err)
+			}
+		})
+	}
+}
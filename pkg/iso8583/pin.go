@@ -0,0 +1,72 @@
+package iso8583
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrPinBlock = errors.New("invalid pin block")
+
+// panBlock is the PAN part of ISO 9564 format 0 PIN block: four zeros and
+// rightmost 12 digits of PAN excluding check digit.
+func panBlock(pan string) ([]byte, error) {
+	if len(pan) < 13 || !isDigits(pan) {
+		return nil, ErrPinBlock
+	}
+	digits := pan[len(pan)-13 : len(pan)-1]
+	return hex.DecodeString("0000" + digits)
+}
+
+// EncodePinBlock returns ISO 9564 format 0 PIN block of 8 bytes. The block
+// isn't encrypted, it must only be sent over an encrypted link such as TLS.
+func EncodePinBlock(pin string, pan string) ([]byte, error) {
+	if len(pin) < 4 || len(pin) > 12 || !isDigits(pin) {
+		return nil, ErrPinBlock
+	}
+	pinField := fmt.Sprintf("0%X%s", len(pin), pin)
+	pinField += strings.Repeat("F", 16-len(pinField))
+	block, err := hex.DecodeString(pinField)
+	if err != nil {
+		return nil, ErrPinBlock
+	}
+	account, err := panBlock(pan)
+	if err != nil {
+		return nil, err
+	}
+	for i := range block {
+		block[i] ^= account[i]
+	}
+	return block, nil
+}
+
+// DecodePinBlock returns PIN from ISO 9564 format 0 PIN block, see EncodePinBlock.
+func DecodePinBlock(block []byte, pan string) (string, error) {
+	if len(block) != 8 {
+		return "", ErrPinBlock
+	}
+	account, err := panBlock(pan)
+	if err != nil {
+		return "", err
+	}
+	clear := make([]byte, len(block))
+	for i := range block {
+		clear[i] = block[i] ^ account[i]
+	}
+
+	pinField := strings.ToUpper(hex.EncodeToString(clear))
+	if pinField[0] != '0' {
+		return "", ErrPinBlock
+	}
+	length, err := strconv.ParseUint(pinField[1:2], 16, 8)
+	if err != nil || length < 4 || length > 12 {
+		return "", ErrPinBlock
+	}
+	pin := pinField[2 : 2+length]
+	if !isDigits(pin) || strings.Trim(pinField[2+length:], "F") != "" {
+		return "", ErrPinBlock
+	}
+	return pin, nil
+}
@@ -0,0 +1,89 @@
+package iso8583
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncodePinBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		pin      string
+		pan      string
+		expected string
+		expErr   error
+	}{
+		{name: "4 digits", pin: "1234", pan: "4111111111111111", expected: "041225EEEEEEEEEE"},
+		{name: "12 digits", pin: "123456789012", pan: "5500000000000004", expected: "0C123456789012FF"},
+		{name: "13 digits PAN", pin: "0000", pan: "4222222222222", expected: "040042DDDDDDDDDD"},
+		{name: "short PIN", pin: "123", pan: "4111111111111111", expErr: ErrPinBlock},
+		{name: "long PIN", pin: "1234567890123", pan: "4111111111111111", expErr: ErrPinBlock},
+		{name: "PIN with letters", pin: "12a4", pan: "4111111111111111", expErr: ErrPinBlock},
+		{name: "short PAN", pin: "1234", pan: "411111111111", expErr: ErrPinBlock},
+		{name: "PAN with letters", pin: "1234", pan: "41111111111111X1", expErr: ErrPinBlock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block, err := EncodePinBlock(tt.pin, tt.pan)
+			if !errors.Is(err, tt.expErr) {
+				t.Fatalf("expected error %v, got %v", tt.expErr, err)
+			}
+			if tt.expErr != nil {
+				return
+			}
+			if got := strings.ToUpper(hex.EncodeToString(block)); got != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, got)
+			}
+
+			pin, err := DecodePinBlock(block, tt.pan)
+			if err != nil {
+				t.Fatalf("decode: unexpected error: %s", err)
+			}
+			if pin != tt.pin {
+				t.Fatalf("decode: expected %s, got %s", tt.pin, pin)
+			}
+		})
+	}
+}
+
+func TestDecodePinBlock(t *testing.T) {
+	const pan = "4111111111111111"
+
+	tests := []struct {
+		name     string
+		block    string
+		pan      string
+		expected string
+		expErr   error
+	}{
+		{name: "valid", block: "041225EEEEEEEEEE", pan: pan, expected: "1234"},
+		{name: "another PAN", block: "041225EEEEEEEEEE", pan: "5500000000000004", expErr: ErrPinBlock},
+		{name: "short block", block: "041225EEEEEEEE", pan: pan, expErr: ErrPinBlock},
+		{name: "long block", block: "041225EEEEEEEEEE00", pan: pan, expErr: ErrPinBlock},
+		{name: "not format 0", block: "141225EEEEEEEEEE", pan: pan, expErr: ErrPinBlock},
+		{name: "PIN length under 4", block: "031225EEEEEEEEEE", pan: pan, expErr: ErrPinBlock},
+		{name: "PIN length over 12", block: "0D1225EEEEEEEEEE", pan: pan, expErr: ErrPinBlock},
+		{name: "PIN with hex digits", block: "04122BEEEEEEEEEE", pan: pan, expErr: ErrPinBlock},
+		{name: "padding isn't F", block: "041225EEEEEEEEEF", pan: pan, expErr: ErrPinBlock},
+		{name: "invalid PAN", block: "041225EEEEEEEEEE", pan: "4111", expErr: ErrPinBlock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block, err := hex.DecodeString(tt.block)
+			if err != nil {
+				t.Fatalf("invalid block in test: %s", err)
+			}
+			pin, err := DecodePinBlock(block, tt.pan)
+			if !errors.Is(err, tt.expErr) {
+				t.Fatalf("expected error %v, got %v", tt.expErr, err)
+			}
+			if pin != tt.expected {
+				t.Fatalf("expected %q, got %q", tt.expected, pin)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package iso8583
+
+// LengthType tells how length of the field is known.
+type LengthType int
+
+const (
+	Fixed LengthType = iota
+	// LLVar and LLLVar fields are prefixed with 2 or 3 ASCII digits of length
+	LLVar
+	LLLVar
+)
+
+// Format is character set allowed in the field.
+type Format int
+
+const (
+	// Numeric fixed fields are padded with leading zeros
+	Numeric Format = iota
+	// Alpha fixed fields are padded with trailing spaces, any printable ASCII is allowed
+	Alpha
+	// Binary fields are raw bytes, Length is in bytes
+	Binary
+)
+
+type Field struct {
+	Name string
+	// Length of fixed field or max length of variable field
+	Length int
+	Prefix LengthType
+	Format Format
+}
+
+// Spec describes data elements exchanged with the peer. Fields missing in the
+// spec are refused both when packing and unpacking.
+type Spec struct {
+	Fields map[int]Field
+	// HexBitmap encodes bitmaps as 16 hex characters instead of 8 raw bytes
+	HexBitmap bool
+}
+
+// DefaultSpec is the ASCII variant of ISO 8583:1987 data elements used by ATM
+// and POS acquirers.
+func DefaultSpec() *Spec {
+	return &Spec{
+		Fields: map[int]Field{
+			2:  {Name: "Primary account number", Length: 19, Prefix: LLVar, Format: Numeric},
+			3:  {Name: "Processing code", Length: 6, Format: Numeric},
+			4:  {Name: "Amount, transaction", Length: 12, Format: Numeric},
+			7:  {Name: "Transmission date and time", Length: 10, Format: Numeric},
+			11: {Name: "System trace audit number", Length: 6, Format: Numeric},
+			12: {Name: "Time, local transaction", Length: 6, Format: Numeric},
+			13: {Name: "Date, local transaction", Length: 4, Format: Numeric},
+			14: {Name: "Date, expiration", Length: 4, Format: Numeric},
+			15: {Name: "Date, settlement", Length: 4, Format: Numeric},
+			18: {Name: "Merchant type", Length: 4, Format: Numeric},
+			22: {Name: "Point of service entry mode", Length: 3, Format: Numeric},
+			25: {Name: "Point of service condition code", Length: 2, Format: Numeric},
+			32: {Name: "Acquiring institution identification code", Length: 11, Prefix: LLVar, Format: Numeric},
+			35: {Name: "Track 2 data", Length: 37, Prefix: LLVar, Format: Alpha},
+			37: {Name: "Retrieval reference number", Length: 12, Format: Alpha},
+			38: {Name: "Authorization identification response", Length: 6, Format: Alpha},
+			39: {Name: "Response code", Length: 2, Format: Alpha},
+			41: {Name: "Card acceptor terminal identification", Length: 8, Format: Alpha},
+			42: {Name: "Card acceptor identification code", Length: 15, Format: Alpha},
+			43: {Name: "Card acceptor name/location", Length: 40, Format: Alpha},
+			48: {Name: "Additional data, private", Length: 999, Prefix: LLLVar, Format: Alpha},
+			49: {Name: "Currency code, transaction", Length: 3, Format: Numeric},
+			52: {Name: "Personal identification number data", Length: 8, Format: Binary},
+			54: {Name: "Additional amounts", Length: 120, Prefix: LLLVar, Format: Alpha},
+			70: {Name: "Network management information code", Length: 3, Format: Numeric},
+			90: {Name: "Original data elements", Length: 42, Format: Numeric},
+			95: {Name: "Replacement amounts", Length: 42, Format: Alpha},
+		},
+	}
+}
@@ -0,0 +1,339 @@
+package tokens
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	ErrNoSigningKey = errors.New("no signing key")
+)
+
+const (
+	keyFileExt            = ".pem"
+	keyRotationCheckEvery = time.Minute
+	// tokens with unknown kid make the set reread dir at most this often
+	keyReloadMinInterval = 5 * time.Second
+)
+
+type signingKey struct {
+	kid       string
+	createdAt time.Time
+	private   ed25519.PrivateKey
+	public    ed25519.PublicKey
+}
+
+// KeySet holds Ed25519 signing keys stored as PKCS8 PEM files <kid>.pem in dir.
+// The newest key signs tokens, all keys in the set verify them. The dir may be
+// shared between instances: keys created by one instance are picked up by others.
+type KeySet struct {
+	mu       sync.RWMutex
+	dir      string
+	keys     map[string]signingKey
+	active   signingKey
+	loadedAt time.Time
+	reloads  singleflight.Group
+}
+
+// NewKeySet loads keys from dir and creates the first key if there are none.
+func NewKeySet(dir string) (*KeySet, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	ks := &KeySet{
+		dir:  dir,
+		keys: map[string]signingKey{},
+	}
+	if err := ks.Load(); err != nil {
+		return nil, err
+	}
+	if len(ks.keys) == 0 {
+		if err := ks.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+	return ks, nil
+}
+
+func parseKid(kid string) (time.Time, error) {
+	created, _, ok := strings.Cut(kid, "-")
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid kid %q", kid)
+	}
+	unix, err := strconv.ParseInt(created, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid kid %q: %w", kid, err)
+	}
+	return time.Unix(unix, 0), nil
+}
+
+func (ks *KeySet) readKey(kid string) (signingKey, error) {
+	var key signingKey
+
+	createdAt, err := parseKid(kid)
+	if err != nil {
+		return key, err
+	}
+	data, err := os.ReadFile(filepath.Join(ks.dir, kid+keyFileExt))
+	if err != nil {
+		return key, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return key, fmt.Errorf("invalid pem of key %s", kid)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return key, err
+	}
+	private, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return key, fmt.Errorf("key %s isn't ed25519", kid)
+	}
+
+	return signingKey{
+		kid:       kid,
+		createdAt: createdAt,
+		private:   private,
+		public:    private.Public().(ed25519.PublicKey),
+	}, nil
+}
+
+// Load rereads keys from dir.
+func (ks *KeySet) Load() error {
+	entries, err := os.ReadDir(ks.dir)
+	if err != nil {
+		return err
+	}
+
+	keys := map[string]signingKey{}
+	var active signingKey
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != keyFileExt {
+			continue
+		}
+		kid := strings.TrimSuffix(entry.Name(), keyFileExt)
+		key, err := ks.readKey(kid)
+		if err != nil {
+			logrus.Errorf("[tokens]: error reading signing key %s: %s", kid, err)
+			continue
+		}
+		keys[kid] = key
+		if key.createdAt.After(active.createdAt) ||
+			(key.createdAt.Equal(active.createdAt) && key.kid > active.kid) {
+			active = key
+		}
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.active = active
+	ks.loadedAt = time.Now()
+	ks.mu.Unlock()
+
+	return nil
+}
+
+// Rotate creates new key which becomes active. Previous keys stay valid for verification.
+func (ks *KeySet) Rotate() error {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	now := time.Now()
+	kid := fmt.Sprintf("%d-%s", now.Unix(), hex.EncodeToString(suffix))
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return err
+	}
+	data := pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: der,
+	})
+
+	// write to temp file first so other instances never read half-written key
+	tmp := filepath.Join(ks.dir, "."+kid+".tmp")
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(ks.dir, kid+keyFileExt)); err != nil {
+		return err
+	}
+
+	key := signingKey{
+		kid:       kid,
+		createdAt: time.Unix(now.Unix(), 0),
+		private:   private,
+		public:    public,
+	}
+	ks.mu.Lock()
+	ks.keys[kid] = key
+	ks.active = key
+	ks.mu.Unlock()
+
+	logrus.Printf("[tokens]: signing key rotated, new kid %s", kid)
+
+	return nil
+}
+
+// Prune deletes keys retired longer than retention ago. A key is retired when
+// the next key is created, so retention must be not less than the max token TTL.
+func (ks *KeySet) Prune(retention time.Duration) error {
+	ks.mu.RLock()
+	keys := make([]signingKey, 0, len(ks.keys))
+	for _, key := range ks.keys {
+		keys = append(keys, key)
+	}
+	ks.mu.RUnlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].createdAt.Before(keys[j].createdAt)
+	})
+
+	for i := 0; i < len(keys)-1; i++ {
+		retiredAt := keys[i+1].createdAt
+		if time.Since(retiredAt) <= retention {
+			continue
+		}
+		err := os.Remove(filepath.Join(ks.dir, keys[i].kid+keyFileExt))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		ks.mu.Lock()
+		delete(ks.keys, keys[i].kid)
+		ks.mu.Unlock()
+	}
+
+	return nil
+}
+
+// RunRotation rotates active key every period and prunes retired keys until ctx is done.
+func (ks *KeySet) RunRotation(ctx context.Context, period time.Duration, retention time.Duration) {
+	ticker := time.NewTicker(keyRotationCheckEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// other instances may have rotated already
+		if err := ks.Load(); err != nil {
+			logrus.Errorf("[tokens]: error loading signing keys: %s", err)
+			continue
+		}
+		if time.Since(ks.signing().createdAt) >= period {
+			if err := ks.Rotate(); err != nil {
+				logrus.Errorf("[tokens]: error rotating signing key: %s", err)
+			}
+		}
+		if err := ks.Prune(retention); err != nil {
+			logrus.Errorf("[tokens]: error pruning signing keys: %s", err)
+		}
+	}
+}
+
+func (ks *KeySet) signing() signingKey {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return ks.active
+}
+
+func (ks *KeySet) publicKey(kid string) (ed25519.PublicKey, bool) {
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	ks.mu.RUnlock()
+	if ok {
+		return key.public, true
+	}
+
+	// the key may be created by another instance after the last load
+	if _, err := parseKid(kid); err != nil {
+		return nil, false
+	}
+	ks.reload()
+
+	ks.mu.RLock()
+	key, ok = ks.keys[kid]
+	ks.mu.RUnlock()
+	return key.public, ok
+}
+
+// reload rereads keys unless they were loaded recently. Concurrent calls share
+// one load, so requests with made up kids can't make every request read dir.
+func (ks *KeySet) reload() {
+	ks.reloads.Do("load", func() (interface{}, error) {
+		ks.mu.RLock()
+		fresh := time.Since(ks.loadedAt) < keyReloadMinInterval
+		ks.mu.RUnlock()
+		if fresh {
+			return nil, nil
+		}
+		if err := ks.Load(); err != nil {
+			logrus.Errorf("[tokens]: error loading signing keys: %s", err)
+		}
+		return nil, nil
+	})
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns public keys of the set in RFC 8037 format.
+func (ks *KeySet) JWKS() JWKS {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	jwks := JWKS{
+		Keys: make([]JWK, 0, len(ks.keys)),
+	}
+	for _, key := range ks.keys {
+		jwks.Keys = append(jwks.Keys, JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key.public),
+			Kid: key.kid,
+			Use: "sig",
+			Alg: "EdDSA",
+		})
+	}
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].Kid > jwks.Keys[j].Kid
+	})
+
+	return jwks
+}
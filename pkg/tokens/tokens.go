@@ -14,36 +14,60 @@ var (
 	ErrTokenInvalid = errors.New("token is invalid")
 )
 
+// AccessTokenType is typ claim of access tokens. Tokens of other kinds are
+// signed with the same keys and must not be accepted as access tokens.
+const AccessTokenType = "access"
+
 type TokenManagerInterface interface {
-	CreateAccessToken(userId uuid.UUID) (string, error)
+	CreateAccessToken(userId uuid.UUID, role string, sessionId uuid.UUID) (string, error)
 	CreateEmailToken(email string) (string, error)
-	ParseAccessToken(tokenString string) (uuid.UUID, error)
+	ParseAccessToken(tokenString string) (*ClaimsAccessToken, error)
 	ParseEmailToken(tokenString string) (string, error)
+	CreateMachineToken(machineId uuid.UUID, tokenId uuid.UUID) (string, error)
+	ParseMachineToken(tokenString string) (*ClaimsMachineToken, error)
+	JWKS() JWKS
 }
 
+// TokenManager signs tokens with EdDSA keys from the key set. Tokens signed
+// with HS256 and the shared secret are accepted while AllowHS256 is set,
+// until all of them are expired after migration.
 type TokenManager struct {
-	secretKey string
-	accessTTL time.Duration
-	emailTTL  time.Duration
+	keys       *KeySet
+	secretKey  string
+	allowHS256 bool
+	accessTTL  time.Duration
+	emailTTL   time.Duration
+	machineTTL time.Duration
 }
 
 type Config struct {
-	SecretKey string
-	AccessTTL time.Duration
-	EmailTTL  time.Duration
+	Keys       *KeySet
+	SecretKey  string
+	AllowHS256 bool
+	AccessTTL  time.Duration
+	EmailTTL   time.Duration
+	MachineTTL time.Duration
 }
 
 func NewTokenManager(cfg Config) *TokenManager {
 	return &TokenManager{
-		secretKey: cfg.SecretKey,
-		accessTTL: cfg.AccessTTL,
-		emailTTL:  cfg.EmailTTL,
+		keys:       cfg.Keys,
+		secretKey:  cfg.SecretKey,
+		allowHS256: cfg.AllowHS256,
+		accessTTL:  cfg.AccessTTL,
+		emailTTL:   cfg.EmailTTL,
+		machineTTL: cfg.MachineTTL,
 	}
 }
 
 type ClaimsAccessToken struct {
 	jwt.StandardClaims
-	Id uuid.UUID `json:"id"`
+	Id        uuid.UUID `json:"id"`
+	Role      string    `json:"role"`
+	SessionId uuid.UUID `json:"sid"`
+	Type      string    `json:"typ"`
+	// IssuedAtMicro is issue time in unix microseconds, iat has only seconds
+	IssuedAtMicro int64 `json:"iatMicro,omitempty"`
 }
 
 type ClaimsEmailToken struct {
@@ -51,10 +75,19 @@ type ClaimsEmailToken struct {
 	Email string `json:"email"`
 }
 
+// legacyAccessClaims tells HS256 access tokens issued before typ claim was
+// introduced from machine and email tokens of that time.
+type legacyAccessClaims struct {
+	ClaimsAccessToken
+	IsMachine bool `json:"isMachine"`
+}
+
+// ClaimsMachineToken is issued to ATM at provisioning. StandardClaims.Id holds
+// token id, only the last issued token of the machine is valid.
 type ClaimsMachineToken struct {
 	jwt.StandardClaims
 	Id        uuid.UUID `json:"id"`
-	IsMachine struct{}  `json:"isMachine"`
+	IsMachine bool      `json:"isMachine"`
 }
 
 func (tm *TokenManager) createStandartClaims(ttl time.Duration) jwt.StandardClaims {
@@ -65,24 +98,73 @@ func (tm *TokenManager) createStandartClaims(ttl time.Duration) jwt.StandardClai
 }
 
 func (tm *TokenManager) createJWTToken(claims jwt.Claims) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(tm.secretKey))
+	key := tm.keys.signing()
+	if key.private == nil {
+		return "", ErrNoSigningKey
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = key.kid
+	return token.SignedString(key.private)
+}
+
+func (tm *TokenManager) keyFunc(t *jwt.Token) (interface{}, error) {
+	switch t.Method {
+	case jwt.SigningMethodEdDSA:
+		kid, ok := t.Header["kid"].(string)
+		if !ok {
+			return nil, ErrTokenInvalid
+		}
+		key, ok := tm.keys.publicKey(kid)
+		if !ok {
+			return nil, ErrTokenInvalid
+		}
+		return key, nil
+	case jwt.SigningMethodHS256:
+		if !tm.allowHS256 || tm.secretKey == "" {
+			return nil, ErrTokenInvalid
+		}
+		return []byte(tm.secretKey), nil
+	}
+	return nil, ErrTokenInvalid
+}
+
+func (tm *TokenManager) parseJWTToken(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	token, err := jwt.ParseWithClaims(tokenString, claims, tm.keyFunc)
+	if err != nil {
+		var validationErr *jwt.ValidationError
+		if errors.As(err, &validationErr) && validationErr.Errors == jwt.ValidationErrorExpired {
+			return nil, ErrTokenExpired
+		}
+		return nil, err
+	}
+	return token, nil
 }
 
-func (tm *TokenManager) CreateAccessToken(userId uuid.UUID) (string, error) {
+func (tm *TokenManager) JWKS() JWKS {
+	return tm.keys.JWKS()
+}
+
+func (tm *TokenManager) CreateAccessToken(userId uuid.UUID, role string,
+	sessionId uuid.UUID) (string, error) {
 	return tm.createJWTToken(&ClaimsAccessToken{
 		tm.createStandartClaims(tm.accessTTL),
 		userId,
+		role,
+		sessionId,
+		AccessTokenType,
+		time.Now().UnixMicro(),
 	})
 }
 
-// func (tm *TokenManager) CreateMachineToken(machineId uuid.UUID) (string, error) {
-// 	return tm.createJWTToken(ClaimsMachineToken{
-// 		tm.createStandartClaims(time.Hour * 24 * 365 * 100),
-// 		machineId,
-// 		struct{}{},
-// 	})
-// }
+func (tm *TokenManager) CreateMachineToken(machineId uuid.UUID, tokenId uuid.UUID) (string, error) {
+	claims := tm.createStandartClaims(tm.machineTTL)
+	claims.Id = tokenId.String()
+	return tm.createJWTToken(&ClaimsMachineToken{
+		claims,
+		machineId,
+		true,
+	})
+}
 
 func (tm *TokenManager) CreateEmailToken(email string) (string, error) {
 	return tm.createJWTToken(&ClaimsEmailToken{
@@ -91,91 +173,52 @@ func (tm *TokenManager) CreateEmailToken(email string) (string, error) {
 	})
 }
 
-func (tm *TokenManager) ParseAccessToken(tokenString string) (uuid.UUID, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &ClaimsAccessToken{}, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			logrus.Println("error invalid")
-			return nil, ErrTokenInvalid
-		}
-		claims, ok := t.Claims.(*ClaimsAccessToken)
-		if !ok {
-			return nil, ErrTokenInvalid
-		}
-		if claims.ExpiresAt <= time.Now().Unix() {
-			return nil, ErrTokenExpired
-		}
-		return []byte(tm.secretKey), nil
-	})
-
+// ParseAccessToken returns claims of the token. HS256 tokens without typ claim
+// are issued before it was introduced, they are access tokens unless they
+// belong to a machine or carry no user.
+func (tm *TokenManager) ParseAccessToken(tokenString string) (*ClaimsAccessToken, error) {
+	claims := &legacyAccessClaims{}
+	token, err := tm.parseJWTToken(tokenString, claims)
 	if err != nil {
 		logrus.Errorf("[tokens]: error parsing access token: %s", err)
 		if errors.Is(ErrTokenExpired, err) {
-			return uuid.UUID{}, ErrTokenExpired
+			return nil, ErrTokenExpired
 		}
-		return uuid.UUID{}, ErrTokenInvalid
+		return nil, ErrTokenInvalid
 	}
-
-	claims, ok := token.Claims.(*ClaimsAccessToken)
-	if !ok {
-		return uuid.UUID{}, ErrTokenInvalid
+	if claims.Type == "" && token.Method == jwt.SigningMethodHS256 && !claims.IsMachine &&
+		claims.Id != uuid.Nil {
+		claims.Type = AccessTokenType
 	}
 
-	return claims.Id, nil
+	return &claims.ClaimsAccessToken, nil
 }
 
 func (tm *TokenManager) ParseEmailToken(tokenString string) (string, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &ClaimsEmailToken{}, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrTokenInvalid
-		}
-		claims, ok := t.Claims.(*ClaimsEmailToken)
-		if !ok {
-			return nil, ErrTokenInvalid
-		}
-		if claims.ExpiresAt <= time.Now().Unix() {
-			return nil, ErrTokenExpired
-		}
-		return []byte(tm.secretKey), nil
-	})
-
-	if err != nil {
+	claims := &ClaimsEmailToken{}
+	if _, err := tm.parseJWTToken(tokenString, claims); err != nil {
 		logrus.Errorf("[tokens]: error parsing email token: %s", err)
 		if errors.Is(ErrTokenExpired, err) {
 			return "", ErrTokenExpired
 		}
 		return "", ErrTokenInvalid
 	}
-	claims, ok := token.Claims.(*ClaimsEmailToken)
-	if !ok {
-		return "", ErrTokenInvalid
-	}
 
 	return claims.Email, nil
 }
 
-// func (tm *TokenManager) ParseMachineToken(tokenString string) (uuid.UUID, error) {
-// 	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-// 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-// 			return nil, ErrTokenInvalid
-// 		}
-// 		claims, ok := t.Claims.(*ClaimsMachineToken)
-// 		if !ok {
-// 			return nil, ErrTokenInvalid
-// 		}
-// 		if claims.ExpiresAt <= time.Now().Unix() {
-// 			return nil, ErrTokenExpired
-// 		}
-// 		return []byte(tm.secretKey), nil
-// 	})
-
-// 	if err != nil {
-// 		return uuid.UUID{}, err
-// 	}
-
-// 	claims, ok := token.Claims.(*ClaimsMachineToken)
-// 	if !ok {
-// 		return uuid.UUID{}, ErrTokenInvalid
-// 	}
-
-// 	return claims.Id, nil
-// }
+func (tm *TokenManager) ParseMachineToken(tokenString string) (*ClaimsMachineToken, error) {
+	claims := &ClaimsMachineToken{}
+	if _, err := tm.parseJWTToken(tokenString, claims); err != nil {
+		logrus.Errorf("[tokens]: error parsing machine token: %s", err)
+		if errors.Is(ErrTokenExpired, err) {
+			return nil, ErrTokenExpired
+		}
+		return nil, ErrTokenInvalid
+	}
+	if !claims.IsMachine {
+		return nil, ErrTokenInvalid
+	}
+
+	return claims, nil
+}